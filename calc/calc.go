@@ -189,6 +189,30 @@ func CalculateBoundingBox(p types.Polygon) BoundingBox {
 	}
 }
 
+// CalculatePlanarBoundingBox вычисляет ограничивающий прямоугольник полигона на плоскости
+// долгота/широта без учета 180-го меридиана. В отличие от CalculateBoundingBox, ребро с перепадом
+// долготы больше 180° (например, сторона прямоугольника от -180 до 180) не считается
+// пересечением меридиана, а долготы вне [-180, 180] сохраняются
+func CalculatePlanarBoundingBox(p types.Polygon) BoundingBox {
+	if len(p) == 0 || len(p[0]) == 0 {
+		return BoundingBox{}
+	}
+
+	box := BoundingBox{
+		MinLon: math.Inf(1), MinLat: math.Inf(1),
+		MaxLon: math.Inf(-1), MaxLat: math.Inf(-1),
+	}
+	for _, lineString := range p {
+		for _, point := range lineString {
+			box.MinLon = math.Min(box.MinLon, point.GetLongitude())
+			box.MaxLon = math.Max(box.MaxLon, point.GetLongitude())
+			box.MinLat = math.Min(box.MinLat, point.GetLatitude())
+			box.MaxLat = math.Max(box.MaxLat, point.GetLatitude())
+		}
+	}
+	return box
+}
+
 // CalculateLineStringLength вычисляет длину линии (в километрах)
 func CalculateLineStringLength(ls types.LineString) float64 {
 	if len(ls) < 2 {
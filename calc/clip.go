@@ -0,0 +1,442 @@
+package calc
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// ClipPolygon возвращает часть полигона внутри окна window на плоскости долгота/широта.
+// Окно - простое кольцо без самопересечений, необязательно выпуклое; направление его обхода
+// не важно, замыкающая точка необязательна. Полигон может быть невыпуклым и содержать дыры.
+// Части, разделенные границей окна, возвращаются отдельными полигонами. Внешние контуры
+// результата обходятся против часовой стрелки, дыры - по часовой.
+// Ребра полигона, совпадающие с границей окна, относятся к результату, только если
+// полигон прилегает к ним изнутри окна, поэтому соседние окна не получают вырожденных частей
+func ClipPolygon(polygon types.Polygon, window types.LineString) types.MultiPolygon {
+	win := newClipWindow(window)
+	if len(win.vertices) < 3 || len(polygon) == 0 {
+		return nil
+	}
+
+	var (
+		chains  []clipChain
+		inside  [][][2]float64
+		outside types.Polygon
+		// exteriorOutside - внешний контур не заходит в окно
+		exteriorOutside bool
+		// scale - длина меньшей из границ окна и внешнего контура; части с площадью
+		// меньше 1e-12·scale² считаются вырожденными
+		scale = win.perimeter
+	)
+	for i, ring := range polygon {
+		pts := openRing(ring)
+		if len(pts) < 3 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+
+		if i == 0 {
+			perimeter := 0.0
+			for k := range pts {
+				perimeter += pointDistance(pts[k], pts[(k+1)%len(pts)])
+			}
+			scale = math.Min(scale, perimeter)
+		}
+
+		// Внешний контур обходится против часовой стрелки, дыры - по часовой,
+		// так что внутренняя область полигона всегда слева от ребра
+		if (ringSignedArea(pts) > 0) != (i == 0) {
+			reversePoints(pts)
+		}
+
+		ringChains, whole := win.clipRing(pts)
+		if whole {
+			inside = append(inside, pts)
+			continue
+		}
+		if len(ringChains) == 0 {
+			outside = append(outside, ring)
+			exteriorOutside = exteriorOutside || i == 0
+		}
+		chains = append(chains, ringChains...)
+	}
+
+	rings := win.assemble(chains)
+	rings = append(rings, inside...)
+
+	// Если граница полигона не заходит в окно, окно целиком внутри полигона или вне его.
+	// Внутреннюю точку окна проверяем по кольцам вне окна: дыры внутри окна уже учтены выше
+	if len(chains) == 0 && exteriorOutside {
+		if p, ok := win.interiorPoint(); ok && PointInPolygon(outside, p) {
+			rings = append(rings, win.vertices)
+		}
+	}
+
+	minArea := 1e-12 * scale * scale
+	var exteriors, holes [][][2]float64
+	for _, ring := range rings {
+		area := ringSignedArea(ring)
+		switch {
+		case math.Abs(area) < minArea:
+		case area > 0:
+			exteriors = append(exteriors, ring)
+		default:
+			holes = append(holes, ring)
+		}
+	}
+
+	result := make(types.MultiPolygon, 0, len(exteriors))
+	for _, ext := range exteriors {
+		result = append(result, types.NewPolygon(closedRing(ext)))
+	}
+	for _, hole := range holes {
+		for i := range exteriors {
+			if pointInRing(result[i][0], types.NewPoint(hole[0][0], hole[0][1])) {
+				result[i] = append(result[i], closedRing(hole))
+				break
+			}
+		}
+	}
+	return result
+}
+
+// clipWindow - окно отсечения, обходимое против часовой стрелки
+type clipWindow struct {
+	vertices [][2]float64
+	ring     types.LineString
+	box      BoundingBox
+	// offsets - расстояние от первой вершины до каждой вершины вдоль границы
+	offsets   []float64
+	perimeter float64
+}
+
+// clipChain - часть кольца внутри окна от точки входа до точки выхода
+type clipChain struct {
+	points     [][2]float64
+	start, end float64
+}
+
+// newClipWindow подготавливает окно отсечения
+func newClipWindow(window types.LineString) clipWindow {
+	pts := openRing(window)
+	if len(pts) < 3 {
+		return clipWindow{}
+	}
+	if ringSignedArea(pts) < 0 {
+		reversePoints(pts)
+	}
+
+	w := clipWindow{vertices: pts, ring: closedRing(pts), offsets: make([]float64, len(pts))}
+	w.box = CalculatePlanarBoundingBox(types.Polygon{w.ring})
+	for i := range pts {
+		w.offsets[i] = w.perimeter
+		w.perimeter += pointDistance(pts[i], pts[(i+1)%len(pts)])
+	}
+	return w
+}
+
+// interiorPoint возвращает точку строго внутри окна: середину ребра, сдвинутую внутрь
+func (w *clipWindow) interiorPoint() (types.Point, bool) {
+	for i, v := range w.vertices {
+		next := w.vertices[(i+1)%len(w.vertices)]
+		length := pointDistance(v, next)
+		if length == 0 {
+			continue
+		}
+		shift := 1e-6 * length
+		p := types.NewPoint(
+			(v[0]+next[0])/2-(next[1]-v[1])/length*shift,
+			(v[1]+next[1])/2+(next[0]-v[0])/length*shift,
+		)
+		if pointInRing(w.ring, p) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// clipSegment возвращает видимые внутри окна части отрезка AB в виде интервалов параметра [t0, t1].
+// Части отрезка на границе окна видимы, только если направлены так же, как граница
+func (w *clipWindow) clipSegment(a, b [2]float64) [][2]float64 {
+	if math.Max(a[0], b[0]) < w.box.MinLon || math.Min(a[0], b[0]) > w.box.MaxLon ||
+		math.Max(a[1], b[1]) < w.box.MinLat || math.Min(a[1], b[1]) > w.box.MaxLat {
+		return nil
+	}
+
+	r := [2]float64{b[0] - a[0], b[1] - a[1]}
+	rr := r[0]*r[0] + r[1]*r[1]
+	if rr == 0 {
+		return nil
+	}
+
+	// along - участки отрезка, лежащие на ребрах окна, и совпадение направлений
+	type alongPart struct {
+		t0, t1 float64
+		same   bool
+	}
+	var along []alongPart
+	ts := []float64{0, 1}
+
+	for i, c := range w.vertices {
+		d := w.vertices[(i+1)%len(w.vertices)]
+		s := [2]float64{d[0] - c[0], d[1] - c[1]}
+		q := [2]float64{c[0] - a[0], c[1] - a[1]}
+
+		denom := r[0]*s[1] - r[1]*s[0]
+		if denom == 0 {
+			if q[0]*r[1]-q[1]*r[0] != 0 {
+				continue
+			}
+			// Ребро окна лежит на прямой отрезка
+			tc := (q[0]*r[0] + q[1]*r[1]) / rr
+			td := ((d[0]-a[0])*r[0] + (d[1]-a[1])*r[1]) / rr
+			lo, hi := math.Max(math.Min(tc, td), 0), math.Min(math.Max(tc, td), 1)
+			if lo < hi {
+				ts = append(ts, lo, hi)
+				along = append(along, alongPart{lo, hi, r[0]*s[0]+r[1]*s[1] > 0})
+			}
+			continue
+		}
+
+		t := (q[0]*s[1] - q[1]*s[0]) / denom
+		u := (q[0]*r[1] - q[1]*r[0]) / denom
+		if t > 0 && t < 1 && u >= 0 && u <= 1 {
+			ts = append(ts, t)
+		}
+	}
+	sort.Float64s(ts)
+
+	var result [][2]float64
+	for i := 0; i+1 < len(ts); i++ {
+		t0, t1 := ts[i], ts[i+1]
+		if t1 <= t0 {
+			continue
+		}
+
+		mid := (t0 + t1) / 2
+		visible, onEdge := false, false
+		for _, part := range along {
+			if mid > part.t0 && mid < part.t1 {
+				visible, onEdge = part.same, true
+				break
+			}
+		}
+		if !onEdge {
+			visible = pointInRing(w.ring, types.NewPoint(a[0]+mid*r[0], a[1]+mid*r[1]))
+		}
+		if !visible {
+			continue
+		}
+
+		// Соседние видимые интервалы объединяем
+		if n := len(result); n > 0 && result[n-1][1] == t0 {
+			result[n-1][1] = t1
+		} else {
+			result = append(result, [2]float64{t0, t1})
+		}
+	}
+	return result
+}
+
+// clipRing разбивает кольцо на части внутри окна. Возвращает true, если кольцо целиком внутри
+func (w *clipWindow) clipRing(ring [][2]float64) ([]clipChain, bool) {
+	n := len(ring)
+	var (
+		chains  []clipChain
+		current [][2]float64
+		open    bool
+	)
+
+	lerp := func(a, b [2]float64, t float64) [2]float64 {
+		switch t {
+		case 0:
+			return a
+		case 1:
+			return b
+		}
+		return [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}
+	}
+	closeChain := func() {
+		if open && len(current) >= 2 {
+			chains = append(chains, clipChain{points: current})
+		}
+		current, open = nil, false
+	}
+
+	allInside := true
+	for i := 0; i < n; i++ {
+		a, b := ring[i], ring[(i+1)%n]
+		parts := w.clipSegment(a, b)
+		if len(parts) != 1 || parts[0] != [2]float64{0, 1} {
+			allInside = false
+		}
+		if len(parts) == 0 {
+			closeChain()
+			continue
+		}
+
+		for _, part := range parts {
+			t0, t1 := part[0], part[1]
+			if !open || t0 > 0 {
+				closeChain()
+				current, open = [][2]float64{lerp(a, b, t0)}, true
+			}
+			current = append(current, lerp(a, b, t1))
+			if t1 < 1 {
+				closeChain()
+			}
+		}
+	}
+	if allInside {
+		return nil, true
+	}
+
+	// Часть, проходящая через первую вершину кольца, разрезана на две - склеиваем их
+	if open && len(chains) > 0 && chains[0].points[0] == ring[0] && current[len(current)-1] == ring[0] {
+		chains[0].points = append(current[:len(current)-1], chains[0].points...)
+		current, open = nil, false
+	}
+	closeChain()
+
+	for i := range chains {
+		pts := chains[i].points
+		chains[i].start = w.position(pts[0])
+		chains[i].end = w.position(pts[len(pts)-1])
+	}
+	return chains, false
+}
+
+// position возвращает положение точки на границе окна как расстояние от первой вершины
+// вдоль границы. Точка проецируется на ближайшее ребро
+func (w *clipWindow) position(p [2]float64) float64 {
+	best, pos := math.Inf(1), 0.0
+	for i, v := range w.vertices {
+		next := w.vertices[(i+1)%len(w.vertices)]
+		e := [2]float64{next[0] - v[0], next[1] - v[1]}
+		length2 := e[0]*e[0] + e[1]*e[1]
+		if length2 == 0 {
+			continue
+		}
+		t := ((p[0]-v[0])*e[0] + (p[1]-v[1])*e[1]) / length2
+		t = math.Max(0, math.Min(1, t))
+		dist := pointDistance(p, [2]float64{v[0] + t*e[0], v[1] + t*e[1]})
+		if dist < best {
+			best, pos = dist, w.offsets[i]+t*math.Sqrt(length2)
+		}
+	}
+	if pos >= w.perimeter {
+		pos -= w.perimeter
+	}
+	return pos
+}
+
+// assemble соединяет части колец в замкнутые контуры, обходя границу окна против часовой
+// стрелки от точки выхода до ближайшей точки входа
+func (w *clipWindow) assemble(chains []clipChain) [][][2]float64 {
+	// forward - расстояние вдоль границы окна от from до to против часовой стрелки
+	forward := func(from, to float64) float64 {
+		d := to - from
+		if d < 0 {
+			d += w.perimeter
+		}
+		return d
+	}
+
+	used := make([]bool, len(chains))
+	var rings [][][2]float64
+	for first := range chains {
+		if used[first] {
+			continue
+		}
+		used[first] = true
+
+		ring := append([][2]float64(nil), chains[first].points...)
+		cur := first
+		for {
+			// Ближайшая точка входа после точки выхода текущей части
+			next, best := -1, math.Inf(1)
+			for i, c := range chains {
+				if used[i] && i != first {
+					continue
+				}
+				if d := forward(chains[cur].end, c.start); d < best {
+					next, best = i, d
+				}
+			}
+			if next < 0 {
+				break
+			}
+
+			// Вершины окна между точкой выхода и точкой входа
+			end := chains[cur].end
+			v := sort.SearchFloat64s(w.offsets, end)
+			for k := 0; k < len(w.vertices); k++ {
+				idx := (v + k) % len(w.vertices)
+				d := forward(end, w.offsets[idx])
+				if d >= best {
+					break
+				}
+				if d > 0 {
+					ring = append(ring, w.vertices[idx])
+				}
+			}
+
+			if next == first {
+				break
+			}
+			used[next] = true
+			ring = append(ring, chains[next].points...)
+			cur = next
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// openRing переводит кольцо в срез координат без замыкающей точки и повторяющихся подряд точек
+func openRing(ring types.LineString) [][2]float64 {
+	pts := make([][2]float64, 0, len(ring))
+	for _, p := range ring {
+		q := [2]float64{p.GetLongitude(), p.GetLatitude()}
+		if len(pts) > 0 && pts[len(pts)-1] == q {
+			continue
+		}
+		pts = append(pts, q)
+	}
+	if len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+	return pts
+}
+
+// closedRing переводит срез координат в замкнутое кольцо
+func closedRing(pts [][2]float64) types.LineString {
+	ring := make(types.LineString, 0, len(pts)+1)
+	for _, p := range pts {
+		ring = append(ring, types.NewPoint(p[0], p[1]))
+	}
+	return append(ring, types.NewPoint(pts[0][0], pts[0][1]))
+}
+
+// ringSignedArea вычисляет ориентированную площадь кольца без замыкающей точки
+func ringSignedArea(pts [][2]float64) float64 {
+	area := 0.0
+	for i, j := 0, len(pts)-1; i < len(pts); j, i = i, i+1 {
+		area += pts[j][0]*pts[i][1] - pts[i][0]*pts[j][1]
+	}
+	return area / 2
+}
+
+func reversePoints(pts [][2]float64) {
+	for a, b := 0, len(pts)-1; a < b; a, b = a+1, b-1 {
+		pts[a], pts[b] = pts[b], pts[a]
+	}
+}
+
+func pointDistance(a, b [2]float64) float64 {
+	return math.Hypot(a[0]-b[0], a[1]-b[1])
+}
@@ -0,0 +1,65 @@
+package calc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// planarArea возвращает площадь набора полигонов на плоскости долгота/широта
+func planarArea(mp types.MultiPolygon) float64 {
+	total := 0.0
+	for _, polygon := range mp {
+		for i, ring := range polygon {
+			area := math.Abs(ringSignedArea(openRing(ring)))
+			if i > 0 {
+				area = -area
+			}
+			total += area
+		}
+	}
+	return total
+}
+
+func TestClipPolygonByWorldWindow(t *testing.T) {
+	world := types.NewLineString(
+		types.NewPoint(-180, -90), types.NewPoint(180, -90), types.NewPoint(180, 90),
+		types.NewPoint(-180, 90), types.NewPoint(-180, -90),
+	)
+	for _, lon := range []float64{-180, -5, 170} {
+		square := types.NewPolygon(types.NewLineString(
+			types.NewPoint(lon, 0), types.NewPoint(lon+10, 0), types.NewPoint(lon+10, 10),
+			types.NewPoint(lon, 10), types.NewPoint(lon, 0),
+		))
+		if got := planarArea(ClipPolygon(square, world)); math.Abs(got-100) > 1e-9 {
+			t.Errorf("square at %g clipped by the world: area %g, want 100", lon, got)
+		}
+	}
+}
+
+func TestClipPolygonKeepsSmallPartsOfLargeWindow(t *testing.T) {
+	// Окно во много раз больше полигона: малая по меркам окна часть не считается вырожденной
+	window := types.NewLineString(
+		types.NewPoint(-100, -80), types.NewPoint(37.5, -80), types.NewPoint(37.5, 55.70001),
+		types.NewPoint(-100, 55.70001), types.NewPoint(-100, -80),
+	)
+	polygon := types.NewPolygon(types.NewLineString(
+		types.NewPoint(37.49, 55.7), types.NewPoint(37.51, 55.7), types.NewPoint(37.51, 55.71),
+		types.NewPoint(37.49, 55.71), types.NewPoint(37.49, 55.7),
+	))
+	want := 0.01 * 0.00001
+	if got := planarArea(ClipPolygon(polygon, window)); math.Abs(got-want) > 1e-11 {
+		t.Errorf("clipped area %g, want %g", got, want)
+	}
+}
+
+func TestCalculatePlanarBoundingBox(t *testing.T) {
+	box := CalculatePlanarBoundingBox(types.NewPolygon(types.NewLineString(
+		types.NewPoint(-180, -10), types.NewPoint(180, -10), types.NewPoint(180, 10),
+		types.NewPoint(-180, 10), types.NewPoint(-180, -10),
+	)))
+	if box != (BoundingBox{MinLon: -180, MinLat: -10, MaxLon: 180, MaxLat: 10}) {
+		t.Errorf("box = %+v", box)
+	}
+}
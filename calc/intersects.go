@@ -0,0 +1,161 @@
+package calc
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// PointInMultiPolygon проверяет, находится ли точка внутри хотя бы одного полигона из набора
+func PointInMultiPolygon(multiPolygon types.MultiPolygon, point types.Point) bool {
+	for _, polygon := range multiPolygon {
+		if PointInPolygon(polygon, point) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolygonIntersectsLineString проверяет, имеет ли линия общие точки с полигоном.
+// Вычисления выполняются на плоскости долгота/широта
+func PolygonIntersectsLineString(polygon types.Polygon, ls types.LineString) bool {
+	rings := polygonRings(polygon)
+	if len(rings) == 0 || len(ls) == 0 {
+		return false
+	}
+
+	// Пересечение любого отрезка линии с любым ребром полигона
+	for i := 0; i < len(ls)-1; i++ {
+		for _, ring := range rings {
+			if ringIntersectsSegment(ring, ls[i], ls[i+1]) {
+				return true
+			}
+		}
+	}
+
+	// Иначе линия либо целиком внутри полигона, либо целиком снаружи
+	return PointInPolygon(polygon, ls[0])
+}
+
+// PolygonsIntersect проверяет, имеют ли два полигона общие точки.
+// Вычисления выполняются на плоскости долгота/широта
+func PolygonsIntersect(a, b types.Polygon) bool {
+	ringsA := polygonRings(a)
+	ringsB := polygonRings(b)
+	if len(ringsA) == 0 || len(ringsB) == 0 {
+		return false
+	}
+
+	for _, ringB := range ringsB {
+		for i, j := 0, len(ringB)-1; i < len(ringB); j, i = i, i+1 {
+			for _, ringA := range ringsA {
+				if ringIntersectsSegment(ringA, ringB[j], ringB[i]) {
+					return true
+				}
+			}
+		}
+	}
+
+	// Границы не пересекаются: один полигон либо содержит другой, либо они не пересекаются
+	return PointInPolygon(a, b[0][0]) || PointInPolygon(b, a[0][0])
+}
+
+// PolygonContainsPolygon проверяет, что полигон b целиком лежит внутри полигона a.
+// Касание границ не считается вхождением. Вычисления выполняются на плоскости долгота/широта
+func PolygonContainsPolygon(a, b types.Polygon) bool {
+	ringsA := polygonRings(a)
+	ringsB := polygonRings(b)
+	if len(ringsA) == 0 || len(ringsB) == 0 {
+		return false
+	}
+
+	// Границы не должны иметь общих точек
+	for _, ringB := range ringsB {
+		for i, j := 0, len(ringB)-1; i < len(ringB); j, i = i, i+1 {
+			for _, ringA := range ringsA {
+				if ringIntersectsSegment(ringA, ringB[j], ringB[i]) {
+					return false
+				}
+			}
+		}
+	}
+
+	// Полигон b внутри a, и ни одна вершина a (в том числе дыр) не попадает внутрь b
+	if !PointInPolygon(a, b[0][0]) {
+		return false
+	}
+	for _, ringA := range ringsA {
+		for _, p := range ringA {
+			if PointInPolygon(b, p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// polygonRings возвращает кольца полигона, учитываемые при проверках:
+// внешний контур и дыры, содержащие не менее трех точек
+func polygonRings(polygon types.Polygon) []types.LineString {
+	if len(polygon) == 0 || len(polygon[0]) < 3 {
+		return nil
+	}
+
+	rings := make([]types.LineString, 0, len(polygon))
+	rings = append(rings, polygon[0])
+	for i := 1; i < len(polygon); i++ {
+		if len(polygon[i]) >= 3 {
+			rings = append(rings, polygon[i])
+		}
+	}
+	return rings
+}
+
+// ringIntersectsSegment проверяет пересечение отрезка с ребрами кольца
+func ringIntersectsSegment(ring types.LineString, a, b types.Point) bool {
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if segmentsIntersect(
+			a.GetLongitude(), a.GetLatitude(), b.GetLongitude(), b.GetLatitude(),
+			ring[j].GetLongitude(), ring[j].GetLatitude(), ring[i].GetLongitude(), ring[i].GetLatitude(),
+		) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentsIntersect проверяет, имеют ли отрезки AB и CD общие точки, включая касание и наложение
+func segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	// Отрезки с непересекающимися габаритами не могут иметь общих точек
+	if math.Max(ax, bx) < math.Min(cx, dx) || math.Max(cx, dx) < math.Min(ax, bx) ||
+		math.Max(ay, by) < math.Min(cy, dy) || math.Max(cy, dy) < math.Min(ay, by) {
+		return false
+	}
+
+	d1 := cross(cx, cy, dx, dy, ax, ay)
+	d2 := cross(cx, cy, dx, dy, bx, by)
+	d3 := cross(ax, ay, bx, by, cx, cy)
+	d4 := cross(ax, ay, bx, by, dx, dy)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	// Случаи касания: точка лежит на другом отрезке
+	return (d1 == 0 && onSegment(cx, cy, dx, dy, ax, ay)) ||
+		(d2 == 0 && onSegment(cx, cy, dx, dy, bx, by)) ||
+		(d3 == 0 && onSegment(ax, ay, bx, by, cx, cy)) ||
+		(d4 == 0 && onSegment(ax, ay, bx, by, dx, dy))
+}
+
+// cross вычисляет векторное произведение (B - A) x (P - A)
+func cross(ax, ay, bx, by, px, py float64) float64 {
+	return (bx-ax)*(py-ay) - (by-ay)*(px-ax)
+}
+
+// onSegment проверяет, что точка P, лежащая на прямой AB, находится в пределах отрезка AB
+func onSegment(ax, ay, bx, by, px, py float64) bool {
+	return px >= math.Min(ax, bx) && px <= math.Max(ax, bx) &&
+		py >= math.Min(ay, by) && py <= math.Max(ay, by)
+}
@@ -0,0 +1,288 @@
+package calc
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// maxPreparedBands ограничивает количество полос индекса ребер одного кольца
+const maxPreparedBands = 1 << 14
+
+// PreparedPolygon - полигон или набор полигонов с предварительно построенным индексом ребер.
+// Используется для многократных проверок вхождения и пересечения с одной и той же областью.
+// Результаты совпадают с PointInPolygon, PolygonIntersectsLineString, PolygonsIntersect
+// и PolygonContainsPolygon
+type PreparedPolygon struct {
+	parts []preparedPart
+}
+
+// preparedPart - подготовленный полигон: внешний контур и дыры
+type preparedPart struct {
+	source types.Polygon
+	rings  []preparedRing
+	// vertices - вершины всех колец, отсортированные по широте
+	vertices []types.Point
+}
+
+// preparedRing - кольцо с индексом ребер по полосам широты
+type preparedRing struct {
+	// edges хранит ребра в виде (xj, yj, xi, yi) в порядке обхода pointInRing
+	edges      [][4]float64
+	minLat     float64
+	maxLat     float64
+	bandHeight float64
+	bands      [][]int32
+}
+
+// NewPreparedPolygon подготавливает полигон для быстрых проверок
+func NewPreparedPolygon(polygon types.Polygon) *PreparedPolygon {
+	pp := &PreparedPolygon{}
+	if part, ok := preparePolygon(polygon); ok {
+		pp.parts = append(pp.parts, part)
+	}
+	return pp
+}
+
+// NewPreparedMultiPolygon подготавливает набор полигонов для быстрых проверок
+func NewPreparedMultiPolygon(multiPolygon types.MultiPolygon) *PreparedPolygon {
+	pp := &PreparedPolygon{parts: make([]preparedPart, 0, len(multiPolygon))}
+	for _, polygon := range multiPolygon {
+		if part, ok := preparePolygon(polygon); ok {
+			pp.parts = append(pp.parts, part)
+		}
+	}
+	return pp
+}
+
+// ContainsPoint проверяет, находится ли точка внутри области
+func (pp *PreparedPolygon) ContainsPoint(point types.Point) bool {
+	for i := range pp.parts {
+		if pp.parts[i].containsPoint(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// IntersectsLineString проверяет, имеет ли линия общие точки с областью
+func (pp *PreparedPolygon) IntersectsLineString(ls types.LineString) bool {
+	if len(ls) == 0 {
+		return false
+	}
+
+	for i := range pp.parts {
+		part := &pp.parts[i]
+
+		for s := 0; s < len(ls)-1; s++ {
+			for r := range part.rings {
+				if part.rings[r].intersectsSegment(ls[s], ls[s+1]) {
+					return true
+				}
+			}
+		}
+
+		if part.containsPoint(ls[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// IntersectsPolygon проверяет, имеет ли полигон общие точки с областью
+func (pp *PreparedPolygon) IntersectsPolygon(polygon types.Polygon) bool {
+	other := polygonRings(polygon)
+	if len(other) == 0 {
+		return false
+	}
+
+	for i := range pp.parts {
+		part := &pp.parts[i]
+
+		for _, ring := range other {
+			for a, b := 0, len(ring)-1; a < len(ring); b, a = a, a+1 {
+				for r := range part.rings {
+					if part.rings[r].intersectsSegment(ring[b], ring[a]) {
+						return true
+					}
+				}
+			}
+		}
+
+		if part.containsPoint(polygon[0][0]) || PointInPolygon(polygon, part.source[0][0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsPolygon проверяет, что полигон целиком лежит внутри одного из полигонов области.
+// Результат совпадает с PolygonContainsPolygon
+func (pp *PreparedPolygon) ContainsPolygon(polygon types.Polygon) bool {
+	other := polygonRings(polygon)
+	if len(other) == 0 {
+		return false
+	}
+
+	// Диапазон широт проверяемого полигона для отбора вершин области
+	minLat, maxLat := math.Inf(1), math.Inf(-1)
+	for _, p := range polygon[0] {
+		minLat = math.Min(minLat, p.GetLatitude())
+		maxLat = math.Max(maxLat, p.GetLatitude())
+	}
+
+	for i := range pp.parts {
+		if pp.parts[i].containsPolygon(polygon, other, minLat, maxLat) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsPolygon проверяет вхождение полигона в подготовленный полигон
+func (part *preparedPart) containsPolygon(polygon types.Polygon, rings []types.LineString, minLat, maxLat float64) bool {
+	for _, ring := range rings {
+		for a, b := 0, len(ring)-1; a < len(ring); b, a = a, a+1 {
+			for r := range part.rings {
+				if part.rings[r].intersectsSegment(ring[b], ring[a]) {
+					return false
+				}
+			}
+		}
+	}
+
+	if !part.containsPoint(polygon[0][0]) {
+		return false
+	}
+
+	// Вершины вне диапазона широт внешнего контура не могут лежать внутри полигона
+	start := sort.Search(len(part.vertices), func(i int) bool {
+		return part.vertices[i].GetLatitude() >= minLat
+	})
+	for i := start; i < len(part.vertices) && part.vertices[i].GetLatitude() <= maxLat; i++ {
+		if PointInPolygon(polygon, part.vertices[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// preparePolygon строит индекс ребер для всех учитываемых колец полигона
+func preparePolygon(polygon types.Polygon) (preparedPart, bool) {
+	rings := polygonRings(polygon)
+	if len(rings) == 0 {
+		return preparedPart{}, false
+	}
+
+	part := preparedPart{source: polygon, rings: make([]preparedRing, len(rings))}
+	for i, ring := range rings {
+		part.rings[i] = prepareRing(ring)
+		part.vertices = append(part.vertices, ring...)
+	}
+	sort.Slice(part.vertices, func(i, j int) bool {
+		return part.vertices[i].GetLatitude() < part.vertices[j].GetLatitude()
+	})
+	return part, true
+}
+
+// prepareRing распределяет ребра кольца по горизонтальным полосам
+func prepareRing(ring types.LineString) preparedRing {
+	n := len(ring)
+	pr := preparedRing{
+		edges:  make([][4]float64, 0, n),
+		minLat: math.Inf(1),
+		maxLat: math.Inf(-1),
+	}
+
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		pr.edges = append(pr.edges, [4]float64{
+			ring[j].GetLongitude(), ring[j].GetLatitude(),
+			ring[i].GetLongitude(), ring[i].GetLatitude(),
+		})
+		pr.minLat = math.Min(pr.minLat, ring[i].GetLatitude())
+		pr.maxLat = math.Max(pr.maxLat, ring[i].GetLatitude())
+	}
+
+	bandCount := min(max(n/2, 1), maxPreparedBands)
+	pr.bandHeight = (pr.maxLat - pr.minLat) / float64(bandCount)
+	pr.bands = make([][]int32, bandCount)
+
+	for idx, e := range pr.edges {
+		from := pr.band(math.Min(e[1], e[3]))
+		to := pr.band(math.Max(e[1], e[3]))
+		for b := from; b <= to; b++ {
+			pr.bands[b] = append(pr.bands[b], int32(idx))
+		}
+	}
+
+	return pr
+}
+
+// band возвращает номер полосы для широты. Функция монотонна по lat
+func (pr *preparedRing) band(lat float64) int {
+	if pr.bandHeight <= 0 {
+		return 0
+	}
+	b := int((lat - pr.minLat) / pr.bandHeight)
+	return max(0, min(b, len(pr.bands)-1))
+}
+
+// containsPoint повторяет проверку PointInPolygon, используя индекс колец
+func (part *preparedPart) containsPoint(point types.Point) bool {
+	if !part.rings[0].containsPoint(point) {
+		return false
+	}
+	for i := 1; i < len(part.rings); i++ {
+		if part.rings[i].containsPoint(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsPoint выполняет ray casting только по ребрам полосы, содержащей точку.
+// Ребро может пересечь горизонтальный луч, только если его диапазон широт содержит широту точки
+func (pr *preparedRing) containsPoint(point types.Point) bool {
+	x := point.GetLongitude()
+	y := point.GetLatitude()
+	if y < pr.minLat || y > pr.maxLat {
+		return false
+	}
+
+	inside := false
+	for _, idx := range pr.bands[pr.band(y)] {
+		e := pr.edges[idx]
+		xj, yj, xi, yi := e[0], e[1], e[2], e[3]
+
+		intersect := ((yi > y) != (yj > y)) &&
+			(x < (xj-xi)*(y-yi)/(yj-yi)+xi)
+
+		if intersect {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// intersectsSegment проверяет пересечение отрезка AB с ребрами из полос, покрывающих его диапазон широт
+func (pr *preparedRing) intersectsSegment(a, b types.Point) bool {
+	ax, ay := a.GetLongitude(), a.GetLatitude()
+	bx, by := b.GetLongitude(), b.GetLatitude()
+
+	lo, hi := math.Min(ay, by), math.Max(ay, by)
+	if hi < pr.minLat || lo > pr.maxLat {
+		return false
+	}
+
+	for band := pr.band(lo); band <= pr.band(hi); band++ {
+		for _, idx := range pr.bands[band] {
+			e := pr.edges[idx]
+			if segmentsIntersect(ax, ay, bx, by, e[0], e[1], e[2], e[3]) {
+				return true
+			}
+		}
+	}
+	return false
+}
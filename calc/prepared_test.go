@@ -0,0 +1,92 @@
+package calc
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// starPolygon возвращает невыпуклый многоугольник со случайными лучами вокруг центра
+func starPolygon(r *rand.Rand, lon, lat, radius float64, n int) types.LineString {
+	ring := make(types.LineString, 0, n+1)
+	for i := 0; i < n; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		d := radius * (0.3 + 0.7*r.Float64())
+		ring = append(ring, types.NewPoint(lon+d*math.Cos(angle), lat+d*math.Sin(angle)))
+	}
+	return append(ring, ring[0])
+}
+
+// square возвращает квадрат со стороной size и юго-западным углом в (lon, lat)
+func square(lon, lat, size float64) types.Polygon {
+	return types.NewPolygon(types.NewLineString(
+		types.NewPoint(lon, lat), types.NewPoint(lon+size, lat), types.NewPoint(lon+size, lat+size),
+		types.NewPoint(lon, lat+size), types.NewPoint(lon, lat),
+	))
+}
+
+func TestPreparedPolygonMatchesPlainChecks(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	hole := starPolygon(r, 37.5, 55.5, 0.3, 12)
+	area := types.MultiPolygon{
+		types.NewPolygon(starPolygon(r, 37.5, 55.5, 1, 200), hole),
+		types.NewPolygon(starPolygon(r, 40, 55.5, 0.5, 30)),
+	}
+	prepared := NewPreparedMultiPolygon(area)
+
+	random := func() types.Point {
+		return types.NewPoint(36+r.Float64()*5, 54+r.Float64()*3)
+	}
+	for i := 0; i < 2000; i++ {
+		p := random()
+		if got, want := prepared.ContainsPoint(p), PointInMultiPolygon(area, p); got != want {
+			t.Fatalf("ContainsPoint(%v) = %v, want %v", p, got, want)
+		}
+
+		line := types.NewLineString(p, random())
+		want := false
+		for _, polygon := range area {
+			want = want || PolygonIntersectsLineString(polygon, line)
+		}
+		if got := prepared.IntersectsLineString(line); got != want {
+			t.Fatalf("IntersectsLineString(%v) = %v, want %v", line, got, want)
+		}
+
+		cell := square(p.GetLongitude(), p.GetLatitude(), r.Float64()*0.3)
+		wantIntersects, wantContains := false, false
+		for _, polygon := range area {
+			wantIntersects = wantIntersects || PolygonsIntersect(polygon, cell)
+			wantContains = wantContains || PolygonContainsPolygon(polygon, cell)
+		}
+		if got := prepared.IntersectsPolygon(cell); got != wantIntersects {
+			t.Fatalf("IntersectsPolygon(%v) = %v, want %v", cell, got, wantIntersects)
+		}
+		if got := prepared.ContainsPolygon(cell); got != wantContains {
+			t.Fatalf("ContainsPolygon(%v) = %v, want %v", cell, got, wantContains)
+		}
+	}
+}
+
+func TestPreparedPolygonHole(t *testing.T) {
+	outer := square(0, 0, 10)[0]
+	prepared := NewPreparedPolygon(types.NewPolygon(outer, square(4, 4, 2)[0]))
+
+	if !prepared.ContainsPoint(types.NewPoint(1, 1)) {
+		t.Error("point inside the exterior is not contained")
+	}
+	if prepared.ContainsPoint(types.NewPoint(5, 5)) {
+		t.Error("point inside the hole is contained")
+	}
+	if prepared.ContainsPolygon(square(3, 3, 4)) {
+		t.Error("polygon around the hole is contained")
+	}
+	inHole := square(4.5, 4.5, 1)
+	if got, want := prepared.IntersectsPolygon(inHole), PolygonsIntersect(types.NewPolygon(outer, square(4, 4, 2)[0]), inHole); got != want {
+		t.Errorf("IntersectsPolygon for a polygon inside the hole = %v, want %v", got, want)
+	}
+	if NewPreparedPolygon(nil).ContainsPoint(types.NewPoint(0, 0)) {
+		t.Error("empty polygon contains a point")
+	}
+}
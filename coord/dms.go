@@ -0,0 +1,484 @@
+package coord
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Hemisphere обозначает полушарие координаты
+type Hemisphere byte
+
+const (
+	North Hemisphere = 'N'
+	South Hemisphere = 'S'
+	East  Hemisphere = 'E'
+	West  Hemisphere = 'W'
+)
+
+// isLatitude проверяет, что полушарие относится к широте
+func (h Hemisphere) isLatitude() bool {
+	return h == North || h == South
+}
+
+// sign возвращает знак координаты для полушария
+func (h Hemisphere) sign() float64 {
+	if h == South || h == West {
+		return -1
+	}
+	return 1
+}
+
+// hemisphereWords - обозначения полушарий на поддерживаемых языках (в нижнем регистре, без точек и пробелов).
+// Поддерживаются английские буквы и слова, русские (с.ш., ю.ш., в.д., з.д.)
+// и украинские (пн.ш., пд.ш., сх.д., зх.д.) сокращения
+var hemisphereWords = map[string]Hemisphere{
+	"n": North, "north": North,
+	"s": South, "south": South,
+	"e": East, "east": East,
+	"w": West, "west": West,
+
+	"с": North, "сш": North, "север": North,
+	"ю": South, "юш": South, "юг": South,
+	"в": East, "вд": East, "восток": East,
+	"з": West, "зд": West, "запад": West,
+
+	"пн": North, "пнш": North,
+	"пд": South, "пдш": South,
+	"сх": East, "схд": East,
+	"зх": West, "зхд": West,
+}
+
+// dmsToken - лексема координатной строки
+type dmsToken struct {
+	kind       byte // 'n' - число, 'h' - полушарие, 'd' - градусы, 'm' - минуты, 's' - секунды
+	text       string
+	hemisphere Hemisphere
+}
+
+// dmsComponent - одна координата: до трех чисел и необязательное полушарие
+type dmsComponent struct {
+	numbers    []string
+	units      []byte
+	hemisphere Hemisphere
+}
+
+// Регулярные выражения для нормализации и разбора
+var (
+	decimalCommaRe = regexp.MustCompile(`(\d),(\d)`)
+	dmsNumberRe    = regexp.MustCompile(`^[+\-−]?\d+(?:\.\d+)?`)
+)
+
+// ParseOptions задает параметры разбора координат
+type ParseOptions struct {
+	// LonFirst - координаты без обозначения полушарий записаны в порядке "долгота, широта".
+	// По умолчанию используется порядок "широта, долгота"
+	LonFirst bool
+}
+
+// ParsePoint разбирает строку с координатами в форматах DMS, DM или десятичных градусов,
+// например `55°45'21"N 37°37'03"E`, `N55 45.35 E37 37.05`, `55°45′21″ с.ш. 37°37′03″ в.д.` или `55.7558, 37.6173`.
+// Координаты без обозначения полушарий считаются записанными в порядке "широта, долгота"
+func ParsePoint(s string) (types.Point, error) {
+	return ParsePointWithOptions(s, ParseOptions{})
+}
+
+// ParsePointWithOptions разбирает строку с координатами с указанными параметрами
+func ParsePointWithOptions(s string, opts ParseOptions) (types.Point, error) {
+	tokens, err := tokenizeDMS(normalizeDMS(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinates %q: %w", s, err)
+	}
+
+	components, err := groupDMS(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinates %q: %w", s, err)
+	}
+	if len(components) != 2 {
+		return nil, fmt.Errorf("invalid coordinates %q: expected 2 coordinates, got %d", s, len(components))
+	}
+
+	var lat, lon float64
+	var hasLat, hasLon bool
+	for i, c := range components {
+		value, err := c.value()
+		if err != nil {
+			return nil, fmt.Errorf("invalid coordinates %q: %w", s, err)
+		}
+
+		isLat := (i == 0) != opts.LonFirst
+		if c.hemisphere != 0 {
+			isLat = c.hemisphere.isLatitude()
+		} else if other := components[1-i].hemisphere; other != 0 {
+			isLat = !other.isLatitude()
+		}
+
+		if isLat {
+			if hasLat {
+				return nil, fmt.Errorf("invalid coordinates %q: latitude is specified twice", s)
+			}
+			lat, hasLat = value, true
+		} else {
+			if hasLon {
+				return nil, fmt.Errorf("invalid coordinates %q: longitude is specified twice", s)
+			}
+			lon, hasLon = value, true
+		}
+	}
+
+	if lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("invalid coordinates %q: latitude %v is out of range [-90, 90]", s, lat)
+	}
+	if lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("invalid coordinates %q: longitude %v is out of range [-180, 180]", s, lon)
+	}
+
+	return types.NewPoint(lon, lat), nil
+}
+
+// normalizeDMS приводит строку к нижнему регистру и заменяет типографские символы на ASCII
+func normalizeDMS(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(
+		"º", "°", "˚", "°", "ᵒ", "°",
+		"′", "'", "’", "'", "‘", "'", "´", "'", "`", "'",
+		"″", `"`, "”", `"`, "“", `"`, "''", `"`,
+		"−", "-", "–", "-",
+	).Replace(s)
+
+	// Десятичная запятая допускается, если в строке нет точек и координаты разделены иначе
+	if !strings.Contains(s, ".") && decimalCommaRe.MatchString(s) {
+		rest := decimalCommaRe.ReplaceAllString(s, "$1$2")
+		if strings.ContainsAny(rest, " ;\t") || strings.Contains(rest, ",") {
+			s = decimalCommaRe.ReplaceAllString(s, "$1.$2")
+		}
+	}
+
+	return s
+}
+
+// tokenizeDMS разбивает нормализованную строку на лексемы
+func tokenizeDMS(s string) ([]dmsToken, error) {
+	var tokens []dmsToken
+
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case r == ' ' || r == '\t' || r == ',' || r == ';' || r == '/':
+			i++
+		case strings.HasPrefix(s[i:], "°"):
+			tokens = append(tokens, dmsToken{kind: 'd'})
+			i += len("°")
+		case r == '\'':
+			tokens = append(tokens, dmsToken{kind: 'm'})
+			i++
+		case r == '"':
+			tokens = append(tokens, dmsToken{kind: 's'})
+			i++
+		case r == '+' || r == '-' || (r >= '0' && r <= '9'):
+			num := dmsNumberRe.FindString(s[i:])
+			if num == "" {
+				return nil, fmt.Errorf("unexpected character %q", s[i])
+			}
+			tokens = append(tokens, dmsToken{kind: 'n', text: num})
+			i += len(num)
+		default:
+			// Слово из букв и точек - обозначение полушария
+			j := i
+			for j < len(s) {
+				rr, size := decodeRune(s[j:])
+				if !unicode.IsLetter(rr) && rr != '.' {
+					break
+				}
+				j += size
+			}
+			if j == i {
+				rr, _ := decodeRune(s[i:])
+				return nil, fmt.Errorf("unexpected character %q", rr)
+			}
+
+			word := strings.ReplaceAll(s[i:j], ".", "")
+			h, ok := hemisphereWords[word]
+			if !ok {
+				return nil, fmt.Errorf("unknown hemisphere designator %q", s[i:j])
+			}
+			tokens = append(tokens, dmsToken{kind: 'h', hemisphere: h})
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+// decodeRune декодирует первый символ строки
+func decodeRune(s string) (rune, int) {
+	for _, r := range s {
+		return r, len(string(r))
+	}
+	return 0, 0
+}
+
+// groupDMS группирует лексемы в координаты. Обозначения полушарий могут стоять
+// перед числами или после них; без обозначений границы определяются по символам градусов
+// или, при их отсутствии, числа делятся поровну
+func groupDMS(tokens []dmsToken) ([]dmsComponent, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no coordinates found")
+	}
+
+	prefix := tokens[0].kind == 'h'
+	hasMarkers := false
+	for _, t := range tokens {
+		if t.kind != 'n' {
+			hasMarkers = true
+			break
+		}
+	}
+
+	// Только числа: делим поровну между широтой и долготой
+	if !hasMarkers {
+		if len(tokens)%2 != 0 || len(tokens) > 6 {
+			return nil, fmt.Errorf("cannot split %d numbers into latitude and longitude", len(tokens))
+		}
+		half := len(tokens) / 2
+		var components []dmsComponent
+		for _, part := range [][]dmsToken{tokens[:half], tokens[half:]} {
+			c := dmsComponent{}
+			for _, t := range part {
+				c.numbers = append(c.numbers, t.text)
+				c.units = append(c.units, 0)
+			}
+			components = append(components, c)
+		}
+		return components, nil
+	}
+
+	var components []dmsComponent
+	var current *dmsComponent
+	start := func() {
+		components = append(components, dmsComponent{})
+		current = &components[len(components)-1]
+	}
+
+	for i, t := range tokens {
+		switch t.kind {
+		case 'h':
+			if prefix {
+				start()
+				current.hemisphere = t.hemisphere
+				continue
+			}
+			if current == nil || current.hemisphere != 0 || len(current.numbers) == 0 {
+				return nil, fmt.Errorf("unexpected hemisphere designator")
+			}
+			current.hemisphere = t.hemisphere
+			current = nil
+		case 'n':
+			// Новая координата начинается, если текущая завершена или следующее число помечено как градусы
+			degreesNext := i+1 < len(tokens) && tokens[i+1].kind == 'd'
+			if current == nil || (!prefix && degreesNext && len(current.numbers) > 0) {
+				if prefix && current == nil {
+					return nil, fmt.Errorf("number before hemisphere designator")
+				}
+				start()
+			}
+			if len(current.numbers) == 3 {
+				return nil, fmt.Errorf("too many numbers in coordinate")
+			}
+			current.numbers = append(current.numbers, t.text)
+			current.units = append(current.units, 0)
+		default:
+			if current == nil || len(current.numbers) == 0 || current.units[len(current.units)-1] != 0 {
+				return nil, fmt.Errorf("unexpected unit symbol")
+			}
+			current.units[len(current.units)-1] = t.kind
+		}
+	}
+
+	return components, nil
+}
+
+// value вычисляет значение координаты в десятичных градусах
+func (c dmsComponent) value() (float64, error) {
+	if len(c.numbers) == 0 {
+		return 0, fmt.Errorf("missing degrees")
+	}
+
+	// Единицы измерения: явные символы должны идти по порядку градусы, минуты, секунды
+	expected := []byte{'d', 'm', 's'}
+	for i, u := range c.units {
+		if u != 0 && u != expected[i] {
+			return 0, fmt.Errorf("unexpected unit order")
+		}
+	}
+
+	negative := false
+	var parts [3]float64
+	for i, text := range c.numbers {
+		if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+			if i > 0 {
+				return 0, fmt.Errorf("only degrees may have a sign")
+			}
+			negative = text[0] == '-'
+			text = text[1:]
+		}
+
+		if strings.Contains(text, ".") && i < len(c.numbers)-1 {
+			return 0, fmt.Errorf("only the last component may have a fractional part")
+		}
+
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", text)
+		}
+		parts[i] = v
+	}
+
+	if parts[1] >= 60 {
+		return 0, fmt.Errorf("minutes must be less than 60, got %v", parts[1])
+	}
+	if parts[2] >= 60 {
+		return 0, fmt.Errorf("seconds must be less than 60, got %v", parts[2])
+	}
+
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+	if negative && c.hemisphere != 0 {
+		return 0, fmt.Errorf("coordinate has both a sign and a hemisphere designator")
+	}
+	if negative {
+		value = -value
+	}
+	if c.hemisphere != 0 {
+		value *= c.hemisphere.sign()
+	}
+
+	return value, nil
+}
+
+// FormatStyle определяет вид записи координат
+type FormatStyle int
+
+const (
+	// StyleDMS - градусы, минуты и секунды: 55°45'21.0"N
+	StyleDMS FormatStyle = iota
+	// StyleDM - градусы и десятичные минуты: 55°45.350'N
+	StyleDM
+	// StyleDecimal - десятичные градусы: 55.755800°N
+	StyleDecimal
+)
+
+// Locale определяет язык обозначений полушарий
+type Locale int
+
+const (
+	// LocaleEnglish - N, S, E, W
+	LocaleEnglish Locale = iota
+	// LocaleRussian - с.ш., ю.ш., в.д., з.д.
+	LocaleRussian
+	// LocaleSigned - знак минус вместо обозначения полушария
+	LocaleSigned
+)
+
+// FormatOptions задает параметры форматирования координат
+type FormatOptions struct {
+	Style FormatStyle
+	// Precision - количество знаков после запятой в последней единице (секундах, минутах или градусах)
+	Precision int
+	Locale    Locale
+	// HemisphereFirst - обозначение полушария записывается перед числом (N55°45'21")
+	HemisphereFirst bool
+	// LonFirst - долгота записывается перед широтой
+	LonFirst bool
+}
+
+// hemisphereLabels - обозначения полушарий для форматирования
+var hemisphereLabels = map[Locale]map[Hemisphere]string{
+	LocaleEnglish: {North: "N", South: "S", East: "E", West: "W"},
+	LocaleRussian: {North: "с.ш.", South: "ю.ш.", East: "в.д.", West: "з.д."},
+}
+
+// FormatPoint форматирует точку в виде строки "широта долгота"
+func FormatPoint(p types.Point, opts FormatOptions) string {
+	lat := FormatLatitude(p.GetLatitude(), opts)
+	lon := FormatLongitude(p.GetLongitude(), opts)
+	if opts.LonFirst {
+		return lon + " " + lat
+	}
+	return lat + " " + lon
+}
+
+// FormatLatitude форматирует широту
+func FormatLatitude(lat float64, opts FormatOptions) string {
+	h := North
+	if lat < 0 {
+		h = South
+	}
+	return formatCoordinate(lat, h, opts)
+}
+
+// FormatLongitude форматирует долготу
+func FormatLongitude(lon float64, opts FormatOptions) string {
+	h := East
+	if lon < 0 {
+		h = West
+	}
+	return formatCoordinate(lon, h, opts)
+}
+
+// formatCoordinate форматирует координату с обозначением полушария
+func formatCoordinate(value float64, h Hemisphere, opts FormatOptions) string {
+	precision := max(opts.Precision, 0)
+	abs := math.Abs(value)
+
+	var body string
+	switch opts.Style {
+	case StyleDecimal:
+		body = strconv.FormatFloat(abs, 'f', precision, 64) + "°"
+	case StyleDM:
+		// Округляем в наименьших единицах, чтобы избежать записи вида 60.000'
+		scale := math.Pow(10, float64(precision))
+		total := math.Round(abs * 60 * scale)
+		deg := math.Floor(total / (60 * scale))
+		minutes := (total - deg*60*scale) / scale
+		body = fmt.Sprintf("%d°%s'", int(deg), padFloat(minutes, precision))
+	default:
+		scale := math.Pow(10, float64(precision))
+		total := math.Round(abs * 3600 * scale)
+		deg := math.Floor(total / (3600 * scale))
+		rest := total - deg*3600*scale
+		minutes := math.Floor(rest / (60 * scale))
+		seconds := (rest - minutes*60*scale) / scale
+		body = fmt.Sprintf("%d°%02d'%s\"", int(deg), int(minutes), padFloat(seconds, precision))
+	}
+
+	if opts.Locale == LocaleSigned {
+		if value < 0 {
+			return "-" + body
+		}
+		return body
+	}
+
+	label := hemisphereLabels[opts.Locale][h]
+	separator := ""
+	if opts.Locale == LocaleRussian {
+		separator = " "
+	}
+	if opts.HemisphereFirst {
+		return label + separator + body
+	}
+	return body + separator + label
+}
+
+// padFloat форматирует число с ведущим нулем до двух цифр в целой части
+func padFloat(v float64, precision int) string {
+	s := strconv.FormatFloat(v, 'f', precision, 64)
+	if v < 10 {
+		s = "0" + s
+	}
+	return s
+}
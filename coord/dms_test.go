@@ -0,0 +1,129 @@
+package coord
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestParsePoint(t *testing.T) {
+	const kremlinLon, kremlinLat = 37 + 37.0/60 + 3.0/3600, 55 + 45.0/60 + 21.0/3600
+	tests := []struct {
+		in       string
+		lon, lat float64
+	}{
+		{`55°45'21"N 37°37'03"E`, kremlinLon, kremlinLat},
+		{`N55 45.35 E37 37.05`, kremlinLon, kremlinLat},
+		{`55°45′21″ с.ш. 37°37′03″ в.д.`, kremlinLon, kremlinLat},
+		{`55°45'21"пн.ш. 37°37'03"сх.д.`, kremlinLon, kremlinLat},
+		{`37°37'03"E 55°45'21"N`, kremlinLon, kremlinLat},
+		{`55 45 21 37 37 03`, kremlinLon, kremlinLat},
+		{`55.7558, 37.6173`, 37.6173, 55.7558},
+		{`55,7558 37,6173`, 37.6173, 55.7558},
+		{`55.7558N 37.6173E`, 37.6173, 55.7558},
+		{`-33.8688 151.2093`, 151.2093, -33.8688},
+		{`−33.8688; 151.2093`, 151.2093, -33.8688},
+		{`33°51'S 151°12'E`, 151.2, -33.85},
+		{`40 26 46 N 79 58 56 W`, -(79 + 58.0/60 + 56.0/3600), 40 + 26.0/60 + 46.0/3600},
+		{`w79 58.5 n40 26.5`, -(79 + 58.5/60), 40 + 26.5/60},
+		{`33.85 ю.ш. 151.2 з.д.`, -151.2, -33.85},
+		{`90 S 180 W`, -180, -90},
+	}
+	for _, tt := range tests {
+		p, err := ParsePoint(tt.in)
+		if err != nil {
+			t.Errorf("ParsePoint(%q): %v", tt.in, err)
+			continue
+		}
+		if math.Abs(p.GetLongitude()-tt.lon) > 1e-9 || math.Abs(p.GetLatitude()-tt.lat) > 1e-9 {
+			t.Errorf("ParsePoint(%q) = %v, want [%v %v]", tt.in, p, tt.lon, tt.lat)
+		}
+	}
+
+	p, err := ParsePointWithOptions(`37.6173 55.7558`, ParseOptions{LonFirst: true})
+	if err != nil || p.GetLongitude() != 37.6173 || p.GetLatitude() != 55.7558 {
+		t.Errorf("ParsePointWithOptions with LonFirst = %v, %v", p, err)
+	}
+}
+
+func TestParsePointInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"55.7558",
+		"55 45 21 37 37",
+		`55°45'21"N 37°37'03"N`,
+		`55°45'21"E 37°37'03"E`,
+		`55°61'N 37°37'E`,
+		`55°45'60"N 37°37'03"E`,
+		`91 N 37 E`,
+		`55 N 181 E`,
+		`-55 N 37 E`,
+		`55.5°45'N 37°E`,
+		`55°45'21"X 37°37'03"E`,
+		`55'45°N 37°37'E`,
+		"55 45 21 13 37 37 03 1",
+		"55.7558 # 37.6173",
+		"N E",
+	} {
+		if p, err := ParsePoint(s); err == nil {
+			t.Errorf("ParsePoint(%q) = %v, want error", s, p)
+		}
+	}
+}
+
+func TestFormatPoint(t *testing.T) {
+	p := types.NewPoint(37.6173, -55.7558)
+	tests := []struct {
+		opts FormatOptions
+		want string
+	}{
+		{FormatOptions{}, `55°45'21"S 37°37'02"E`},
+		{FormatOptions{Precision: 2}, `55°45'20.88"S 37°37'02.28"E`},
+		{FormatOptions{Style: StyleDM, Precision: 3}, `55°45.348'S 37°37.038'E`},
+		{FormatOptions{Style: StyleDecimal, Precision: 4}, `55.7558°S 37.6173°E`},
+		{FormatOptions{Locale: LocaleRussian, Precision: 1}, `55°45'20.9" ю.ш. 37°37'02.3" в.д.`},
+		{FormatOptions{Locale: LocaleSigned, Style: StyleDecimal, Precision: 2}, `-55.76° 37.62°`},
+		{FormatOptions{HemisphereFirst: true, LonFirst: true}, `E37°37'02" S55°45'21"`},
+		{FormatOptions{Precision: -1}, `55°45'21"S 37°37'02"E`},
+	}
+	for _, tt := range tests {
+		if got := FormatPoint(p, tt.opts); got != tt.want {
+			t.Errorf("FormatPoint(%+v) = %s, want %s", tt.opts, got, tt.want)
+		}
+	}
+
+	// Округление переносится в старшие единицы, а не дает 60 секунд или минут
+	if got := FormatPoint(types.NewPoint(10.99999999, 59.9999999), FormatOptions{Precision: 1}); got != `60°00'00.0"N 11°00'00.0"E` {
+		t.Errorf("rounding carry: %s", got)
+	}
+	if got := FormatPoint(types.NewPoint(10.99999999, 59.9999999), FormatOptions{Style: StyleDM, Precision: 2}); got != `60°00.00'N 11°00.00'E` {
+		t.Errorf("rounding carry in minutes: %s", got)
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	points := []types.Point{
+		types.NewPoint(37.6173, 55.7558),
+		types.NewPoint(-79.9822, 40.4461),
+		types.NewPoint(151.2093, -33.8688),
+		types.NewPoint(-0.0001, -0.0001),
+	}
+	for _, p := range points {
+		for _, opts := range []FormatOptions{
+			{Precision: 3},
+			{Style: StyleDM, Precision: 5, HemisphereFirst: true},
+			{Style: StyleDecimal, Precision: 6, Locale: LocaleRussian},
+			{Style: StyleDecimal, Precision: 6, Locale: LocaleSigned},
+		} {
+			s := FormatPoint(p, opts)
+			got, err := ParsePoint(s)
+			if err != nil {
+				t.Fatalf("ParsePoint(%q): %v", s, err)
+			}
+			if math.Abs(got.GetLongitude()-p.GetLongitude()) > 1e-6 || math.Abs(got.GetLatitude()-p.GetLatitude()) > 1e-6 {
+				t.Errorf("%v formatted as %q parses to %v", p, s, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package coord
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Точность MGRS - количество цифр в каждой из координат внутри 100-километрового квадрата
+const (
+	MGRSPrecision100km = 0
+	MGRSPrecision10km  = 1
+	MGRSPrecision1km   = 2
+	MGRSPrecision100m  = 3
+	MGRSPrecision10m   = 4
+	MGRSPrecision1m    = 5
+)
+
+// Буквы 100-километровых квадратов MGRS
+var (
+	mgrsColumnSets = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+	mgrsRowLetters = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// MGRS представляет координаты военной системы координатной сетки (Military Grid Reference System)
+type MGRS struct {
+	Zone int
+	Band byte
+	// Square - две буквы 100-километрового квадрата
+	Square string
+	// Easting, Northing - смещения юго-западного угла ячейки внутри квадрата (в метрах)
+	Easting  float64
+	Northing float64
+	// Precision - количество цифр в каждой координате (от 0 до 5)
+	Precision int
+}
+
+// ToMGRS преобразует точку в строку MGRS с указанной точностью (MGRSPrecision100km ... MGRSPrecision1m)
+func ToMGRS(p types.Point, precision int) (string, error) {
+	m, err := NewMGRS(p, precision)
+	if err != nil {
+		return "", err
+	}
+	return m.String(), nil
+}
+
+// NewMGRS преобразует точку в координаты MGRS с указанной точностью.
+// Полярные области (UPS) не поддерживаются
+func NewMGRS(p types.Point, precision int) (MGRS, error) {
+	if precision < MGRSPrecision100km || precision > MGRSPrecision1m {
+		return MGRS{}, fmt.Errorf("MGRS precision must be in range 0..5, got %d", precision)
+	}
+
+	u, err := ToUTM(p)
+	if err != nil {
+		return MGRS{}, fmt.Errorf("failed to convert to MGRS: %w", err)
+	}
+	return utmToMGRS(u, precision), nil
+}
+
+// utmToMGRS вычисляет квадрат и смещения MGRS по координатам UTM
+func utmToMGRS(u UTM, precision int) MGRS {
+	column := int(math.Floor(u.Easting / 100000))
+	row := int(math.Floor(u.Northing / 100000))
+
+	set := mgrsColumnSets[(u.Zone-1)%3]
+	// Для четных зон буквы строк смещены на 5
+	rowOffset := 0
+	if u.Zone%2 == 0 {
+		rowOffset = 5
+	}
+
+	square := string([]byte{
+		set[(column-1+len(set))%len(set)],
+		mgrsRowLetters[(row+rowOffset)%len(mgrsRowLetters)],
+	})
+
+	// Округляем вниз до размера ячейки выбранной точности
+	cell := math.Pow(10, float64(5-precision))
+	easting := math.Floor(math.Mod(u.Easting, 100000)/cell) * cell
+	northing := math.Floor(math.Mod(u.Northing, 100000)/cell) * cell
+
+	return MGRS{
+		Zone:      u.Zone,
+		Band:      u.Band,
+		Square:    square,
+		Easting:   easting,
+		Northing:  northing,
+		Precision: precision,
+	}
+}
+
+// String возвращает координаты MGRS без пробелов, например "37UDB1322479767"
+func (m MGRS) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d%c%s", m.Zone, m.Band, m.Square)
+	if m.Precision > 0 {
+		cell := math.Pow(10, float64(5-m.Precision))
+		fmt.Fprintf(&sb, "%0*d%0*d",
+			m.Precision, int(m.Easting/cell),
+			m.Precision, int(m.Northing/cell))
+	}
+	return sb.String()
+}
+
+// ParseMGRS строго разбирает строку MGRS. Допускаются пробелы между частями и строчные буквы,
+// например "37UDB1322479767" или "37U DB 13224 79767"
+func ParseMGRS(s string) (MGRS, error) {
+	compact := strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	if compact == "" {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: empty string", s)
+	}
+
+	// Номер зоны - одна или две цифры в начале строки
+	i := 0
+	for i < len(compact) && i < 3 && isDigit(compact[i]) {
+		i++
+	}
+	zone, err := parseZone(compact[:i])
+	if err != nil {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: %w", s, err)
+	}
+
+	rest := compact[i:]
+	if len(rest) < 3 {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: expected latitude band and two 100 km square letters after zone", s)
+	}
+
+	band := rest[0]
+	if strings.IndexByte(latitudeBands, band) < 0 {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: unknown latitude band %q", s, band)
+	}
+	if band == 'X' && (zone == 32 || zone == 34 || zone == 36) {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: zone %d%c does not exist", s, zone, band)
+	}
+
+	column, row := rest[1], rest[2]
+	set := mgrsColumnSets[(zone-1)%3]
+	if strings.IndexByte(set, column) < 0 {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: column letter %q is not used in zone %d (expected one of %s)", s, column, zone, set)
+	}
+	if strings.IndexByte(mgrsRowLetters, row) < 0 {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: unknown row letter %q", s, row)
+	}
+
+	digits := rest[3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return MGRS{}, fmt.Errorf("invalid MGRS %q: expected an even number of digits up to 10, got %d", s, len(digits))
+	}
+	for k := 0; k < len(digits); k++ {
+		if !isDigit(digits[k]) {
+			c, _ := utf8.DecodeRuneInString(digits[k:])
+			return MGRS{}, fmt.Errorf("invalid MGRS %q: unexpected character %q in numeric part", s, c)
+		}
+	}
+
+	precision := len(digits) / 2
+	cell := math.Pow(10, float64(5-precision))
+
+	m := MGRS{
+		Zone:      zone,
+		Band:      band,
+		Square:    string([]byte{column, row}),
+		Precision: precision,
+	}
+	if precision > 0 {
+		e, err := strconv.Atoi(digits[:precision])
+		if err != nil {
+			return MGRS{}, fmt.Errorf("invalid MGRS %q: easting: %w", s, err)
+		}
+		n, err := strconv.Atoi(digits[precision:])
+		if err != nil {
+			return MGRS{}, fmt.Errorf("invalid MGRS %q: northing: %w", s, err)
+		}
+		m.Easting = float64(e) * cell
+		m.Northing = float64(n) * cell
+	}
+
+	return m, nil
+}
+
+// ToUTM преобразует координаты юго-западного угла ячейки MGRS в UTM
+func (m MGRS) ToUTM() (UTM, error) {
+	if len(m.Square) != 2 {
+		return UTM{}, fmt.Errorf("invalid MGRS square %q", m.Square)
+	}
+
+	set := mgrsColumnSets[(m.Zone-1)%3]
+	column := strings.IndexByte(set, m.Square[0])
+	row := strings.IndexByte(mgrsRowLetters, m.Square[1])
+	if column < 0 || row < 0 {
+		return UTM{}, fmt.Errorf("invalid MGRS square %q for zone %d", m.Square, m.Zone)
+	}
+
+	rowOffset := 0
+	if m.Zone%2 == 0 {
+		rowOffset = 5
+	}
+	row = (row - rowOffset + len(mgrsRowLetters)) % len(mgrsRowLetters)
+
+	easting := float64(column+1)*100000 + m.Easting
+	northing := float64(row)*100000 + m.Northing
+
+	// Буквы строк повторяются каждые 2000 км, поэтому северное смещение
+	// восстанавливается по минимальному северному смещению широтного пояса
+	minNorthing, err := bandMinNorthing(m.Band)
+	if err != nil {
+		return UTM{}, err
+	}
+	for northing < minNorthing {
+		northing += 2000000
+	}
+
+	return UTM{
+		Zone:     m.Zone,
+		Band:     m.Band,
+		South:    m.Band < 'N',
+		Easting:  easting,
+		Northing: northing,
+	}, nil
+}
+
+// ToPoint возвращает юго-западный угол ячейки MGRS
+func (m MGRS) ToPoint() (types.Point, error) {
+	u, err := m.ToUTM()
+	if err != nil {
+		return nil, err
+	}
+	return u.ToPoint()
+}
+
+// Center возвращает центр ячейки MGRS
+func (m MGRS) Center() (types.Point, error) {
+	u, err := m.ToUTM()
+	if err != nil {
+		return nil, err
+	}
+
+	half := math.Pow(10, float64(5-m.Precision)) / 2
+	u.Easting += half
+	u.Northing += half
+	return u.ToPoint()
+}
+
+// MGRSToPoint разбирает строку MGRS и возвращает центр ячейки
+func MGRSToPoint(s string) (types.Point, error) {
+	m, err := ParseMGRS(s)
+	if err != nil {
+		return nil, err
+	}
+	return m.Center()
+}
+
+// bandMinNorthing возвращает северное смещение южной границы пояса на осевом меридиане,
+// округленное вниз до 100 км
+func bandMinNorthing(band byte) (float64, error) {
+	if strings.IndexByte(latitudeBands, band) < 0 {
+		return 0, fmt.Errorf("unknown latitude band %q", band)
+	}
+
+	south := band < 'N'
+	projection, err := proj.NewUTM(31, south)
+	if err != nil {
+		return 0, err
+	}
+
+	xy, err := projection.Forward(types.NewPoint(proj.UTMCentralMeridian(31), bandSouthLatitude(band)))
+	if err != nil {
+		return 0, err
+	}
+	return math.Floor(xy.GetLatitude()/100000) * 100000, nil
+}
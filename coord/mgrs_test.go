@@ -0,0 +1,114 @@
+package coord
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestToMGRS(t *testing.T) {
+	tests := []struct {
+		point types.Point
+		want  string
+	}{
+		{types.NewPoint(-74.0060, 40.7128), "18TWL8395907350"},
+		{types.NewPoint(37.6173, 55.7558), "37UDB1322479766"},
+		{types.NewPoint(151.2093, -33.8688), "56HLH3436850948"},
+		{types.NewPoint(5, 60), "32VKM7697958157"},
+		{types.NewPoint(10, 78), "33XUG8408563320"},
+	}
+	for _, tt := range tests {
+		got, err := ToMGRS(tt.point, MGRSPrecision1m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("ToMGRS(%v) = %s, want %s", tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestMGRSPrecisionRoundTrip(t *testing.T) {
+	points := []types.Point{
+		types.NewPoint(-74.0060, 40.7128),
+		types.NewPoint(37.6173, 55.7558),
+		types.NewPoint(151.2093, -33.8688),
+		types.NewPoint(5, 60),
+		types.NewPoint(10, 78),
+		types.NewPoint(-0.1, -79.9),
+	}
+	for _, p := range points {
+		full, _ := ToMGRS(p, MGRSPrecision1m)
+		for precision := MGRSPrecision100km; precision <= MGRSPrecision1m; precision++ {
+			s, err := ToMGRS(p, precision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s) != len(full)-2*(MGRSPrecision1m-precision) {
+				t.Fatalf("ToMGRS(%v, %d) = %s has wrong length", p, precision, s)
+			}
+			// Запись меньшей точности - усечение полной записи
+			prefix := full[:len(full)-10] + full[len(full)-10:len(full)-10+precision] + full[len(full)-5:len(full)-5+precision]
+			if s != prefix {
+				t.Errorf("ToMGRS(%v, %d) = %s, want %s", p, precision, s, prefix)
+			}
+
+			center, err := MGRSToPoint(s)
+			if err != nil {
+				t.Fatalf("MGRSToPoint(%q): %v", s, err)
+			}
+			// Центр 100-километрового квадрата у границы зоны может лежать в соседней зоне
+			if back, _ := ToMGRS(center, precision); back != s && precision > MGRSPrecision100km {
+				t.Errorf("centre of %s encodes to %s", s, back)
+			}
+			// Точка лежит в ячейке: расстояние до центра не больше половины диагонали
+			cell := math.Pow(10, float64(5-precision))
+			if d := calc.CalculateDistance(center, p) * 1000; d > cell*math.Sqrt2/2*1.01 {
+				t.Errorf("centre of %s is %g m from the point", s, d)
+			}
+
+			spaced := strings.ToLower(s[:len(s)-2*precision-2]) + " " + s[len(s)-2*precision-2:len(s)-2*precision] +
+				" " + s[len(s)-2*precision:len(s)-precision] + " " + s[len(s)-precision:]
+			m, err := ParseMGRS(spaced)
+			if err != nil || m.String() != s {
+				t.Errorf("ParseMGRS(%q) = %s, %v, want %s", spaced, m, err, s)
+			}
+		}
+	}
+}
+
+func TestParseMGRSInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"37",
+		"37U",
+		"37UD",
+		"61UDB1322479766",
+		"0UDB1322479766",
+		"37IDB1322479766",
+		"32XMH1234",
+		"37UJB1322479766",
+		"37UDI1322479766",
+		"37UDB132247976",
+		"37UDB13224797661",
+		"37UDB132247976612",
+		"37UDB1322x79766",
+		"4QFJ١٢٣٤",
+		"4QFJ+123",
+		"-4QFJ1234",
+	} {
+		if m, err := ParseMGRS(s); err == nil {
+			t.Errorf("ParseMGRS(%q) = %s, want error", s, m)
+		}
+	}
+
+	if _, err := ToMGRS(types.NewPoint(37, 55), 6); err == nil {
+		t.Error("ToMGRS accepted precision 6")
+	}
+	if _, err := ToMGRS(types.NewPoint(37, 85), MGRSPrecision1m); err == nil {
+		t.Error("ToMGRS accepted a polar point")
+	}
+}
@@ -0,0 +1,159 @@
+// Package coord содержит преобразование координат в распространенные текстовые форматы:
+// UTM, MGRS, градусы-минуты-секунды
+package coord
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Границы применимости UTM по широте
+const (
+	MinUTMLatitude = -80.0
+	MaxUTMLatitude = 84.0
+)
+
+// latitudeBands - буквы широтных поясов UTM/MGRS от 80° ю.ш. с шагом 8° (пояс X - 12°)
+const latitudeBands = "CDEFGHJKLMNPQRSTUVWX"
+
+// UTM представляет координаты в универсальной поперечной проекции Меркатора на эллипсоиде WGS 84
+type UTM struct {
+	Zone int
+	// Band - буква широтного пояса (C-X без I и O)
+	Band byte
+	// South - точка находится в южном полушарии
+	South    bool
+	Easting  float64
+	Northing float64
+}
+
+// ToUTM преобразует точку в координаты UTM с учетом исключений зон для Норвегии и Шпицбергена
+func ToUTM(p types.Point) (UTM, error) {
+	lat := p.GetLatitude()
+	if lat < MinUTMLatitude || lat > MaxUTMLatitude || math.IsNaN(lat) {
+		return UTM{}, fmt.Errorf("latitude %v is outside of UTM limits [%v, %v]", lat, MinUTMLatitude, MaxUTMLatitude)
+	}
+	return ToUTMZone(p, proj.UTMZone(p))
+}
+
+// ToUTMZone преобразует точку в координаты UTM принудительно в указанной зоне
+func ToUTMZone(p types.Point, zone int) (UTM, error) {
+	band, err := LatitudeBand(p.GetLatitude())
+	if err != nil {
+		return UTM{}, err
+	}
+
+	south := p.GetLatitude() < 0
+	projection, err := proj.NewUTM(zone, south)
+	if err != nil {
+		return UTM{}, err
+	}
+
+	xy, err := projection.Forward(p)
+	if err != nil {
+		return UTM{}, fmt.Errorf("failed to project to UTM zone %d: %w", zone, err)
+	}
+
+	return UTM{
+		Zone:     zone,
+		Band:     band,
+		South:    south,
+		Easting:  xy.GetLongitude(),
+		Northing: xy.GetLatitude(),
+	}, nil
+}
+
+// ToPoint преобразует координаты UTM в точку [долгота, широта]
+func (u UTM) ToPoint() (types.Point, error) {
+	projection, err := proj.NewUTM(u.Zone, u.South)
+	if err != nil {
+		return nil, err
+	}
+	return projection.Inverse(types.NewPoint(u.Easting, u.Northing))
+}
+
+// String возвращает координаты в виде "37U 413224 6179767" с округлением до метра
+func (u UTM) String() string {
+	return fmt.Sprintf("%d%c %d %d", u.Zone, u.Band, int(math.Floor(u.Easting)), int(math.Floor(u.Northing)))
+}
+
+// ParseUTM разбирает строку вида "37U 413224 6179767" (зона с буквой пояса, восточное смещение, северное смещение)
+func ParseUTM(s string) (UTM, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return UTM{}, fmt.Errorf("invalid UTM %q: expected \"<zone><band> <easting> <northing>\"", s)
+	}
+
+	zoneBand := strings.ToUpper(fields[0])
+	if len(zoneBand) < 2 {
+		return UTM{}, fmt.Errorf("invalid UTM %q: missing latitude band letter", s)
+	}
+
+	zone, err := parseZone(zoneBand[:len(zoneBand)-1])
+	if err != nil {
+		return UTM{}, fmt.Errorf("invalid UTM %q: %w", s, err)
+	}
+
+	band := zoneBand[len(zoneBand)-1]
+	if strings.IndexByte(latitudeBands, band) < 0 {
+		return UTM{}, fmt.Errorf("invalid UTM %q: unknown latitude band %q", s, band)
+	}
+
+	easting, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || !(easting >= 100000 && easting < 1000000) {
+		return UTM{}, fmt.Errorf("invalid UTM %q: easting must be a number in [100000, 1000000)", s)
+	}
+
+	northing, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil || !(northing >= 0 && northing <= 10000000) {
+		return UTM{}, fmt.Errorf("invalid UTM %q: northing must be a number in [0, 10000000]", s)
+	}
+
+	return UTM{
+		Zone:     zone,
+		Band:     band,
+		South:    band < 'N',
+		Easting:  easting,
+		Northing: northing,
+	}, nil
+}
+
+// LatitudeBand возвращает букву широтного пояса UTM/MGRS для широты
+func LatitudeBand(lat float64) (byte, error) {
+	if lat < MinUTMLatitude || lat > MaxUTMLatitude || math.IsNaN(lat) {
+		return 0, fmt.Errorf("latitude %v is outside of UTM limits [%v, %v]", lat, MinUTMLatitude, MaxUTMLatitude)
+	}
+
+	idx := int(math.Floor((lat + 80) / 8))
+	return latitudeBands[min(idx, len(latitudeBands)-1)], nil
+}
+
+// bandSouthLatitude возвращает южную границу широтного пояса (в градусах)
+func bandSouthLatitude(band byte) float64 {
+	return float64(strings.IndexByte(latitudeBands, band))*8 - 80
+}
+
+// parseZone разбирает номер зоны UTM
+func parseZone(s string) (int, error) {
+	if len(s) == 0 || len(s) > 2 || !isDigit(s[0]) || !isDigit(s[len(s)-1]) {
+		return 0, fmt.Errorf("zone must have 1 or 2 digits, got %q", s)
+	}
+	zone, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("zone must be a number, got %q", s)
+	}
+	if zone < 1 || zone > 60 {
+		return 0, fmt.Errorf("zone must be in range 1..60, got %d", zone)
+	}
+	return zone, nil
+}
+
+// isDigit проверяет, что байт - десятичная цифра ASCII
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,85 @@
+package coord
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestToUTM(t *testing.T) {
+	tests := []struct {
+		name  string
+		point types.Point
+		want  string
+	}{
+		{"new york", types.NewPoint(-74.0060, 40.7128), "18T 583959 4507350"},
+		{"moscow", types.NewPoint(37.6173, 55.7558), "37U 413224 6179766"},
+		{"sydney", types.NewPoint(151.2093, -33.8688), "56H 334368 6250948"},
+		// Зона 32V расширена на запад до 3° в.д.
+		{"norway 32V", types.NewPoint(5, 60), "32V 276979 6658157"},
+		// Зона 32X не используется, ее территория делится между 31X и 33X
+		{"svalbard 31X", types.NewPoint(8, 78), "31X 615914 8663320"},
+		{"svalbard 33X", types.NewPoint(10, 78), "33X 384085 8663320"},
+		{"svalbard 37X", types.NewPoint(40, 80), "37X 519384 8881752"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ToUTM(tt.point)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u.String(); got != tt.want {
+				t.Errorf("ToUTM = %s, want %s", got, tt.want)
+			}
+
+			p, err := u.ToPoint()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := calc.CalculateDistance(p, tt.point); d > 1e-6 {
+				t.Errorf("round trip moved the point by %g km", d)
+			}
+
+			parsed, err := ParseUTM(u.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Zone != u.Zone || parsed.Band != u.Band || parsed.South != u.South ||
+				math.Abs(parsed.Easting-u.Easting) > 1 || math.Abs(parsed.Northing-u.Northing) > 1 {
+				t.Errorf("ParseUTM(%q) = %+v, want %+v", u.String(), parsed, u)
+			}
+		})
+	}
+}
+
+func TestToUTMOutOfRange(t *testing.T) {
+	for _, lat := range []float64{-80.5, 84.5, 90, math.NaN()} {
+		if _, err := ToUTM(types.NewPoint(10, lat)); err == nil {
+			t.Errorf("ToUTM accepted latitude %g", lat)
+		}
+	}
+}
+
+func TestParseUTMInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"37U 413224",
+		"37 413224 6179766",
+		"61U 413224 6179766",
+		"0U 413224 6179766",
+		"+7U 413224 6179766",
+		"37I 413224 6179766",
+		"37U 99999 6179766",
+		"37U 1000000 6179766",
+		"37U NaN 6179766",
+		"37U 413224 -1",
+		"37U 413224 10000001",
+		"37U 413224 x",
+	} {
+		if u, err := ParseUTM(s); err == nil {
+			t.Errorf("ParseUTM(%q) = %+v, want error", s, u)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package crs содержит реестр систем координат по кодам EPSG и преобразования геометрий между ними
+package crs
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Fliiiiii/go-geo/datum"
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// EPSG коды часто используемых систем координат
+const (
+	EPSG4326 = 4326 // WGS 84, географические координаты
+	EPSG3857 = 3857 // WGS 84 / Pseudo-Mercator
+)
+
+// CRS описывает систему координат: датум и, для проекционных систем, проекцию
+type CRS struct {
+	EPSG  int
+	Name  string
+	Datum datum.Datum
+	// Projection - проекция системы координат, nil для географических систем
+	Projection proj.Projection
+}
+
+// IsGeographic проверяет, что система координат географическая (долгота и широта в градусах)
+func (c CRS) IsGeographic() bool {
+	return c.Projection == nil
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[int]CRS{}
+)
+
+// Register добавляет систему координат в реестр, заменяя существующую с тем же кодом
+func Register(c CRS) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[c.EPSG] = c
+}
+
+// Lookup возвращает систему координат по коду EPSG
+func Lookup(epsg int) (CRS, bool) {
+	registryMu.RLock()
+	c, ok := registry[epsg]
+	registryMu.RUnlock()
+	if ok {
+		return c, true
+	}
+
+	// Зоны UTM создаются по требованию
+	if c, ok := utmCRS(epsg); ok {
+		Register(c)
+		return c, true
+	}
+	return CRS{}, false
+}
+
+// Codes возвращает коды всех зарегистрированных систем координат по возрастанию
+func Codes() []int {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	codes := make([]int, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// PointFunc возвращает функцию преобразования точек из системы fromEPSG в систему toEPSG
+func PointFunc(fromEPSG, toEPSG int) (proj.PointFunc, error) {
+	from, ok := Lookup(fromEPSG)
+	if !ok {
+		return nil, fmt.Errorf("unknown source CRS EPSG:%d", fromEPSG)
+	}
+	to, ok := Lookup(toEPSG)
+	if !ok {
+		return nil, fmt.Errorf("unknown target CRS EPSG:%d", toEPSG)
+	}
+
+	sameDatum := from.Datum == to.Datum
+
+	return func(p types.Point) (types.Point, error) {
+		var err error
+		if from.Projection != nil {
+			if p, err = from.Projection.Inverse(p); err != nil {
+				return nil, err
+			}
+		}
+		if !sameDatum {
+			if p, err = datum.Transform(p, from.Datum, to.Datum); err != nil {
+				return nil, err
+			}
+		}
+		if to.Projection != nil {
+			if p, err = to.Projection.Forward(p); err != nil {
+				return nil, err
+			}
+		}
+		return p, nil
+	}, nil
+}
+
+// TransformPoint преобразует точку из системы fromEPSG в систему toEPSG
+func TransformPoint(p types.Point, fromEPSG, toEPSG int) (types.Point, error) {
+	fn, err := PointFunc(fromEPSG, toEPSG)
+	if err != nil {
+		return nil, err
+	}
+	return fn(p)
+}
+
+// Transform преобразует геометрию из системы fromEPSG в систему toEPSG.
+// Если у исходной геометрии задан член "crs", у результата он указывает на toEPSG
+func Transform(g types.Geometry, fromEPSG, toEPSG int) (types.Geometry, error) {
+	fn, err := PointFunc(fromEPSG, toEPSG)
+	if err != nil {
+		return types.Geometry{}, err
+	}
+
+	result, err := proj.TransformGeometry(g, fn)
+	if err != nil {
+		return types.Geometry{}, err
+	}
+	if g.CRS != nil {
+		result.CRS = types.NewEPSGCRS(toEPSG)
+	}
+	return result, nil
+}
+
+// SourceEPSG возвращает код системы координат из члена "crs".
+// При отсутствии члена возвращается EPSG:4326, как предписывает RFC 7946
+func SourceEPSG(member *types.CRS) (int, error) {
+	if member == nil {
+		return EPSG4326, nil
+	}
+	code, ok := member.EPSG()
+	if !ok {
+		return 0, fmt.Errorf("unsupported crs member of type %q", member.Type)
+	}
+	return code, nil
+}
+
+// TransformFeatureCollection преобразует все объекты коллекции в систему toEPSG.
+// Исходная система определяется по члену "crs" коллекции или отдельных геометрий (по умолчанию EPSG:4326).
+// Член "crs" записывается в результат, только если он был у исходной коллекции или toEPSG отличается от EPSG:4326
+func TransformFeatureCollection(fc *types.FeatureCollection, toEPSG int) (*types.FeatureCollection, error) {
+	fromEPSG, err := SourceEPSG(fc.CRS)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.FeatureCollection{
+		Type:     fc.Type,
+		Features: make([]types.Feature, len(fc.Features)),
+	}
+
+	for i, feature := range fc.Features {
+		geomEPSG := fromEPSG
+		if feature.Geometry.CRS != nil {
+			if geomEPSG, err = SourceEPSG(feature.Geometry.CRS); err != nil {
+				return nil, fmt.Errorf("error in feature %d: %w", i, err)
+			}
+		}
+
+		geometry, err := Transform(feature.Geometry, geomEPSG, toEPSG)
+		if err != nil {
+			return nil, fmt.Errorf("error in feature %d: %w", i, err)
+		}
+
+		feature.Geometry = geometry
+		result.Features[i] = feature
+	}
+
+	if fc.CRS != nil || toEPSG != EPSG4326 {
+		result.CRS = types.NewEPSGCRS(toEPSG)
+	}
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package crs
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestCodesSorted(t *testing.T) {
+	Lookup(32637) // зона UTM регистрируется при первом обращении
+
+	codes := Codes()
+	if !slices.IsSorted(codes) {
+		t.Fatalf("codes are not sorted: %v", codes)
+	}
+	if !slices.Contains(codes, EPSG4326) || !slices.Contains(codes, 32637) {
+		t.Errorf("codes %v miss registered systems", codes)
+	}
+	if again := Codes(); !slices.Equal(codes, again) {
+		t.Errorf("repeated calls differ: %v and %v", codes, again)
+	}
+}
+
+// checkLine проверяет координаты линии с допуском tol
+func checkLine(t *testing.T, name string, g types.Geometry, want [][2]float64, tol float64) {
+	t.Helper()
+	line, ok := g.Coordinates.(types.LineString)
+	if !ok || g.Type != types.GeometryLineString || len(line) != len(want) {
+		t.Fatalf("%s = %+v, want a line of %d points", name, g, len(want))
+	}
+	for i, p := range line {
+		if math.Abs(p.GetLongitude()-want[i][0]) > tol || math.Abs(p.GetLatitude()-want[i][1]) > tol {
+			t.Errorf("%s point %d = %v, want %v", name, i, p, want[i])
+		}
+	}
+}
+
+// lineCoords возвращает координаты точек линии
+func lineCoords(line types.LineString) [][2]float64 {
+	coords := make([][2]float64, len(line))
+	for i, p := range line {
+		coords[i] = [2]float64{p.GetLongitude(), p.GetLatitude()}
+	}
+	return coords
+}
+
+func TestTransformUTM(t *testing.T) {
+	line := types.NewLineString(types.NewPoint(39, 45), types.NewPoint(37.6173, 55.7558), types.NewPoint(36.5, 60))
+	g := types.NewLineStringGeometry(line)
+	g.CRS = types.NewEPSGCRS(EPSG4326)
+
+	// Контрольные значения вычислены по рядам Крюгера шестого порядка
+	utm, err := Transform(g, EPSG4326, 32637)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLine(t, "EPSG:32637", utm, [][2]float64{
+		{500000, 4982950.400},
+		{413224.138, 6179766.954},
+		{360577.912, 6654046.024},
+	}, 0.001)
+	if code, ok := utm.CRS.EPSG(); !ok || code != 32637 {
+		t.Errorf("crs member = %+v, want EPSG:32637", utm.CRS)
+	}
+
+	back, err := Transform(utm, 32637, EPSG4326)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLine(t, "round trip", back, lineCoords(line), 1e-8)
+
+	// Без члена "crs" у исходной геометрии он не появляется и у результата
+	if plain, err := Transform(types.NewLineStringGeometry(line), EPSG4326, 32637); err != nil || plain.CRS != nil {
+		t.Errorf("crs member without source crs = %+v, %v", plain.CRS, err)
+	}
+}
+
+func TestTransformWebMercator(t *testing.T) {
+	line := types.NewLineString(types.NewPoint(0, 0), types.NewPoint(179, 0), types.NewPoint(37.6173, 55.7558))
+	g, err := Transform(types.NewLineStringGeometry(line), EPSG4326, EPSG3857)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// x = R·λ, y = R·ln tg(π/4 + φ/2) при R = 6378137 м
+	checkLine(t, "EPSG:3857", g, [][2]float64{
+		{0, 0},
+		{19926188.8520, 0},
+		{4187538.6810, 7509955.1423},
+	}, 0.001)
+
+	back, err := Transform(g, EPSG3857, EPSG4326)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLine(t, "round trip", back, lineCoords(line), 1e-9)
+}
+
+func TestTransformGaussKruger(t *testing.T) {
+	// На осевом меридиане зоны 7 (39°) абсцисса равна ложному смещению с номером зоны,
+	// а ордината - длине дуги меридиана эллипсоида Красовского
+	line := types.NewLineString(types.NewPoint(39, 55), types.NewPoint(39, 60))
+	g, err := Transform(types.NewLineStringGeometry(line), 4284, 28407)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLine(t, "EPSG:28407", g, [][2]float64{
+		{7500000, 6097337.192},
+		{7500000, 6654189.092},
+	}, 0.001)
+
+	// Из WGS 84 точка сначала переводится в СК-42, поэтому результат смещается
+	// на десятки метров, а обратное преобразование возвращает исходную точку
+	moscow := types.NewLineString(types.NewPoint(37.6173, 55.7558))
+	wgs, err := Transform(types.NewLineStringGeometry(moscow), EPSG4326, 28407)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := Transform(types.NewLineStringGeometry(moscow), 4284, 28407)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, q := wgs.Coordinates.(types.LineString)[0], sk.Coordinates.(types.LineString)[0]
+	if shift := math.Hypot(p.GetLongitude()-q.GetLongitude(), p.GetLatitude()-q.GetLatitude()); shift < 50 || shift > 300 {
+		t.Errorf("datum shift %g m, want tens of metres", shift)
+	}
+	back, err := Transform(wgs, 28407, EPSG4326)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLine(t, "round trip", back, lineCoords(moscow), 1e-8)
+
+	if _, err := Transform(types.NewLineStringGeometry(moscow), EPSG4326, 28433); err == nil {
+		t.Error("unknown zone is accepted")
+	}
+}
@@ -0,0 +1,103 @@
+package crs
+
+import (
+	"fmt"
+
+	"github.com/Fliiiiii/go-geo/datum"
+	"github.com/Fliiiiii/go-geo/proj"
+)
+
+func init() {
+	// Географические системы координат
+	for _, c := range []CRS{
+		{EPSG: EPSG4326, Name: "WGS 84", Datum: datum.WGS84},
+		{EPSG: 4284, Name: "Pulkovo 1942", Datum: datum.SK42},
+		{EPSG: 4200, Name: "Pulkovo 1995", Datum: datum.SK95},
+		{EPSG: 4740, Name: "PZ-90", Datum: datum.PZ90},
+		{EPSG: 7679, Name: "PZ-90.11", Datum: datum.PZ9011},
+		{EPSG: 7683, Name: "GSK-2011", Datum: datum.GSK2011},
+	} {
+		Register(c)
+	}
+
+	// Web Mercator и его устаревшие коды
+	webMercator := proj.NewWebMercator()
+	for _, code := range []int{EPSG3857, 900913, 102100} {
+		Register(CRS{EPSG: code, Name: "WGS 84 / Pseudo-Mercator", Datum: datum.WGS84, Projection: webMercator})
+	}
+
+	// ETRS89 / LAEA Europe. ETRS89 совпадает с WGS 84 с точностью до метра
+	laea, _ := proj.NewLambertAzimuthalEqualArea(proj.AzimuthalParams{
+		Ellipsoid:     proj.GRS80,
+		CenterLon:     10,
+		CenterLat:     52,
+		FalseEasting:  4321000,
+		FalseNorthing: 3210000,
+	})
+	Register(CRS{EPSG: 3035, Name: "ETRS89-extended / LAEA Europe", Datum: datum.WGS84, Projection: laea})
+
+	// NAD83 / Conus Albers. NAD83 совпадает с WGS 84 с точностью до пары метров
+	albers, _ := proj.NewAlbersEqualArea(proj.ConicParams{
+		Ellipsoid:         proj.GRS80,
+		CentralMeridian:   -96,
+		LatitudeOfOrigin:  23,
+		StandardParallel1: 29.5,
+		StandardParallel2: 45.5,
+	})
+	Register(CRS{EPSG: 5070, Name: "NAD83 / Conus Albers", Datum: datum.WGS84, Projection: albers})
+
+	// Pulkovo 1942 / Gauss-Kruger, зоны 4-32 с номером зоны в ложном смещении на восток
+	for zone := 4; zone <= 32; zone++ {
+		Register(CRS{
+			EPSG:  28400 + zone,
+			Name:  fmt.Sprintf("Pulkovo 1942 / Gauss-Kruger zone %d", zone),
+			Datum: datum.SK42,
+			Projection: proj.NewTransverseMercator(proj.TransverseMercatorParams{
+				Ellipsoid:       proj.Krassovsky,
+				CentralMeridian: float64(zone)*6 - 3,
+				ScaleFactor:     1,
+				FalseEasting:    float64(zone)*1e6 + 500000,
+			}),
+		})
+	}
+}
+
+// utmCRS создает систему координат WGS 84 / UTM для кодов 32601-32660 и 32701-32760
+func utmCRS(epsg int) (CRS, bool) {
+	var zone int
+	var south bool
+
+	switch {
+	case epsg >= 32601 && epsg <= 32660:
+		zone = epsg - 32600
+	case epsg >= 32701 && epsg <= 32760:
+		zone = epsg - 32700
+		south = true
+	default:
+		return CRS{}, false
+	}
+
+	projection, err := proj.NewUTM(zone, south)
+	if err != nil {
+		return CRS{}, false
+	}
+
+	hemisphere := "N"
+	if south {
+		hemisphere = "S"
+	}
+	return CRS{
+		EPSG:       epsg,
+		Name:       fmt.Sprintf("WGS 84 / UTM zone %d%s", zone, hemisphere),
+		Datum:      datum.WGS84,
+		Projection: projection,
+	}, true
+}
+
+// UTMEPSG возвращает код EPSG системы WGS 84 / UTM для зоны и полушария
+func UTMEPSG(zone int, south bool) int {
+	if south {
+		return 32700 + zone
+	}
+	return 32600 + zone
+}
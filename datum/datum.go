@@ -0,0 +1,137 @@
+// Package datum содержит преобразования координат между геодезическими датумами:
+// переход к геоцентрическим координатам, преобразования Гельмерта и Молоденского
+// и параметры распространенных систем координат, включая СК-42, СК-95, ГСК-2011 и ПЗ-90
+package datum
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Datum описывает геодезический датум: эллипсоид и параметры перехода к WGS 84
+type Datum struct {
+	Name      string
+	Ellipsoid proj.Ellipsoid
+	// ToWGS84 - параметры преобразования геоцентрических координат датума в WGS 84
+	ToWGS84 Helmert
+}
+
+// Распространенные датумы. Параметры перехода для российских систем координат
+// взяты из ГОСТ 32453-2017 и ГОСТ Р 51794-2008 и приведены к соглашению Position Vector
+var (
+	WGS84 = Datum{Name: "WGS84", Ellipsoid: proj.WGS84}
+	// SK42 - система координат 1942 года (Пулково-1942)
+	SK42 = Datum{
+		Name:      "SK-42",
+		Ellipsoid: proj.Krassovsky,
+		ToWGS84:   HelmertFromCoordinateFrame(23.57, -140.95, -79.8, 0, -0.35, -0.79, -0.22),
+	}
+	// SK95 - система координат 1995 года
+	SK95 = Datum{
+		Name:      "SK-95",
+		Ellipsoid: proj.Krassovsky,
+		ToWGS84:   HelmertFromCoordinateFrame(24.47, -130.89, -81.56, 0, 0, -0.13, -0.22),
+	}
+	// PZ90 - Параметры Земли 1990 года (первоначальная версия)
+	PZ90 = Datum{
+		Name:      "PZ-90",
+		Ellipsoid: proj.PZ90,
+		ToWGS84:   HelmertFromCoordinateFrame(-1.08, -0.27, -0.9, 0, 0, -0.16, -0.12),
+	}
+	// PZ9002 - Параметры Земли 1990 года, редакция 2002 года
+	PZ9002 = Datum{
+		Name:      "PZ-90.02",
+		Ellipsoid: proj.PZ90,
+		ToWGS84:   HelmertFromCoordinateFrame(-0.36, 0.08, 0.18, 0, 0, 0, 0),
+	}
+	// PZ9011 - Параметры Земли 1990 года, редакция 2011 года
+	PZ9011 = Datum{
+		Name:      "PZ-90.11",
+		Ellipsoid: proj.PZ90,
+		ToWGS84:   HelmertFromCoordinateFrame(-0.013, 0.106, 0.022, -0.00230, 0.00354, -0.00421, -0.008),
+	}
+	// GSK2011 - геодезическая система координат 2011 года.
+	// Параметры получены последовательным переходом ГСК-2011 → ПЗ-90.11 → WGS 84
+	GSK2011 = Datum{
+		Name:      "GSK-2011",
+		Ellipsoid: proj.GSK2011,
+		ToWGS84:   HelmertFromCoordinateFrame(-0.013, 0.120, 0.014, -0.002862, 0.003559, -0.004263, -0.0086),
+	}
+)
+
+// datums содержит датумы, доступные по названию
+var datums = map[string]Datum{}
+
+func init() {
+	for _, d := range []Datum{WGS84, SK42, SK95, PZ90, PZ9002, PZ9011, GSK2011} {
+		Register(d)
+	}
+	datums["PULKOVO1942"] = SK42
+	datums["PULKOVO1995"] = SK95
+}
+
+// Register добавляет датум в список именованных датумов
+func Register(d Datum) {
+	datums[normalizeName(d.Name)] = d
+}
+
+// ByName возвращает датум по названию без учета регистра и разделителей
+func ByName(name string) (Datum, bool) {
+	d, ok := datums[normalizeName(name)]
+	return d, ok
+}
+
+// normalizeName приводит название датума к виду для поиска
+func normalizeName(name string) string {
+	r := strings.NewReplacer("-", "", "_", "", " ", "", ".", "")
+	return strings.ToUpper(r.Replace(name))
+}
+
+// Transform преобразует точку (долгота и широта в градусах, необязательная высота в метрах)
+// из датума from в датум to через геоцентрические координаты WGS 84
+func Transform(p types.Point, from, to Datum) (types.Point, error) {
+	if len(p) < 2 {
+		return nil, fmt.Errorf("point must have at least 2 coordinates, got %d", len(p))
+	}
+	if from == to {
+		return p, nil
+	}
+
+	c := GeodeticToECEF(from.Ellipsoid, p.GetLongitude(), p.GetLatitude(), pointHeight(p))
+	if !from.ToWGS84.IsZero() {
+		c = from.ToWGS84.Apply(c)
+	}
+	if !to.ToWGS84.IsZero() {
+		c = to.ToWGS84.ApplyInverse(c)
+	}
+
+	lon, lat, h := ECEFToGeodetic(to.Ellipsoid, c)
+	return withHeight(p, lon, lat, h), nil
+}
+
+// PointFunc возвращает функцию преобразования точек из датума from в датум to
+func PointFunc(from, to Datum) proj.PointFunc {
+	return func(p types.Point) (types.Point, error) {
+		return Transform(p, from, to)
+	}
+}
+
+// TransformGeometry преобразует все точки геометрии из датума from в датум to
+func TransformGeometry(g types.Geometry, from, to Datum) (types.Geometry, error) {
+	return proj.TransformGeometry(g, PointFunc(from, to))
+}
+
+// MolodenskyTransform преобразует точку из датума from в датум to по формулам Молоденского,
+// используя только линейные смещения параметров перехода к WGS 84
+func MolodenskyTransform(p types.Point, from, to Datum, abridged bool) types.Point {
+	m := Molodensky{
+		DX:       from.ToWGS84.TX - to.ToWGS84.TX,
+		DY:       from.ToWGS84.TY - to.ToWGS84.TY,
+		DZ:       from.ToWGS84.TZ - to.ToWGS84.TZ,
+		Abridged: abridged,
+	}
+	return m.Transform(from.Ellipsoid, to.Ellipsoid, p)
+}
@@ -0,0 +1,182 @@
+package datum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// dms переводит градусы, минуты и секунды в градусы
+func dms(d, m, s float64) float64 {
+	return d + m/60 + s/3600
+}
+
+// checkECEF проверяет геоцентрические координаты с допуском tol метров
+func checkECEF(t *testing.T, name string, got, want ECEF, tol float64) {
+	t.Helper()
+	if math.Abs(got.X-want.X) > tol || math.Abs(got.Y-want.Y) > tol || math.Abs(got.Z-want.Z) > tol {
+		t.Errorf("%s = %+v, want %+v", name, got, want)
+	}
+}
+
+// checkPoint проверяет долготу и широту с допуском tol градусов и высоту с допуском tolH метров
+func checkPoint(t *testing.T, name string, got types.Point, lon, lat, h, tol, tolH float64) {
+	t.Helper()
+	if math.Abs(got.GetLongitude()-lon) > tol || math.Abs(got.GetLatitude()-lat) > tol ||
+		math.Abs(pointHeight(got)-h) > tolH {
+		t.Errorf("%s = %v, want [%.9f %.9f %.4f]", name, got, lon, lat, h)
+	}
+}
+
+func TestECEF(t *testing.T) {
+	// Пример EPSG Guidance Note 7-2 для метода 9602
+	lon, lat := dms(2, 7, 46.38), dms(53, 48, 33.82)
+	c := GeodeticToECEF(proj.WGS84, lon, lat, 73)
+	checkECEF(t, "GeodeticToECEF", c, ECEF{X: 3771793.968, Y: 140253.342, Z: 5124304.349}, 0.001)
+	gotLon, gotLat, gotH := ECEFToGeodetic(proj.WGS84, c)
+	checkPoint(t, "ECEFToGeodetic", types.Point{gotLon, gotLat, gotH}, lon, lat, 73, 1e-11, 1e-6)
+
+	// Обратное преобразование на разных эллипсоидах, включая полюса и большие высоты
+	r := rand.New(rand.NewSource(1))
+	for _, e := range []proj.Ellipsoid{proj.WGS84, proj.Krassovsky, proj.PZ90} {
+		for i := 0; i < 1000; i++ {
+			lon, lat, h := r.Float64()*360-180, r.Float64()*180-90, r.Float64()*20000-1000
+			if i < 2 {
+				lat = float64(1-2*i) * 90
+			}
+			gotLon, gotLat, gotH := ECEFToGeodetic(e, GeodeticToECEF(e, lon, lat, h))
+			if math.Abs(gotLat-lat) > 1e-10 || math.Abs(gotH-h) > 1e-6 ||
+				(math.Abs(lat) < 90 && math.Abs(gotLon-lon) > 1e-10) {
+				t.Fatalf("%s: round trip of [%g %g %g] gives [%g %g %g]", e.Name, lon, lat, h, gotLon, gotLat, gotH)
+			}
+		}
+	}
+}
+
+func TestHelmertConventions(t *testing.T) {
+	// Пример EPSG Guidance Note 7-2 WGS 72 → WGS 84: одно и то же преобразование
+	// в соглашениях Position Vector (9606) и Coordinate Frame (9607)
+	src := ECEF{X: 3657660.66, Y: 255768.55, Z: 5201382.11}
+	want := ECEF{X: 3657660.78, Y: 255778.43, Z: 5201387.75}
+
+	pv := Helmert{TZ: 4.5, RZ: 0.554, Scale: 0.219}
+	cf := HelmertFromCoordinateFrame(0, 0, 4.5, 0, 0, -0.554, 0.219)
+	if pv != cf {
+		t.Errorf("HelmertFromCoordinateFrame = %+v, want %+v", cf, pv)
+	}
+	checkECEF(t, "Position Vector", pv.Apply(src), want, 0.01)
+	checkECEF(t, "Coordinate Frame", cf.Apply(src), want, 0.01)
+	checkECEF(t, "ApplyInverse", pv.ApplyInverse(pv.Apply(src)), src, 1e-6)
+
+	// Со знаком Position Vector, переданным как Coordinate Frame, разворот идет в обратную сторону
+	wrong := HelmertFromCoordinateFrame(0, 0, 4.5, 0, 0, 0.554, 0.219).Apply(src)
+	if math.Abs(wrong.Y-want.Y) < 10 {
+		t.Errorf("rotation sign does not matter: %+v", wrong)
+	}
+
+	if !(Helmert{}).IsZero() || pv.IsZero() {
+		t.Error("IsZero is wrong")
+	}
+}
+
+func TestMolodensky(t *testing.T) {
+	// Пример EPSG Guidance Note 7-2 WGS 84 → ED50 для методов 9604 и 9605
+	international := proj.Ellipsoid{Name: "International 1924", A: 6378388, InvFlattening: 297}
+	p := types.Point{dms(2, 7, 46.38), dms(53, 48, 33.82), 73}
+	m := Molodensky{DX: 84.87, DY: 96.49, DZ: 116.95}
+
+	const arcSec = 1.0 / 3600
+	got := m.Transform(proj.WGS84, international, p)
+	checkPoint(t, "Molodensky", got, dms(2, 7, 51.477), dms(53, 48, 36.565), 28.02, 0.002*arcSec, 0.01)
+
+	m.Abridged = true
+	got = m.Transform(proj.WGS84, international, p)
+	checkPoint(t, "abridged Molodensky", got, dms(2, 7, 51.477), dms(53, 48, 36.563), 28.091, 0.002*arcSec, 0.01)
+
+	// Без высоты у исходной точки результат тоже двумерный
+	if got := m.Transform(proj.WGS84, international, types.NewPoint(2, 53)); len(got) != 2 {
+		t.Errorf("2D point is transformed to %v", got)
+	}
+}
+
+// Контрольные значения вычислены по формулам ГОСТ 32453-2017 (соглашение Coordinate Frame)
+// с параметрами ГОСТ Р 51794-2008 и ГОСТ 32453-2017 независимо от Helmert
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		datum         Datum
+		lon, lat, h   float64
+		wLon, wLat, w float64
+	}{
+		{SK42, 37.6173, 55.7558, 150, 37.615425530, 55.755842659, 154.5469},
+		{SK42, 30.3267, 59.7718, 75, 30.324458534, 59.771771096, 89.0544},
+		{SK42, 131.8869, 43.1155, 0, 131.887992823, 43.115807293, -34.7419},
+		{SK95, 37.6173, 55.7558, 150, 37.615446952, 55.755859704, 156.9753},
+		{SK95, 131.8869, 43.1155, 0, 131.887786038, 43.115688409, -30.9408},
+		{PZ9011, 37.6173, 55.7558, 150, 37.617302770, 55.755801273, 149.0308},
+		{PZ9011, 131.8869, 43.1155, 0, 131.886901496, 43.115499853, -0.9499},
+		{GSK2011, 37.6173, 55.7558, 150, 37.617302786, 55.755800399, 149.4543},
+		{GSK2011, 30.3267, 59.7718, 75, 30.326702826, 59.771800492, 74.4407},
+	}
+	for _, tt := range tests {
+		p := types.Point{tt.lon, tt.lat, tt.h}
+		got, err := Transform(p, tt.datum, WGS84)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPoint(t, tt.datum.Name+" → WGS84", got, tt.wLon, tt.wLat, tt.w, 1e-8, 0.001)
+
+		back, err := Transform(got, WGS84, tt.datum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPoint(t, "WGS84 → "+tt.datum.Name, back, tt.lon, tt.lat, tt.h, 1e-10, 1e-4)
+	}
+
+	// Переход СК-42 → ПЗ-90.11 через WGS 84 отличается от прямого перехода с параметрами
+	// ГОСТ 32453-2017 меньше чем на метр: так согласованы параметры двух стандартов
+	p := types.Point{37.6173, 55.7558, 150}
+	got, err := Transform(p, SK42, PZ9011)
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct := Datum{
+		Name:      "SK-42 (GOST 32453)",
+		Ellipsoid: proj.Krassovsky,
+		ToWGS84:   HelmertFromCoordinateFrame(23.557, -140.844, -79.778, -0.00230, -0.34646, -0.79421, -0.228),
+	}
+	want, _ := Transform(p, direct, Datum{Name: "PZ-90.11", Ellipsoid: proj.PZ90})
+	checkPoint(t, "SK-42 → PZ-90.11", got, want.GetLongitude(), want.GetLatitude(), pointHeight(want), 1e-5, 0.5)
+
+	// Двумерная точка остается двумерной, одинаковые датумы не меняют точку
+	if got, _ := Transform(types.NewPoint(37, 55), SK42, WGS84); len(got) != 2 {
+		t.Errorf("2D point is transformed to %v", got)
+	}
+	if got, _ := Transform(p, SK42, SK42); &got[0] != &p[0] {
+		t.Error("same datum transform copies the point")
+	}
+	if _, err := Transform(types.Point{37}, SK42, WGS84); err == nil {
+		t.Error("1D point is accepted")
+	}
+}
+
+func TestByName(t *testing.T) {
+	for name, want := range map[string]string{
+		"sk-42":        "SK-42",
+		"Pulkovo1942":  "SK-42",
+		"PULKOVO_1995": "SK-95",
+		"pz 90.11":     "PZ-90.11",
+		"GSK2011":      "GSK-2011",
+		"wgs84":        "WGS84",
+	} {
+		d, ok := ByName(name)
+		if !ok || d.Name != want {
+			t.Errorf("ByName(%q) = %q, %v, want %q", name, d.Name, ok, want)
+		}
+	}
+	if _, ok := ByName("NAD27"); ok {
+		t.Error("unknown datum is found")
+	}
+}
@@ -0,0 +1,91 @@
+package datum
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// ECEF представляет геоцентрические прямоугольные координаты (в метрах)
+type ECEF struct {
+	X, Y, Z float64
+}
+
+// GeodeticToECEF преобразует геодезические координаты (долгота и широта в градусах, высота в метрах)
+// в геоцентрические прямоугольные координаты на указанном эллипсоиде
+func GeodeticToECEF(ellipsoid proj.Ellipsoid, lon, lat, height float64) ECEF {
+	e2 := ellipsoid.E2()
+	lonRad := lon * math.Pi / 180.0
+	latRad := lat * math.Pi / 180.0
+
+	sinLat := math.Sin(latRad)
+	cosLat := math.Cos(latRad)
+
+	// Радиус кривизны первого вертикала
+	n := ellipsoid.A / math.Sqrt(1-e2*sinLat*sinLat)
+
+	return ECEF{
+		X: (n + height) * cosLat * math.Cos(lonRad),
+		Y: (n + height) * cosLat * math.Sin(lonRad),
+		Z: (n*(1-e2) + height) * sinLat,
+	}
+}
+
+// ECEFToGeodetic преобразует геоцентрические прямоугольные координаты в геодезические
+// (долгота и широта в градусах, высота в метрах) на указанном эллипсоиде
+func ECEFToGeodetic(ellipsoid proj.Ellipsoid, c ECEF) (lon, lat, height float64) {
+	a := ellipsoid.A
+	b := ellipsoid.B()
+	e2 := ellipsoid.E2()
+	ep2 := (a*a - b*b) / (b * b)
+
+	p := math.Hypot(c.X, c.Y)
+	lonRad := math.Atan2(c.Y, c.X)
+
+	// Начальное приближение по формуле Боуринга
+	theta := math.Atan2(c.Z*a, p*b)
+	sinT, cosT := math.Sin(theta), math.Cos(theta)
+	latRad := math.Atan2(c.Z+ep2*b*sinT*sinT*sinT, p-e2*a*cosT*cosT*cosT)
+
+	// Уточняем широту итерациями
+	for i := 0; i < 5; i++ {
+		sinLat := math.Sin(latRad)
+		n := a / math.Sqrt(1-e2*sinLat*sinLat)
+		next := math.Atan2(c.Z+e2*n*sinLat, p)
+		if math.Abs(next-latRad) < 1e-14 {
+			latRad = next
+			break
+		}
+		latRad = next
+	}
+
+	sinLat := math.Sin(latRad)
+	cosLat := math.Cos(latRad)
+	n := a / math.Sqrt(1-e2*sinLat*sinLat)
+
+	if math.Abs(cosLat) > 1e-10 {
+		height = p/cosLat - n
+	} else {
+		height = math.Abs(c.Z) - b
+	}
+
+	return lonRad * 180.0 / math.Pi, latRad * 180.0 / math.Pi, height
+}
+
+// pointHeight возвращает высоту точки (третья координата) или ноль
+func pointHeight(p types.Point) float64 {
+	if len(p) > 2 {
+		return p[2]
+	}
+	return 0
+}
+
+// withHeight создает точку, сохраняя высоту только если она была у исходной точки
+func withHeight(src types.Point, lon, lat, height float64) types.Point {
+	if len(src) > 2 {
+		out := types.Point{lon, lat, height}
+		return append(out, src[3:]...)
+	}
+	return types.NewPoint(lon, lat)
+}
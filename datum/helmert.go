@@ -0,0 +1,90 @@
+package datum
+
+import "math"
+
+// arcSecond - угловая секунда в радианах
+const arcSecond = math.Pi / (180.0 * 3600.0)
+
+// Helmert содержит параметры семипараметрического преобразования Гельмерта
+// в соглашении Position Vector (EPSG:9606), как в параметре towgs84 библиотеки PROJ.
+// Для параметров в соглашении Coordinate Frame (EPSG:9607, ГОСТ 32453) используйте HelmertFromCoordinateFrame
+type Helmert struct {
+	// TX, TY, TZ - линейные смещения (в метрах)
+	TX, TY, TZ float64
+	// RX, RY, RZ - углы разворота осей (в угловых секундах)
+	RX, RY, RZ float64
+	// Scale - масштабный коэффициент (в миллионных долях)
+	Scale float64
+}
+
+// HelmertFromCoordinateFrame создает параметры преобразования из параметров в соглашении
+// Coordinate Frame, отличающемся знаком углов разворота
+func HelmertFromCoordinateFrame(tx, ty, tz, rx, ry, rz, scale float64) Helmert {
+	return Helmert{TX: tx, TY: ty, TZ: tz, RX: -rx, RY: -ry, RZ: -rz, Scale: scale}
+}
+
+// IsZero проверяет, что преобразование тождественное
+func (h Helmert) IsZero() bool {
+	return h == Helmert{}
+}
+
+// Apply выполняет прямое преобразование геоцентрических координат
+func (h Helmert) Apply(c ECEF) ECEF {
+	rx, ry, rz := h.RX*arcSecond, h.RY*arcSecond, h.RZ*arcSecond
+	m := 1 + h.Scale*1e-6
+
+	return ECEF{
+		X: h.TX + m*(c.X-rz*c.Y+ry*c.Z),
+		Y: h.TY + m*(rz*c.X+c.Y-rx*c.Z),
+		Z: h.TZ + m*(-ry*c.X+rx*c.Y+c.Z),
+	}
+}
+
+// ApplyInverse выполняет точное обратное преобразование геоцентрических координат
+func (h Helmert) ApplyInverse(c ECEF) ECEF {
+	rx, ry, rz := h.RX*arcSecond, h.RY*arcSecond, h.RZ*arcSecond
+	m := 1 + h.Scale*1e-6
+
+	// Матрица поворота R = [[1, -rz, ry], [rz, 1, -rx], [-ry, rx, 1]]
+	r := [3][3]float64{
+		{1, -rz, ry},
+		{rz, 1, -rx},
+		{-ry, rx, 1},
+	}
+	inv, ok := invert3(r)
+	if !ok {
+		return c
+	}
+
+	x := (c.X - h.TX) / m
+	y := (c.Y - h.TY) / m
+	z := (c.Z - h.TZ) / m
+
+	return ECEF{
+		X: inv[0][0]*x + inv[0][1]*y + inv[0][2]*z,
+		Y: inv[1][0]*x + inv[1][1]*y + inv[1][2]*z,
+		Z: inv[2][0]*x + inv[2][1]*y + inv[2][2]*z,
+	}
+}
+
+// invert3 обращает матрицу 3x3
+func invert3(m [3][3]float64) ([3][3]float64, bool) {
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	if det == 0 {
+		return [3][3]float64{}, false
+	}
+
+	var inv [3][3]float64
+	inv[0][0] = (m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det
+	inv[0][1] = (m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det
+	inv[0][2] = (m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det
+	inv[1][0] = (m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det
+	inv[1][1] = (m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det
+	inv[1][2] = (m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det
+	inv[2][0] = (m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det
+	inv[2][1] = (m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det
+	inv[2][2] = (m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det
+	return inv, true
+}
@@ -0,0 +1,60 @@
+package datum
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Molodensky содержит параметры преобразования Молоденского между двумя эллипсоидами
+type Molodensky struct {
+	// DX, DY, DZ - смещения центра целевого эллипсоида относительно исходного (в метрах)
+	DX, DY, DZ float64
+	// Abridged - использовать сокращенные формулы Молоденского
+	Abridged bool
+}
+
+// Transform преобразует точку (долгота и широта в градусах, необязательная высота в метрах)
+// с эллипсоида from на эллипсоид to
+func (m Molodensky) Transform(from, to proj.Ellipsoid, p types.Point) types.Point {
+	a := from.A
+	f := from.F()
+	e2 := from.E2()
+	da := to.A - from.A
+	df := to.F() - from.F()
+
+	lon := p.GetLongitude() * math.Pi / 180.0
+	lat := p.GetLatitude() * math.Pi / 180.0
+	h := pointHeight(p)
+
+	sinLat, cosLat := math.Sin(lat), math.Cos(lat)
+	sinLon, cosLon := math.Sin(lon), math.Cos(lon)
+
+	w := math.Sqrt(1 - e2*sinLat*sinLat)
+	// Радиусы кривизны меридиана и первого вертикала
+	rm := a * (1 - e2) / (w * w * w)
+	rn := a / w
+
+	var dLat, dLon, dH float64
+	if m.Abridged {
+		k := a*df + f*da
+		dLat = (-m.DX*sinLat*cosLon - m.DY*sinLat*sinLon + m.DZ*cosLat + k*math.Sin(2*lat)) / rm
+		dLon = (-m.DX*sinLon + m.DY*cosLon) / (rn * cosLat)
+		dH = m.DX*cosLat*cosLon + m.DY*cosLat*sinLon + m.DZ*sinLat + k*sinLat*sinLat - da
+	} else {
+		b := from.B()
+		dLat = (-m.DX*sinLat*cosLon - m.DY*sinLat*sinLon + m.DZ*cosLat +
+			da*(rn*e2*sinLat*cosLat)/a +
+			df*(rm*a/b+rn*b/a)*sinLat*cosLat) / (rm + h)
+		dLon = (-m.DX*sinLon + m.DY*cosLon) / ((rn + h) * cosLat)
+		dH = m.DX*cosLat*cosLon + m.DY*cosLat*sinLon + m.DZ*sinLat -
+			da*a/rn + df*b/a*rn*sinLat*sinLat
+	}
+
+	return withHeight(p,
+		(lon+dLon)*180.0/math.Pi,
+		(lat+dLat)*180.0/math.Pi,
+		h+dH,
+	)
+}
@@ -0,0 +1,262 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// WeightedPoint - точка для агрегации по ячейкам сетки
+type WeightedPoint struct {
+	Point types.Point
+	// Weight - значение точки, по которому считаются sum, mean, min, max и перцентили
+	Weight float64
+	// Properties - свойства точки, по которым считается количество различных значений
+	Properties types.Properties
+}
+
+// AggregateOptions - параметры агрегации точек
+type AggregateOptions struct {
+	// Percentiles - перцентили значений от 0 до 100. Перцентиль 90 записывается в свойство p90,
+	// перцентиль 99.9 - в свойство p99_9. Для перцентилей значения точек хранятся в памяти
+	Percentiles []float64
+	// DistinctProperties - свойства точек, для которых считается количество различных значений.
+	// Результат для свойства courier_id записывается в distinct_courier_id
+	DistinctProperties []string
+	// SkipEmpty - не включать в результат ячейки без точек
+	SkipEmpty bool
+}
+
+// CellStats - статистика точек в ячейке
+type CellStats struct {
+	Count int
+	Sum   float64
+	Min   float64
+	Max   float64
+	// values - значения точек, хранятся только при заданных перцентилях
+	values []float64
+	// distinct - различные значения свойств по именам свойств
+	distinct map[string]map[string]struct{}
+}
+
+// Mean возвращает среднее значение точек ячейки или 0 для пустой ячейки
+func (s *CellStats) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Distinct возвращает количество различных значений свойства name
+func (s *CellStats) Distinct(name string) int {
+	return len(s.distinct[name])
+}
+
+// Percentile возвращает перцентиль p (от 0 до 100) значений ячейки с линейной интерполяцией
+// между соседними значениями. Возвращает false, если значения не сохранялись или ячейка пуста
+func (s *CellStats) Percentile(p float64) (float64, bool) {
+	if len(s.values) == 0 || p < 0 || p > 100 {
+		return 0, false
+	}
+	slices.Sort(s.values)
+
+	pos := p / 100 * float64(len(s.values)-1)
+	lower := int(math.Floor(pos))
+	if lower == len(s.values)-1 {
+		return s.values[lower], true
+	}
+	frac := pos - float64(lower)
+	return s.values[lower] + frac*(s.values[lower+1]-s.values[lower]), true
+}
+
+// Aggregator распределяет точки по ячейкам сетки и накапливает статистику.
+// Точки добавляются по одной, поэтому источник может быть потоком любой длины;
+// память зависит от количества непустых ячеек, а при заданных перцентилях - и от количества точек
+type Aggregator struct {
+	grid    Grid
+	opts    AggregateOptions
+	cells   map[CellID]*CellStats
+	dropped int
+}
+
+// NewAggregator создает агрегатор точек по ячейкам сетки g
+func NewAggregator(g Grid, opts AggregateOptions) (*Aggregator, error) {
+	for _, p := range opts.Percentiles {
+		if p < 0 || p > 100 || math.IsNaN(p) {
+			return nil, fmt.Errorf("invalid percentile %g: must be between 0 and 100", p)
+		}
+	}
+	return &Aggregator{grid: g, opts: opts, cells: make(map[CellID]*CellStats)}, nil
+}
+
+// Add добавляет точку. Возвращает false, если точка не попала ни в одну ячейку сетки
+func (a *Aggregator) Add(p WeightedPoint) bool {
+	id, ok := a.grid.Locate(p.Point)
+	if !ok {
+		a.dropped++
+		return false
+	}
+
+	s, ok := a.cells[id]
+	if !ok {
+		s = &CellStats{Min: p.Weight, Max: p.Weight}
+		a.cells[id] = s
+	}
+	s.Count++
+	s.Sum += p.Weight
+	s.Min = math.Min(s.Min, p.Weight)
+	s.Max = math.Max(s.Max, p.Weight)
+	if len(a.opts.Percentiles) > 0 {
+		s.values = append(s.values, p.Weight)
+	}
+
+	for _, name := range a.opts.DistinctProperties {
+		value, ok := p.Properties[name]
+		if !ok || value == nil {
+			continue
+		}
+		if s.distinct == nil {
+			s.distinct = make(map[string]map[string]struct{}, len(a.opts.DistinctProperties))
+		}
+		if s.distinct[name] == nil {
+			s.distinct[name] = make(map[string]struct{})
+		}
+		// Тип входит в ключ, чтобы число 1 и строка "1" считались разными значениями
+		s.distinct[name][fmt.Sprintf("%T:%v", value, value)] = struct{}{}
+	}
+	return true
+}
+
+// AddAll добавляет все точки последовательности и возвращает количество точек, попавших в сетку
+func (a *Aggregator) AddAll(points iter.Seq[WeightedPoint]) int {
+	n := 0
+	for p := range points {
+		if a.Add(p) {
+			n++
+		}
+	}
+	return n
+}
+
+// Dropped возвращает количество точек, не попавших в сетку
+func (a *Aggregator) Dropped() int {
+	return a.dropped
+}
+
+// Stats возвращает статистику ячейки. Возвращает false для ячейки без точек
+func (a *Aggregator) Stats(id CellID) (*CellStats, bool) {
+	s, ok := a.cells[id]
+	return s, ok
+}
+
+// Features возвращает ячейки сетки со статистикой в порядке генерации сетки. Кроме свойств
+// Features сетки, объект содержит count, sum, mean, min, max, перцентили и distinct_*.
+// У пустой ячейки count и sum равны 0, а mean, min, max и перцентили отсутствуют
+func (a *Aggregator) Features() *types.FeatureCollection {
+	fc := types.NewFeatureCollection()
+	if a.opts.SkipEmpty {
+		fc.Features = make([]types.Feature, 0, len(a.cells))
+	} else {
+		fc.Features = make([]types.Feature, 0, a.grid.Len())
+	}
+
+	for id := range a.grid.Cells() {
+		s, ok := a.cells[id]
+		if !ok && a.opts.SkipEmpty {
+			continue
+		}
+		if !ok {
+			s = &CellStats{}
+		}
+
+		cell, _ := a.grid.Boundary(id)
+		fc.Features = append(fc.Features, types.NewFeature(types.NewPolygonGeometry(cell), a.properties(id, s)))
+	}
+	return fc
+}
+
+// properties возвращает свойства объекта ячейки со статистикой
+func (a *Aggregator) properties(id CellID, s *CellStats) types.Properties {
+	props := gridCellProperties(a.grid, id)
+	props["count"] = s.Count
+	props["sum"] = s.Sum
+	if s.Count > 0 {
+		props["mean"] = s.Mean()
+		props["min"] = s.Min
+		props["max"] = s.Max
+		for _, p := range a.opts.Percentiles {
+			props[percentileProperty(p)], _ = s.Percentile(p)
+		}
+	}
+	for _, name := range a.opts.DistinctProperties {
+		props["distinct_"+name] = s.Distinct(name)
+	}
+	return props
+}
+
+// percentileProperty возвращает имя свойства перцентиля: p90, p99_9
+func percentileProperty(p float64) string {
+	return "p" + strings.ReplaceAll(strconv.FormatFloat(p, 'f', -1, 64), ".", "_")
+}
+
+// Aggregate распределяет точки по ячейкам сетки g и возвращает ячейки со статистикой
+// в виде набора объектов. Точки вне сетки пропускаются
+func Aggregate(g Grid, points iter.Seq[WeightedPoint], opts AggregateOptions) (*types.FeatureCollection, error) {
+	a, err := NewAggregator(g, opts)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate points: %w", err)
+	}
+	a.AddAll(points)
+	return a.Features(), nil
+}
+
+// FeaturePoints возвращает последовательность точек для агрегации из объектов с геометрией Point.
+// Значение точки берется из числового свойства weightProperty; если weightProperty пусто,
+// значение равно 1. Объекты другой геометрии и без числового значения пропускаются
+func FeaturePoints(fc *types.FeatureCollection, weightProperty string) iter.Seq[WeightedPoint] {
+	return func(yield func(WeightedPoint) bool) {
+		for _, f := range fc.Features {
+			point, ok := f.Geometry.Coordinates.(types.Point)
+			if f.Geometry.Type != types.GeometryPoint || !ok {
+				continue
+			}
+
+			weight := 1.0
+			if weightProperty != "" {
+				if weight, ok = numericValue(f.Properties[weightProperty]); !ok {
+					continue
+				}
+			}
+			if !yield(WeightedPoint{Point: point, Weight: weight, Properties: f.Properties}) {
+				return
+			}
+		}
+	}
+}
+
+// numericValue приводит числовое значение свойства к float64
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,201 @@
+package grid
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// aggregateTestGrid возвращает сетку 2x2 из ячеек в 1° у экватора и ячейки точек a и b
+func aggregateTestGrid(t *testing.T) (*RectangularGrid, CellID, CellID) {
+	t.Helper()
+	g, err := NewRectangularGrid(0, 0, 2, 2, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, okA := g.Locate(types.NewPoint(0.5, 0.5))
+	b, okB := g.Locate(types.NewPoint(1.5, 1.5))
+	if !okA || !okB || a == b {
+		t.Fatalf("test cells %v, %v are not located", a, b)
+	}
+	return g, a, b
+}
+
+func TestAggregatorStats(t *testing.T) {
+	g, a, b := aggregateTestGrid(t)
+	agg, err := NewAggregator(g, AggregateOptions{
+		Percentiles:        []float64{0, 50, 90, 99.9, 100},
+		DistinctProperties: []string{"courier_id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Число 1 и строка "1" - разные значения, nil и отсутствующее свойство не считаются
+	couriers := []any{1, "1", 1, nil, "2"}
+	for i, w := range []float64{5, 3, 1, 4, 2} {
+		props := types.Properties{}
+		if couriers[i] != nil {
+			props["courier_id"] = couriers[i]
+		}
+		if !agg.Add(WeightedPoint{Point: types.NewPoint(0.2+0.1*float64(i), 0.5), Weight: w, Properties: props}) {
+			t.Fatalf("point %d is not added", i)
+		}
+	}
+	agg.Add(WeightedPoint{Point: types.NewPoint(1.5, 1.5), Weight: -7})
+	if agg.Add(WeightedPoint{Point: types.NewPoint(10, 10), Weight: 100}) {
+		t.Error("point outside the grid is added")
+	}
+	if agg.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", agg.Dropped())
+	}
+
+	s, ok := agg.Stats(a)
+	if !ok {
+		t.Fatal("cell a has no stats")
+	}
+	if s.Count != 5 || s.Sum != 15 || s.Mean() != 3 || s.Min != 1 || s.Max != 5 {
+		t.Errorf("stats = %+v, mean %g", s, s.Mean())
+	}
+	if s.Distinct("courier_id") != 3 || s.Distinct("other") != 0 {
+		t.Errorf("distinct couriers = %d, want 3", s.Distinct("courier_id"))
+	}
+
+	// Значения 1..5: позиция перцентиля p равна p/100·4 с интерполяцией между соседями
+	percentiles := map[float64]float64{0: 1, 25: 2, 50: 3, 90: 4.6, 99.9: 4.996, 100: 5}
+	for p, want := range percentiles {
+		if got, ok := s.Percentile(p); !ok || math.Abs(got-want) > 1e-9 {
+			t.Errorf("Percentile(%g) = %g, %v, want %g", p, got, ok, want)
+		}
+	}
+	for _, p := range []float64{-1, 101} {
+		if _, ok := s.Percentile(p); ok {
+			t.Errorf("Percentile(%g) is ok", p)
+		}
+	}
+
+	single, _ := agg.Stats(b)
+	if got, ok := single.Percentile(75); !ok || got != -7 || single.Min != -7 || single.Max != -7 {
+		t.Errorf("single point cell: p75 = %g, %v, stats %+v", got, ok, single)
+	}
+	for id := range g.Cells() {
+		if _, ok := agg.Stats(id); ok != (id == a || id == b) {
+			t.Errorf("cell %v has stats: %v", id, ok)
+		}
+	}
+}
+
+func TestAggregatorFeatures(t *testing.T) {
+	g, a, _ := aggregateTestGrid(t)
+	points := []WeightedPoint{
+		{Point: types.NewPoint(0.5, 0.5), Weight: 2, Properties: types.Properties{"courier_id": 7}},
+		{Point: types.NewPoint(0.6, 0.6), Weight: 4, Properties: types.Properties{"courier_id": "7"}},
+		{Point: types.NewPoint(10, 10), Weight: 1},
+	}
+
+	fc, err := Aggregate(g, slices.Values(points), AggregateOptions{
+		Percentiles:        []float64{50, 99.9},
+		DistinctProperties: []string{"courier_id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != g.Len() {
+		t.Fatalf("%d features, want %d", len(fc.Features), g.Len())
+	}
+
+	for _, f := range fc.Features {
+		props := f.Properties
+		if props["cell_id"] != a.String() {
+			// У пустой ячейки есть только count, sum и distinct_*
+			if props["count"] != 0 || props["sum"] != 0.0 || props["distinct_courier_id"] != 0 {
+				t.Errorf("empty cell properties = %v", props)
+			}
+			for _, name := range []string{"mean", "min", "max", "p50", "p99_9"} {
+				if _, ok := props[name]; ok {
+					t.Errorf("empty cell has %s", name)
+				}
+			}
+			continue
+		}
+		want := types.Properties{
+			"cell_id": a.String(), "row": a.Row(), "col": a.Col(),
+			"count": 2, "sum": 6.0, "mean": 3.0, "min": 2.0, "max": 4.0,
+			"p50": 3.0, "p99_9": 3.998, "distinct_courier_id": 2,
+		}
+		for name, value := range want {
+			got, ok := props[name].(float64)
+			if ok && math.Abs(got-value.(float64)) < 1e-9 {
+				continue
+			}
+			if props[name] != value {
+				t.Errorf("%s = %v, want %v", name, props[name], value)
+			}
+		}
+	}
+
+	skipped, err := Aggregate(g, slices.Values(points), AggregateOptions{SkipEmpty: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped.Features) != 1 || skipped.Features[0].Properties["cell_id"] != a.String() {
+		t.Errorf("SkipEmpty features = %v", skipped.Features)
+	}
+	if _, ok := skipped.Features[0].Properties["p50"]; ok {
+		t.Error("percentile is written without Percentiles")
+	}
+}
+
+func TestAggregatorInvalidPercentile(t *testing.T) {
+	g, _, _ := aggregateTestGrid(t)
+	for _, p := range []float64{-0.1, 100.1, math.NaN()} {
+		if _, err := NewAggregator(g, AggregateOptions{Percentiles: []float64{p}}); err == nil {
+			t.Errorf("percentile %g is accepted", p)
+		}
+		if _, err := Aggregate(g, slices.Values([]WeightedPoint(nil)), AggregateOptions{Percentiles: []float64{p}}); err == nil {
+			t.Errorf("Aggregate accepts percentile %g", p)
+		}
+	}
+
+	// Без сохраненных значений перцентиль не вычисляется
+	agg, _ := NewAggregator(g, AggregateOptions{})
+	agg.Add(WeightedPoint{Point: types.NewPoint(0.5, 0.5), Weight: 1})
+	id, _ := g.Locate(types.NewPoint(0.5, 0.5))
+	s, ok := agg.Stats(id)
+	if !ok {
+		t.Fatal("cell has no stats")
+	}
+	if _, ok := s.Percentile(50); ok {
+		t.Error("percentile without stored values is ok")
+	}
+}
+
+func TestFeaturePoints(t *testing.T) {
+	fc := types.NewFeatureCollection(
+		types.NewFeature(types.NewPointGeometry(types.NewPoint(0.5, 0.5)), types.Properties{"weight": 2.5}),
+		types.NewFeature(types.NewPointGeometry(types.NewPoint(0.5, 0.5)), types.Properties{"weight": 3}),
+		types.NewFeature(types.NewPointGeometry(types.NewPoint(0.5, 0.5)), types.Properties{"weight": "4"}),
+		types.NewFeature(types.NewLineStringGeometry(types.NewLineString(types.NewPoint(0, 0), types.NewPoint(1, 1))), types.Properties{"weight": 1.0}),
+	)
+
+	var weights []float64
+	for p := range FeaturePoints(fc, "weight") {
+		weights = append(weights, p.Weight)
+	}
+	if !slices.Equal(weights, []float64{2.5, 3}) {
+		t.Errorf("weights = %v, want [2.5 3]", weights)
+	}
+
+	count := 0
+	for p := range FeaturePoints(fc, "") {
+		if p.Weight != 1 {
+			t.Errorf("weight without property = %g", p.Weight)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("%d points without weight property, want 3", count)
+	}
+}
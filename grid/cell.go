@@ -0,0 +1,48 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidGrid возвращается при создании сетки с некорректными границами или шагом
+var ErrInvalidGrid = errors.New("invalid grid parameters")
+
+// maxGridCells - наибольшее количество рядов и ячеек в ряду сетки, размер которой
+// вычисляется по размеру ячейки. Слишком малая ячейка дает ошибку, а не неограниченное выделение памяти
+const maxGridCells = 1 << 20
+
+// positiveFinite проверяет, что размер ячейки - конечное положительное число
+func positiveFinite(v float64) bool {
+	return v > 0 && !math.IsInf(v, 0)
+}
+
+// finitePoint проверяет, что координаты точки - конечные числа
+func finitePoint(lon, lat float64) bool {
+	return !math.IsNaN(lon) && !math.IsInf(lon, 0) && !math.IsNaN(lat) && !math.IsInf(lat, 0)
+}
+
+// CellID - идентификатор ячейки сетки: номер ряда в старших 32 битах, номер столбца в младших.
+// Ряды и столбцы нумеруются с нуля в порядке генерации ячеек
+type CellID uint64
+
+// NewCellID создает идентификатор ячейки по номерам ряда и столбца
+func NewCellID(row, col int) CellID {
+	return CellID(uint64(uint32(row))<<32 | uint64(uint32(col)))
+}
+
+// Row возвращает номер ряда ячейки
+func (id CellID) Row() int {
+	return int(uint32(id >> 32))
+}
+
+// Col возвращает номер столбца ячейки
+func (id CellID) Col() int {
+	return int(uint32(id))
+}
+
+// String возвращает идентификатор в виде "ряд/столбец"
+func (id CellID) String() string {
+	return fmt.Sprintf("%d/%d", id.Row(), id.Col())
+}
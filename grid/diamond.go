@@ -0,0 +1,223 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"sort"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// CreateDiamondGrid создает сетку ромбов с заданными параметрами
+// minLon, minLat - координаты юго-западного угла
+// maxLon, maxLat - координаты северо-восточного угла
+// width - горизонтальная диагональ ромба в градусах долготы
+func CreateDiamondGrid(minLon, minLat, maxLon, maxLat, width float64) types.MultiPolygon {
+	var M types.MultiPolygon
+
+	g, err := NewDiamondGrid(minLon, minLat, maxLon, maxLat, width)
+	if err != nil {
+		return M // Возвращаем пустую карту при некорректных границах
+	}
+	return g.MultiPolygon()
+}
+
+// DiamondGrid - сетка ромбов с горизонтальной и вертикальной диагоналями. Нечетные ряды
+// смещены на полдиагонали к западу и на полвысоты к северу, поэтому каждый ромб имеет
+// общие стороны с двумя ромбами предыдущего и двумя ромбами следующего ряда.
+// Вертикальная диагональ равна горизонтальной с поправкой на широту
+type DiamondGrid struct {
+	bounds calc.BoundingBox
+	width  float64
+	// levels - широты вершин: ромб ряда r имеет южную вершину на levels[r],
+	// западную и восточную - на levels[r+1], северную - на levels[r+2]
+	levels []float64
+	cols   int
+}
+
+// NewDiamondGrid создает сетку ромбов, покрывающую прямоугольник.
+// width - горизонтальная диагональ ромба в градусах долготы.
+// Границы ограничиваются допустимым диапазоном координат.
+// Возвращает ErrInvalidGrid, если рядов или ячеек в ряду получается больше 2^20
+func NewDiamondGrid(minLon, minLat, maxLon, maxLat, width float64) (*DiamondGrid, error) {
+	if !(minLat <= maxLat) || !(minLon <= maxLon) || !positiveFinite(width) {
+		return nil, fmt.Errorf("diamond grid [%g, %g, %g, %g] with width %g: %w",
+			minLon, minLat, maxLon, maxLat, width, ErrInvalidGrid)
+	}
+
+	// Ограничиваем широту до диапазона [-90, 90] и долготу до [-180, 180]
+	minLat, maxLat = max(minLat, -90), min(maxLat, 90)
+	minLon, maxLon = max(minLon, -180), min(maxLon, 180)
+
+	g := &DiamondGrid{
+		bounds: calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat},
+		width:  width,
+	}
+
+	// Западные и восточные вершины первого ряда лежат на южной границе, поэтому
+	// ряды покрывают прямоугольник по всей высоте, начиная с minLat
+	halfHeight := width / 2
+	g.levels = []float64{max(minLat-halfHeight*latitudeFactor(minLat), -90), minLat}
+	for lat := minLat; lat < maxLat; {
+		lat = min(lat+halfHeight*latitudeFactor(lat), 90)
+		g.levels = append(g.levels, lat)
+		if len(g.levels) > maxGridCells+1 {
+			return nil, fmt.Errorf("diamond grid [%g, %g, %g, %g] with width %g needs more than %d rows: %w",
+				minLon, minLat, maxLon, maxLat, width, maxGridCells, ErrInvalidGrid)
+		}
+	}
+	last := g.levels[len(g.levels)-1]
+	g.levels = append(g.levels, min(last+halfHeight*latitudeFactor(last), 90))
+
+	// Нечетные ряды начинаются на полдиагонали западнее границы
+	cols := math.Ceil((maxLon-minLon)/width+0.5) + 1
+	if cols > maxGridCells {
+		return nil, fmt.Errorf("diamond grid [%g, %g, %g, %g] with width %g needs %g cells in a row: %w",
+			minLon, minLat, maxLon, maxLat, width, cols, ErrInvalidGrid)
+	}
+	g.cols = int(cols)
+	return g, nil
+}
+
+// Bounds возвращает границы сетки после ограничения диапазоном координат
+func (g *DiamondGrid) Bounds() calc.BoundingBox {
+	return g.bounds
+}
+
+// Width возвращает горизонтальную диагональ ромбов в градусах долготы
+func (g *DiamondGrid) Width() float64 {
+	return g.width
+}
+
+// Rows возвращает количество рядов сетки
+func (g *DiamondGrid) Rows() int {
+	return len(g.levels) - 2
+}
+
+// Cols возвращает количество ромбов в ряду
+func (g *DiamondGrid) Cols() int {
+	return g.cols
+}
+
+// Len возвращает общее количество ячеек сетки
+func (g *DiamondGrid) Len() int {
+	return g.Rows() * g.cols
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *DiamondGrid) Contains(id CellID) bool {
+	return id.Row() < g.Rows() && id.Col() < g.cols
+}
+
+// centerLon возвращает долготу северной и южной вершин ромба
+func (g *DiamondGrid) centerLon(row, col int) float64 {
+	lon := g.bounds.MinLon + float64(col)*g.width
+	if row%2 == 1 {
+		lon -= g.width / 2
+	}
+	return lon
+}
+
+// Boundary возвращает ромб ячейки. Возвращает false, если ячейки нет в сетке
+func (g *DiamondGrid) Boundary(id CellID) (types.Polygon, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	row := id.Row()
+	lon := g.centerLon(row, id.Col())
+	return types.NewPolygon(types.NewLineString(
+		types.NewPoint(lon, g.levels[row]),
+		types.NewPoint(lon+g.width/2, g.levels[row+1]),
+		types.NewPoint(lon, g.levels[row+2]),
+		types.NewPoint(lon-g.width/2, g.levels[row+1]),
+		types.NewPoint(lon, g.levels[row]),
+	)), true
+}
+
+// Center возвращает точку пересечения диагоналей ромба. Возвращает false, если ячейки нет в сетке
+func (g *DiamondGrid) Center(id CellID) (types.Point, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	return types.NewPoint(g.centerLon(id.Row(), id.Col()), g.levels[id.Row()+1]), true
+}
+
+// Locate возвращает ячейку, ромб которой содержит точку. Точка на общей стороне
+// двух ромбов относится к ромбу, южная вершина которого севернее
+func (g *DiamondGrid) Locate(p types.Point) (CellID, bool) {
+	lon, lat := p.GetLongitude(), p.GetLatitude()
+	if lat < g.levels[0] || lat > g.levels[len(g.levels)-1] {
+		return 0, false
+	}
+
+	// Полоса между соседними уровнями: в ней лежат северные половины ромбов ряда level-1
+	// и южные половины ромбов ряда level
+	level := sort.Search(len(g.levels)-1, func(i int) bool { return g.levels[i+1] > lat })
+	if level == len(g.levels)-1 {
+		level-- // северная граница сетки
+	}
+	t := 0.0
+	if height := g.levels[level+1] - g.levels[level]; height > 0 {
+		t = (lat - g.levels[level]) / height
+	}
+
+	// В южной половине ромба ряда level полуширина на доле высоты t равна t·width/2
+	if level < g.Rows() {
+		u := (lon - g.centerLon(level, 0)) / g.width
+		col := math.Round(u)
+		if math.Abs(u-col) <= t/2 && col >= 0 && int(col) < g.cols {
+			return NewCellID(level, int(col)), true
+		}
+	}
+
+	// Иначе точка в северной половине ромба ряда level-1
+	if level == 0 {
+		return 0, false
+	}
+	u := (lon - g.centerLon(level-1, 0)) / g.width
+	col := math.Round(u)
+	if math.Abs(u-col) > (1-t)/2 || col < 0 || int(col) >= g.cols {
+		return 0, false
+	}
+	return NewCellID(level-1, int(col)), true
+}
+
+// Neighbors возвращает до четырех ячеек, имеющих с данной общую сторону:
+// по два ромба в предыдущем и следующем рядах
+func (g *DiamondGrid) Neighbors(id CellID) []CellID {
+	if !g.Contains(id) {
+		return nil
+	}
+	row, col := id.Row(), id.Col()
+
+	// Соседние ряды смещены на полдиагонали: к западу для четного ряда, к востоку для нечетного
+	west, east := col, col+1
+	if row%2 == 1 {
+		west, east = col-1, col
+	}
+
+	candidates := [][2]int{
+		{row - 1, west}, {row - 1, east},
+		{row + 1, west}, {row + 1, east},
+	}
+
+	result := make([]CellID, 0, len(candidates))
+	for _, rc := range candidates {
+		if rc[0] >= 0 && rc[0] < g.Rows() && rc[1] >= 0 && rc[1] < g.cols {
+			result = append(result, NewCellID(rc[0], rc[1]))
+		}
+	}
+	return result
+}
+
+// Cells перебирает ячейки сетки по рядам с юга на север
+func (g *DiamondGrid) Cells() iter.Seq[CellID] {
+	return rowMajor(g.Rows(), func(int) int { return g.cols })
+}
+
+// MultiPolygon возвращает все ячейки сетки в порядке рядов с юга на север
+func (g *DiamondGrid) MultiPolygon() types.MultiPolygon {
+	return Polygons(g)
+}
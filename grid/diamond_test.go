@@ -0,0 +1,41 @@
+package grid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDiamondGrid(t *testing.T) {
+	g, err := NewDiamondGrid(37, 55, 38, 56, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGrid(t, g, g.Bounds())
+
+	// Соседние ромбы имеют общую сторону
+	for id := range g.Cells() {
+		cell, _ := g.Boundary(id)
+		neighbors := g.Neighbors(id)
+		if len(neighbors) == 0 || len(neighbors) > 4 {
+			t.Fatalf("%v has %d neighbours", id, len(neighbors))
+		}
+		for _, n := range neighbors {
+			other, _ := g.Boundary(n)
+			if sharedVertices(cell, other) != 2 {
+				t.Fatalf("%v and %v do not share a side", id, n)
+			}
+		}
+	}
+}
+
+func TestDiamondGridInvalid(t *testing.T) {
+	for _, width := range []float64{0, -1, math.NaN(), math.Inf(1), 1e-9} {
+		if _, err := NewDiamondGrid(37, 55, 38, 56, width); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("width %g: error = %v, want ErrInvalidGrid", width, err)
+		}
+		if cells := CreateDiamondGrid(37, 55, 38, 56, width); len(cells) != 0 {
+			t.Errorf("width %g: %d cells, want none", width, len(cells))
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Площади ячеек равновеликих сеток вычисляются на сфере радиуса calc.EarthRadiusKm.
+// Отличие от площадей на эллипсоиде WGS 84 не превышает 0,7% и одинаково для ячеек одной широты
+
+// earthSphere - сфера, на которой строятся равновеликие сетки
+var earthSphere = proj.Ellipsoid{Name: "Sphere", A: calc.EarthRadiusMeters}
+
+// hexEdgeSegments - количество отрезков, которыми аппроксимируется сторона шестиугольника
+const hexEdgeSegments = 4
+
+// equalAreaRow - ряд равновеликой прямоугольной сетки
+type equalAreaRow struct {
+	minLat, maxLat float64
+	stepLon        float64
+	cols           int
+}
+
+// EqualAreaRectangularGrid - прямоугольная сетка из ячеек одинаковой площади.
+// Ряды имеют одинаковую высоту в метрах, шаг по долготе ряда выбирается так, чтобы
+// R²·Δλ·(sin φ2 − sin φ1) равнялось заданной площади. Ячейки покрывают исходный прямоугольник
+// целиком и могут выходить за его восточную и северную границы.
+// Площадь всех ячеек равна заданной с точностью вычислений, кроме двух случаев:
+// ряд, охватывающий все 360° долготы, делится на целое число ячеек (отклонение не больше 1/(2n)
+// для n ячеек в ряду), а ряд у полюса обрезается по полюсу. Фактическая площадь возвращает CellArea
+type EqualAreaRectangularGrid struct {
+	bounds   calc.BoundingBox
+	areaKm2  float64
+	stepLat  float64
+	rows     []equalAreaRow
+	fullTurn bool
+}
+
+// NewEqualAreaRectangularGrid создает прямоугольную сетку с площадью ячеек cellAreaKm2 в квадратных километрах.
+// Возвращает ErrInvalidGrid, если рядов или ячеек в ряду получается больше 2^20
+func NewEqualAreaRectangularGrid(minLon, minLat, maxLon, maxLat, cellAreaKm2 float64) (*EqualAreaRectangularGrid, error) {
+	if !(minLon < maxLon) || !(minLat < maxLat) || minLat < -90 || maxLat > 90 || !positiveFinite(cellAreaKm2) {
+		return nil, fmt.Errorf("equal-area grid [%g, %g, %g, %g] with cell area %g km²: %w",
+			minLon, minLat, maxLon, maxLat, cellAreaKm2, ErrInvalidGrid)
+	}
+
+	g := &EqualAreaRectangularGrid{
+		bounds:   calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat},
+		areaKm2:  cellAreaKm2,
+		fullTurn: maxLon-minLon >= 360,
+	}
+
+	// Высота ряда - сторона квадрата заданной площади
+	g.stepLat = math.Sqrt(cellAreaKm2) / calc.EarthRadiusKm * 180 / math.Pi
+	rows := math.Ceil((maxLat - minLat) / g.stepLat)
+	if rows > maxGridCells {
+		return nil, fmt.Errorf("equal-area grid [%g, %g, %g, %g] with cell area %g km² needs %g rows: %w",
+			minLon, minLat, maxLon, maxLat, cellAreaKm2, rows, ErrInvalidGrid)
+	}
+	width := maxLon - minLon
+	if g.fullTurn {
+		width = 360
+	}
+
+	g.rows = make([]equalAreaRow, int(rows))
+	for r := range g.rows {
+		row := equalAreaRow{
+			minLat: minLat + float64(r)*g.stepLat,
+			maxLat: min(minLat+float64(r+1)*g.stepLat, 90),
+		}
+
+		band := math.Sin(row.maxLat*math.Pi/180) - math.Sin(row.minLat*math.Pi/180)
+		row.stepLon = math.Min(cellAreaKm2/(calc.EarthRadiusKm*calc.EarthRadiusKm*band)*180/math.Pi, 360)
+		if g.fullTurn {
+			row.cols = max(int(math.Round(360/row.stepLon)), 1)
+			row.stepLon = 360 / float64(row.cols)
+		} else {
+			row.cols = max(int(math.Ceil(width/row.stepLon)), 1)
+		}
+		if row.cols > maxGridCells {
+			return nil, fmt.Errorf("equal-area grid [%g, %g, %g, %g] with cell area %g km² needs %d cells in row %d: %w",
+				minLon, minLat, maxLon, maxLat, cellAreaKm2, row.cols, r, ErrInvalidGrid)
+		}
+		g.rows[r] = row
+	}
+	return g, nil
+}
+
+// NewMetricRectangularGrid создает равновеликую прямоугольную сетку из ячеек площадью
+// cellSizeMeters x cellSizeMeters. Высота ряда равна cellSizeMeters, ширина ячейки
+// на средней широте ряда близка к ней
+func NewMetricRectangularGrid(minLon, minLat, maxLon, maxLat, cellSizeMeters float64) (*EqualAreaRectangularGrid, error) {
+	if !positiveFinite(cellSizeMeters) {
+		return nil, fmt.Errorf("metric grid with cell size %g m: %w", cellSizeMeters, ErrInvalidGrid)
+	}
+	return NewEqualAreaRectangularGrid(minLon, minLat, maxLon, maxLat, cellSizeMeters*cellSizeMeters/1e6)
+}
+
+// Bounds возвращает исходные границы сетки
+func (g *EqualAreaRectangularGrid) Bounds() calc.BoundingBox {
+	return g.bounds
+}
+
+// Len возвращает общее количество ячеек сетки
+func (g *EqualAreaRectangularGrid) Len() int {
+	n := 0
+	for _, row := range g.rows {
+		n += row.cols
+	}
+	return n
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *EqualAreaRectangularGrid) Contains(id CellID) bool {
+	return id.Row() < len(g.rows) && id.Col() < g.rows[id.Row()].cols
+}
+
+// Cells перебирает ячейки сетки по рядам с юга на север
+func (g *EqualAreaRectangularGrid) Cells() iter.Seq[CellID] {
+	return rowMajor(len(g.rows), func(r int) int { return g.rows[r].cols })
+}
+
+// cellBounds возвращает границы ячейки
+func (g *EqualAreaRectangularGrid) cellBounds(id CellID) (calc.BoundingBox, bool) {
+	if !g.Contains(id) {
+		return calc.BoundingBox{}, false
+	}
+	row := g.rows[id.Row()]
+	lon := g.bounds.MinLon + float64(id.Col())*row.stepLon
+	return calc.BoundingBox{MinLon: lon, MinLat: row.minLat, MaxLon: lon + row.stepLon, MaxLat: row.maxLat}, true
+}
+
+// Boundary возвращает полигон ячейки. Возвращает false, если ячейки нет в сетке
+func (g *EqualAreaRectangularGrid) Boundary(id CellID) (types.Polygon, bool) {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return nil, false
+	}
+	return boxPolygon(box), true
+}
+
+// Center возвращает центр ячейки. Возвращает false, если ячейки нет в сетке
+func (g *EqualAreaRectangularGrid) Center(id CellID) (types.Point, bool) {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return nil, false
+	}
+	return types.NewPoint((box.MinLon+box.MaxLon)/2, (box.MinLat+box.MaxLat)/2), true
+}
+
+// CellArea возвращает площадь ячейки в квадратных километрах
+func (g *EqualAreaRectangularGrid) CellArea(id CellID) (float64, bool) {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return 0, false
+	}
+	return boxAreaKm2(box), true
+}
+
+// Locate возвращает ячейку, содержащую точку. Ячейка включает западную и южную границы
+func (g *EqualAreaRectangularGrid) Locate(p types.Point) (CellID, bool) {
+	lon, lat := p.GetLongitude(), p.GetLatitude()
+	if g.fullTurn {
+		lon = g.bounds.MinLon + math.Mod(math.Mod(lon-g.bounds.MinLon, 360)+360, 360)
+	}
+	if lon < g.bounds.MinLon || lat < g.bounds.MinLat {
+		return 0, false
+	}
+
+	r := int((lat - g.bounds.MinLat) / g.stepLat)
+	if r >= len(g.rows) {
+		// Северная граница последнего ряда относится к нему
+		if r > len(g.rows) || lat > g.rows[len(g.rows)-1].maxLat {
+			return 0, false
+		}
+		r--
+	}
+	row := g.rows[r]
+	c := int((lon - g.bounds.MinLon) / row.stepLon)
+	if c >= row.cols {
+		if c > row.cols || lon > g.bounds.MinLon+float64(row.cols)*row.stepLon {
+			return 0, false
+		}
+		c--
+	}
+	return NewCellID(r, c), true
+}
+
+// Neighbors возвращает ячейки, имеющие с данной общую сторону или угол. Ряд, охватывающий
+// все 360° долготы, замыкается через антимеридиан
+func (g *EqualAreaRectangularGrid) Neighbors(id CellID) []CellID {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return nil
+	}
+
+	var result []CellID
+	for r := id.Row() - 1; r <= id.Row()+1; r++ {
+		if r < 0 || r >= len(g.rows) {
+			continue
+		}
+		row := g.rows[r]
+
+		// Допуск нужен, чтобы ячейки, касающиеся углами, находили друг друга взаимно
+		const eps = 1e-9
+		first := int(math.Ceil((box.MinLon-g.bounds.MinLon)/row.stepLon-eps)) - 1
+		last := int(math.Floor((box.MaxLon-g.bounds.MinLon)/row.stepLon + eps))
+		seen := make(map[int]bool, last-first+1)
+		for c := first; c <= last; c++ {
+			col := c
+			if g.fullTurn {
+				col = (c%row.cols + row.cols) % row.cols
+			}
+			if col < 0 || col >= row.cols || seen[col] || (r == id.Row() && col == id.Col()) {
+				continue
+			}
+			seen[col] = true
+			result = append(result, NewCellID(r, col))
+		}
+	}
+	return result
+}
+
+// EqualAreaHexagonalGrid - сетка правильных шестиугольников одинаковой площади, построенная
+// в равновеликой азимутальной проекции Ламберта с центром в центре прямоугольника.
+// Проекция сохраняет площади, поэтому площадь каждой ячейки на сфере равна заданной
+// с точностью вычислений; форма ячеек искажается с удалением от центра (на 1000 км -
+// примерно на 2%). Стороны шестиугольников аппроксимируются несколькими отрезками.
+// Нечетные ряды смещены на полширины ячейки к востоку
+type EqualAreaHexagonalGrid struct {
+	bounds     calc.BoundingBox
+	projection *proj.LambertAzimuthalEqualArea
+	areaKm2    float64
+	// side - сторона шестиугольника в метрах
+	side   float64
+	x0, y0 float64
+	rows   int
+	cols   int
+}
+
+// NewEqualAreaHexagonalGrid создает гексагональную сетку с площадью ячеек cellAreaKm2
+// в квадратных километрах, покрывающую прямоугольник.
+// Возвращает ErrInvalidGrid, если рядов или столбцов получается больше 2^20
+func NewEqualAreaHexagonalGrid(minLon, minLat, maxLon, maxLat, cellAreaKm2 float64) (*EqualAreaHexagonalGrid, error) {
+	if !(minLon < maxLon) || !(minLat < maxLat) || minLat < -90 || maxLat > 90 || maxLon-minLon >= 180 || !positiveFinite(cellAreaKm2) {
+		return nil, fmt.Errorf("equal-area hexagonal grid [%g, %g, %g, %g] with cell area %g km²: %w",
+			minLon, minLat, maxLon, maxLat, cellAreaKm2, ErrInvalidGrid)
+	}
+
+	projection, err := proj.NewLambertAzimuthalEqualArea(proj.AzimuthalParams{
+		Ellipsoid: earthSphere,
+		CenterLon: (minLon + maxLon) / 2,
+		CenterLat: (minLat + maxLat) / 2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("equal-area hexagonal grid: %w", err)
+	}
+
+	g := &EqualAreaHexagonalGrid{
+		bounds:     calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat},
+		projection: projection,
+		areaKm2:    cellAreaKm2,
+		// Площадь правильного шестиугольника со стороной s равна 3√3/2·s²
+		side: math.Sqrt(2 * cellAreaKm2 * 1e6 / (3 * math.Sqrt(3))),
+	}
+
+	// Границы прямоугольника в проекции: стороны прямоугольника искривляются,
+	// поэтому берем крайние значения по точкам на всех четырех сторонах
+	const samples = 64
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i := 0; i <= samples; i++ {
+		t := float64(i) / samples
+		lon := minLon + t*(maxLon-minLon)
+		lat := minLat + t*(maxLat-minLat)
+		for _, p := range [4]types.Point{
+			types.NewPoint(lon, minLat), types.NewPoint(lon, maxLat),
+			types.NewPoint(minLon, lat), types.NewPoint(maxLon, lat),
+		} {
+			xy, err := projection.Forward(p)
+			if err != nil {
+				return nil, fmt.Errorf("equal-area hexagonal grid: %w", err)
+			}
+			minX, maxX = math.Min(minX, xy[0]), math.Max(maxX, xy[0])
+			minY, maxY = math.Min(minY, xy[1]), math.Max(maxY, xy[1])
+		}
+	}
+
+	g.x0, g.y0 = minX, minY
+	cols := math.Ceil((maxX-minX)/g.width()) + 1
+	rows := math.Ceil((maxY-minY)/(1.5*g.side)) + 1
+	if cols > maxGridCells || rows > maxGridCells {
+		return nil, fmt.Errorf("equal-area hexagonal grid [%g, %g, %g, %g] with cell area %g km² needs %g x %g cells: %w",
+			minLon, minLat, maxLon, maxLat, cellAreaKm2, rows, cols, ErrInvalidGrid)
+	}
+	g.cols, g.rows = int(cols), int(rows)
+	return g, nil
+}
+
+// NewMetricHexagonalGrid создает равновеликую гексагональную сетку из шестиугольников
+// со стороной (радиусом описанной окружности) radiusMeters
+func NewMetricHexagonalGrid(minLon, minLat, maxLon, maxLat, radiusMeters float64) (*EqualAreaHexagonalGrid, error) {
+	if !positiveFinite(radiusMeters) {
+		return nil, fmt.Errorf("metric hexagonal grid with radius %g m: %w", radiusMeters, ErrInvalidGrid)
+	}
+	return NewEqualAreaHexagonalGrid(minLon, minLat, maxLon, maxLat, 3*math.Sqrt(3)/2*radiusMeters*radiusMeters/1e6)
+}
+
+// width возвращает ширину шестиугольника между параллельными сторонами в метрах
+func (g *EqualAreaHexagonalGrid) width() float64 {
+	return math.Sqrt(3) * g.side
+}
+
+// Bounds возвращает исходные границы сетки
+func (g *EqualAreaHexagonalGrid) Bounds() calc.BoundingBox {
+	return g.bounds
+}
+
+// Len возвращает общее количество ячеек сетки
+func (g *EqualAreaHexagonalGrid) Len() int {
+	return g.rows * g.cols
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *EqualAreaHexagonalGrid) Contains(id CellID) bool {
+	return id.Row() < g.rows && id.Col() < g.cols
+}
+
+// Cells перебирает ячейки сетки по рядам с юга на север
+func (g *EqualAreaHexagonalGrid) Cells() iter.Seq[CellID] {
+	return rowMajor(g.rows, func(int) int { return g.cols })
+}
+
+// center возвращает центр ячейки в координатах проекции
+func (g *EqualAreaHexagonalGrid) center(row, col int) (float64, float64) {
+	x := g.x0 + float64(col)*g.width()
+	if row%2 == 1 {
+		x += g.width() / 2
+	}
+	return x, g.y0 + float64(row)*1.5*g.side
+}
+
+// Boundary возвращает шестиугольник ячейки. Возвращает false, если ячейки нет в сетке
+func (g *EqualAreaHexagonalGrid) Boundary(id CellID) (types.Polygon, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	cx, cy := g.center(id.Row(), id.Col())
+
+	ring := make(types.LineString, 0, 6*hexEdgeSegments+1)
+	for i := 0; i < 6; i++ {
+		// Вершины шестиугольника с вершиной на севере, против часовой стрелки
+		a0 := math.Pi/6 + float64(i)*math.Pi/3
+		a1 := a0 + math.Pi/3
+		x0, y0 := cx+g.side*math.Cos(a0), cy+g.side*math.Sin(a0)
+		x1, y1 := cx+g.side*math.Cos(a1), cy+g.side*math.Sin(a1)
+		for k := 0; k < hexEdgeSegments; k++ {
+			t := float64(k) / hexEdgeSegments
+			p, err := g.projection.Inverse(types.NewPoint(x0+t*(x1-x0), y0+t*(y1-y0)))
+			if err != nil {
+				return nil, false
+			}
+			ring = append(ring, p)
+		}
+	}
+	ring = append(ring, ring[0])
+	return types.NewPolygon(ring), true
+}
+
+// Center возвращает центр ячейки. Возвращает false, если ячейки нет в сетке
+func (g *EqualAreaHexagonalGrid) Center(id CellID) (types.Point, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	cx, cy := g.center(id.Row(), id.Col())
+	p, err := g.projection.Inverse(types.NewPoint(cx, cy))
+	if err != nil {
+		return nil, false
+	}
+	return p, true
+}
+
+// CellArea возвращает площадь ячейки в квадратных километрах
+func (g *EqualAreaHexagonalGrid) CellArea(id CellID) (float64, bool) {
+	if !g.Contains(id) {
+		return 0, false
+	}
+	return g.areaKm2, true
+}
+
+// Locate возвращает ячейку, содержащую точку: точка проецируется и округляется
+// до ближайшего центра шестиугольной решетки
+func (g *EqualAreaHexagonalGrid) Locate(p types.Point) (CellID, bool) {
+	xy, err := g.projection.Forward(p)
+	if err != nil {
+		return 0, false
+	}
+
+	// Осевые координаты решетки с началом в центре ячейки 0/0
+	x, y := xy[0]-g.x0, xy[1]-g.y0
+	q := (math.Sqrt(3)/3*x - y/3) / g.side
+	r := 2.0 / 3 * y / g.side
+
+	// Округление в кубических координатах
+	cx, cz := q, r
+	cy := -cx - cz
+	rx, ry, rz := math.Round(cx), math.Round(cy), math.Round(cz)
+	dx, dy, dz := math.Abs(rx-cx), math.Abs(ry-cy), math.Abs(rz-cz)
+	if dx > dy && dx > dz {
+		rx = -ry - rz
+	} else if dy <= dz {
+		rz = -rx - ry
+	}
+
+	row := int(rz)
+	col := int(rx) + (row-(row&1))/2
+	if row < 0 || col < 0 || row >= g.rows || col >= g.cols {
+		return 0, false
+	}
+	return NewCellID(row, col), true
+}
+
+// Neighbors возвращает до шести ячеек, имеющих с данной общую сторону
+func (g *EqualAreaHexagonalGrid) Neighbors(id CellID) []CellID {
+	if !g.Contains(id) {
+		return nil
+	}
+	row, col := id.Row(), id.Col()
+
+	// Смещения соседей для четных и нечетных рядов (нечетные сдвинуты к востоку)
+	offsets := [2][6][2]int{
+		{{0, -1}, {0, 1}, {-1, -1}, {-1, 0}, {1, -1}, {1, 0}},
+		{{0, -1}, {0, 1}, {-1, 0}, {-1, 1}, {1, 0}, {1, 1}},
+	}
+
+	result := make([]CellID, 0, 6)
+	for _, d := range offsets[row&1] {
+		r, c := row+d[0], col+d[1]
+		if r >= 0 && c >= 0 && r < g.rows && c < g.cols {
+			result = append(result, NewCellID(r, c))
+		}
+	}
+	return result
+}
+
+// boxAreaKm2 вычисляет площадь прямоугольника долгота/широта на сфере в квадратных километрах
+func boxAreaKm2(box calc.BoundingBox) float64 {
+	band := math.Sin(box.MaxLat*math.Pi/180) - math.Sin(box.MinLat*math.Pi/180)
+	return calc.EarthRadiusKm * calc.EarthRadiusKm * (box.MaxLon - box.MinLon) * math.Pi / 180 * band
+}
+
+// equalAreaKm2 вычисляет площадь полигонов на сфере в квадратных километрах по формуле площади
+// в цилиндрической равновеликой проекции. Для ребер вдоль меридианов и параллелей результат точен,
+// для остальных ребер погрешность мала, если ребра короткие
+func equalAreaKm2(mp types.MultiPolygon) float64 {
+	total := 0.0
+	for _, polygon := range mp {
+		for i, ring := range polygon {
+			area := 0.0
+			for a, b := len(ring)-1, 0; b < len(ring); a, b = b, b+1 {
+				x1, y1 := ring[a].GetLongitude()*math.Pi/180, math.Sin(ring[a].GetLatitude()*math.Pi/180)
+				x2, y2 := ring[b].GetLongitude()*math.Pi/180, math.Sin(ring[b].GetLatitude()*math.Pi/180)
+				area += x1*y2 - x2*y1
+			}
+			area = math.Abs(area) / 2 * calc.EarthRadiusKm * calc.EarthRadiusKm
+			if i == 0 {
+				total += area
+			} else {
+				total -= area
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,76 @@
+package grid
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestEqualAreaInvalidParameters(t *testing.T) {
+	for _, area := range []float64{0, -1, math.NaN(), math.Inf(1), math.Inf(-1), 1e-12} {
+		if _, err := NewEqualAreaRectangularGrid(30, 40, 40, 70, area); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("rectangular grid with area %g: err = %v", area, err)
+		}
+		if _, err := NewEqualAreaHexagonalGrid(30, 40, 40, 70, area); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("hexagonal grid with area %g: err = %v", area, err)
+		}
+	}
+	for _, size := range []float64{0, -1, math.NaN(), math.Inf(1), 1e-6} {
+		if _, err := NewMetricRectangularGrid(30, 40, 40, 70, size); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("metric rectangular grid with size %g: err = %v", size, err)
+		}
+		if _, err := NewMetricHexagonalGrid(30, 40, 40, 70, size); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("metric hexagonal grid with radius %g: err = %v", size, err)
+		}
+	}
+	if _, err := NewEqualAreaRectangularGrid(math.NaN(), 40, 40, 70, 100); !errors.Is(err, ErrInvalidGrid) {
+		t.Errorf("rectangular grid with NaN bounds: err = %v", err)
+	}
+}
+
+// checkAreaGrid проверяет площади ячеек и то, что ячейка, найденная по центру, совпадает с исходной
+func checkAreaGrid(t *testing.T, name string, g AreaGrid, wantKm2, tolerance float64) {
+	t.Helper()
+	if g.Len() == 0 {
+		t.Fatalf("%s: empty grid", name)
+	}
+	for id := range g.Cells() {
+		area, ok := g.CellArea(id)
+		if !ok || math.Abs(area-wantKm2) > tolerance*wantKm2 {
+			t.Errorf("%s: cell %v area %g km², want %g", name, id, area, wantKm2)
+		}
+		cell, _ := g.Boundary(id)
+		if measured := equalAreaKm2(types.MultiPolygon{cell}); math.Abs(measured-area) > 0.01*area {
+			t.Errorf("%s: cell %v polygon area %g km², reported %g", name, id, measured, area)
+		}
+		center, _ := g.Center(id)
+		if got, ok := g.Locate(center); !ok || got != id {
+			t.Errorf("%s: centre of %v is located in %v, %v", name, id, got, ok)
+		}
+	}
+}
+
+func TestEqualAreaRectangularGrid(t *testing.T) {
+	g, err := NewEqualAreaRectangularGrid(30, 40, 40, 70, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ряд у северной границы может выходить за нее, но площадь ячеек сохраняется
+	checkAreaGrid(t, "rectangular", g, 2500, 1e-9)
+
+	m, err := NewMetricRectangularGrid(37, 55, 38, 56, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAreaGrid(t, "metric rectangular", m, 25, 1e-9)
+}
+
+func TestEqualAreaHexagonalGrid(t *testing.T) {
+	g, err := NewEqualAreaHexagonalGrid(30, 40, 40, 50, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAreaGrid(t, "hexagonal", g, 1000, 1e-9)
+}
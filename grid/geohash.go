@@ -0,0 +1,289 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// geohashAlphabet - алфавит base32, используемый в geohash
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeohashMaxPrecision - максимальная длина geohash (60 бит, точность порядка сантиметров)
+const GeohashMaxPrecision = 12
+
+// ErrInvalidGeohash возвращается при разборе строки, не являющейся корректным geohash
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+// GeohashDirection - направление на соседнюю ячейку geohash
+type GeohashDirection int
+
+const (
+	GeohashNorth GeohashDirection = iota
+	GeohashNorthEast
+	GeohashEast
+	GeohashSouthEast
+	GeohashSouth
+	GeohashSouthWest
+	GeohashWest
+	GeohashNorthWest
+)
+
+// directionOffsets - смещения соседних ячеек в размерах ячейки (по долготе, по широте)
+var directionOffsets = [8][2]float64{
+	GeohashNorth:     {0, 1},
+	GeohashNorthEast: {1, 1},
+	GeohashEast:      {1, 0},
+	GeohashSouthEast: {1, -1},
+	GeohashSouth:     {0, -1},
+	GeohashSouthWest: {-1, -1},
+	GeohashWest:      {-1, 0},
+	GeohashNorthWest: {-1, 1},
+}
+
+// EncodeGeohash кодирует точку в geohash заданной длины.
+// Длина ограничивается диапазоном [1, GeohashMaxPrecision]
+func EncodeGeohash(p types.Point, precision int) string {
+	precision = max(1, min(precision, GeohashMaxPrecision))
+
+	lon := p.GetLongitude()
+	lat := p.GetLatitude()
+	minLon, maxLon := -180.0, 180.0
+	minLat, maxLat := -90.0, 90.0
+
+	var sb strings.Builder
+	sb.Grow(precision)
+
+	// Биты долготы и широты чередуются, начиная с долготы
+	even := true
+	bits, ch := 0, 0
+	for sb.Len() < precision {
+		ch <<= 1
+		if even {
+			mid := (minLon + maxLon) / 2
+			if lon >= mid {
+				ch |= 1
+				minLon = mid
+			} else {
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch |= 1
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		bits++
+		if bits == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// GeohashBounds возвращает границы ячейки geohash
+func GeohashBounds(hash string) (calc.BoundingBox, error) {
+	if hash == "" {
+		return calc.BoundingBox{}, fmt.Errorf("empty string: %w", ErrInvalidGeohash)
+	}
+	if len(hash) > GeohashMaxPrecision {
+		return calc.BoundingBox{}, fmt.Errorf("%q longer than %d characters: %w", hash, GeohashMaxPrecision, ErrInvalidGeohash)
+	}
+
+	box := calc.BoundingBox{MinLon: -180, MinLat: -90, MaxLon: 180, MaxLat: 90}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(geohashAlphabet, lowerASCII(hash[i]))
+		if idx < 0 {
+			return calc.BoundingBox{}, fmt.Errorf("unexpected character %q in %q: %w", hash[i], hash, ErrInvalidGeohash)
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := idx>>bit&1 == 1
+			if even {
+				mid := (box.MinLon + box.MaxLon) / 2
+				if set {
+					box.MinLon = mid
+				} else {
+					box.MaxLon = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if set {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box, nil
+}
+
+// DecodeGeohash возвращает центр ячейки geohash
+func DecodeGeohash(hash string) (types.Point, error) {
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPoint((box.MinLon+box.MaxLon)/2, (box.MinLat+box.MaxLat)/2), nil
+}
+
+// GeohashPolygon возвращает ячейку geohash в виде полигона
+func GeohashPolygon(hash string) (types.Polygon, error) {
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		return nil, err
+	}
+	return boxPolygon(box), nil
+}
+
+// GeohashNeighbor возвращает соседнюю ячейку той же длины в заданном направлении.
+// По долготе соседи продолжаются через 180-й меридиан; за полюсом соседа нет,
+// в этом случае возвращается пустая строка
+func GeohashNeighbor(hash string, dir GeohashDirection) (string, error) {
+	if dir < GeohashNorth || dir > GeohashNorthWest {
+		return "", fmt.Errorf("unknown direction %d", dir)
+	}
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		return "", err
+	}
+	return neighborOf(box, len(hash), dir), nil
+}
+
+// GeohashNeighbors возвращает 8 соседних ячеек в порядке направлений GeohashDirection: от северной
+// по часовой стрелке до северо-западной. Отсутствующие за полюсом соседи равны пустой строке
+func GeohashNeighbors(hash string) ([8]string, error) {
+	var result [8]string
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		return result, err
+	}
+	for dir := GeohashNorth; dir <= GeohashNorthWest; dir++ {
+		result[dir] = neighborOf(box, len(hash), dir)
+	}
+	return result, nil
+}
+
+// GeohashCover возвращает минимальный набор geohash разной длины (не длиннее maxPrecision),
+// покрывающий полигон. Ячейки, целиком лежащие внутри полигона, не дробятся;
+// ячейки на границе дробятся до maxPrecision. Если все 32 дочерние ячейки входят в покрытие,
+// они заменяются родительской. Вычисления выполняются на плоскости долгота/широта
+func GeohashCover(polygon types.Polygon, maxPrecision int) []string {
+	if len(polygon) == 0 || len(polygon[0]) < 3 {
+		return nil
+	}
+	maxPrecision = max(1, min(maxPrecision, GeohashMaxPrecision))
+
+	// Габариты внешнего контура для отсечения заведомо лишних ячеек
+	bounds := calc.BoundingBox{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)}
+	for _, p := range polygon[0] {
+		bounds.MinLon = math.Min(bounds.MinLon, p.GetLongitude())
+		bounds.MinLat = math.Min(bounds.MinLat, p.GetLatitude())
+		bounds.MaxLon = math.Max(bounds.MaxLon, p.GetLongitude())
+		bounds.MaxLat = math.Max(bounds.MaxLat, p.GetLatitude())
+	}
+
+	c := &geohashCoverer{
+		region:       calc.NewPreparedPolygon(polygon),
+		bounds:       bounds,
+		maxPrecision: maxPrecision,
+	}
+
+	var result []string
+	for i := 0; i < len(geohashAlphabet); i++ {
+		result = append(result, c.cover(geohashAlphabet[i:i+1])...)
+	}
+	return result
+}
+
+// geohashCoverer хранит состояние построения покрытия полигона
+type geohashCoverer struct {
+	region       *calc.PreparedPolygon
+	bounds       calc.BoundingBox
+	maxPrecision int
+}
+
+// cover возвращает покрытие части полигона, попадающей в ячейку hash
+func (c *geohashCoverer) cover(hash string) []string {
+	box, _ := GeohashBounds(hash)
+	if box.MaxLon < c.bounds.MinLon || box.MinLon > c.bounds.MaxLon ||
+		box.MaxLat < c.bounds.MinLat || box.MinLat > c.bounds.MaxLat {
+		return nil
+	}
+
+	cell := boxPolygon(box)
+	if !c.region.IntersectsPolygon(cell) {
+		return nil
+	}
+	if len(hash) == c.maxPrecision || c.region.ContainsPolygon(cell) {
+		return []string{hash}
+	}
+
+	var result []string
+	whole := 0
+	for i := 0; i < len(geohashAlphabet); i++ {
+		child := hash + geohashAlphabet[i:i+1]
+		sub := c.cover(child)
+		if len(sub) == 1 && sub[0] == child {
+			whole++
+		}
+		result = append(result, sub...)
+	}
+
+	// Все дочерние ячейки вошли целиком - достаточно родительской
+	if whole == len(geohashAlphabet) {
+		return []string{hash}
+	}
+	return result
+}
+
+// neighborOf вычисляет соседа ячейки с границами box
+func neighborOf(box calc.BoundingBox, precision int, dir GeohashDirection) string {
+	width := box.MaxLon - box.MinLon
+	height := box.MaxLat - box.MinLat
+
+	lat := (box.MinLat+box.MaxLat)/2 + directionOffsets[dir][1]*height
+	if lat > 90 || lat < -90 {
+		return ""
+	}
+
+	lon := (box.MinLon+box.MaxLon)/2 + directionOffsets[dir][0]*width
+	if lon > 180 {
+		lon -= 360
+	} else if lon < -180 {
+		lon += 360
+	}
+	return EncodeGeohash(types.NewPoint(lon, lat), precision)
+}
+
+// boxPolygon строит полигон по прямоугольнику в порядке обхода против часовой стрелки
+func boxPolygon(box calc.BoundingBox) types.Polygon {
+	return types.NewPolygon(types.NewLineString(
+		types.NewPoint(box.MinLon, box.MinLat),
+		types.NewPoint(box.MaxLon, box.MinLat),
+		types.NewPoint(box.MaxLon, box.MaxLat),
+		types.NewPoint(box.MinLon, box.MaxLat),
+		types.NewPoint(box.MinLon, box.MinLat),
+	))
+}
+
+// lowerASCII приводит латинскую букву к нижнему регистру
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestGeohashRoundTrip(t *testing.T) {
+	p := types.NewPoint(37.6173, 55.7558)
+	hash := EncodeGeohash(p, 9)
+	if hash != "ucfv0n014" {
+		t.Errorf("EncodeGeohash = %q, want ucfv0n014", hash)
+	}
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !boxContains(box, p.GetLongitude(), p.GetLatitude()) {
+		t.Errorf("cell %+v does not contain %v", box, p)
+	}
+	center, err := DecodeGeohash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := EncodeGeohash(center, 9); got != hash {
+		t.Errorf("centre of %q encodes to %q", hash, got)
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	opposite := map[GeohashDirection]GeohashDirection{
+		GeohashNorth: GeohashSouth, GeohashNorthEast: GeohashSouthWest,
+		GeohashEast: GeohashWest, GeohashSouthEast: GeohashNorthWest,
+	}
+	for _, hash := range []string{"ucftpuz", "rzzzzzz", "2pbpbpb"} {
+		neighbors, err := GeohashNeighbors(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for dir, back := range opposite {
+			n := neighbors[dir]
+			if got, _ := GeohashNeighbor(n, back); got != hash {
+				t.Errorf("%q: neighbour %d is %q, its neighbour back is %q", hash, dir, n, got)
+			}
+		}
+	}
+	if _, err := GeohashNeighbor("ucf", GeohashNorthWest+1); err == nil {
+		t.Error("unknown direction is accepted")
+	}
+}
+
+func TestGeohashCoverContainsPolygon(t *testing.T) {
+	polygon := types.NewPolygon(types.NewLineString(
+		types.NewPoint(37.5, 55.7), types.NewPoint(37.7, 55.7), types.NewPoint(37.6, 55.8), types.NewPoint(37.5, 55.7),
+	))
+	cover := GeohashCover(polygon, 6)
+	if len(cover) == 0 {
+		t.Fatal("empty cover")
+	}
+	for _, p := range polygon[0] {
+		covered := false
+		for _, hash := range cover {
+			box, _ := GeohashBounds(hash)
+			covered = covered || boxContains(box, p.GetLongitude(), p.GetLatitude())
+		}
+		if !covered {
+			t.Errorf("vertex %v is not covered", p)
+		}
+	}
+}
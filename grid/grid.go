@@ -0,0 +1,98 @@
+package grid
+
+import (
+	"iter"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Grid - сетка ячеек с идентификаторами CellID. Реализуется прямоугольной, гексагональной,
+// треугольной, ромбической и радиальной сетками, поэтому агрегация и экспорт могут работать с любым видом сетки
+type Grid interface {
+	// Cells перебирает идентификаторы всех ячеек в порядке генерации
+	Cells() iter.Seq[CellID]
+	// Len возвращает количество ячеек
+	Len() int
+	// Contains проверяет, что идентификатор соответствует ячейке сетки
+	Contains(id CellID) bool
+	// Boundary возвращает полигон ячейки
+	Boundary(id CellID) (types.Polygon, bool)
+	// Center возвращает центр ячейки
+	Center(id CellID) (types.Point, bool)
+	// Locate возвращает ячейку, содержащую точку
+	Locate(p types.Point) (CellID, bool)
+	// Neighbors возвращает соседние ячейки
+	Neighbors(id CellID) []CellID
+}
+
+// AreaGrid - сетка, знающая площади своих ячеек. Реализуется равновеликими сетками
+type AreaGrid interface {
+	Grid
+	// CellArea возвращает площадь ячейки в квадратных километрах
+	CellArea(id CellID) (float64, bool)
+}
+
+var (
+	_ Grid     = (*RectangularGrid)(nil)
+	_ Grid     = (*HexagonalGrid)(nil)
+	_ Grid     = (*RadialGrid)(nil)
+	_ Grid     = (*TriangularGrid)(nil)
+	_ Grid     = (*DiamondGrid)(nil)
+	_ Grid     = (*QuadtreeGrid)(nil)
+	_ AreaGrid = (*EqualAreaRectangularGrid)(nil)
+	_ AreaGrid = (*EqualAreaHexagonalGrid)(nil)
+)
+
+// Polygons возвращает полигоны всех ячеек сетки в порядке генерации
+func Polygons(g Grid) types.MultiPolygon {
+	cells := make(types.MultiPolygon, 0, g.Len())
+	for id := range g.Cells() {
+		cell, _ := g.Boundary(id)
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// Features возвращает ячейки сетки в виде набора объектов. Свойства объекта:
+// cell_id - идентификатор в виде "ряд/столбец", row и col - номера ряда и столбца.
+// Для сетки AreaGrid добавляется area_km2 - площадь ячейки в квадратных километрах
+func Features(g Grid) *types.FeatureCollection {
+	fc := types.NewFeatureCollection()
+	fc.Features = make([]types.Feature, 0, g.Len())
+	for id := range g.Cells() {
+		cell, _ := g.Boundary(id)
+		fc.Features = append(fc.Features, types.NewFeature(types.NewPolygonGeometry(cell), gridCellProperties(g, id)))
+	}
+	return fc
+}
+
+// cellProperties возвращает свойства объекта ячейки
+func cellProperties(id CellID) types.Properties {
+	props := types.NewProperties()
+	props["cell_id"] = id.String()
+	props["row"] = id.Row()
+	props["col"] = id.Col()
+	return props
+}
+
+// gridCellProperties возвращает свойства объекта ячейки с площадью для сетки AreaGrid
+func gridCellProperties(g Grid, id CellID) types.Properties {
+	props := cellProperties(id)
+	if areas, ok := g.(AreaGrid); ok {
+		props["area_km2"], _ = areas.CellArea(id)
+	}
+	return props
+}
+
+// rowMajor перебирает ячейки по рядам; cols возвращает количество ячеек в ряду
+func rowMajor(rows int, cols func(row int) int) iter.Seq[CellID] {
+	return func(yield func(CellID) bool) {
+		for r := 0; r < rows; r++ {
+			for c, n := 0, cols(r); c < n; c++ {
+				if !yield(NewCellID(r, c)) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package grid
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// checkGrid проверяет общие свойства сетки: случайная точка прямоугольника box находится
+// в ячейке, полигон которой ее содержит, центр ячейки находится в самой ячейке,
+// а соседство ячеек симметрично
+func checkGrid(t *testing.T, g Grid, box calc.BoundingBox) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := types.NewPoint(box.MinLon+r.Float64()*(box.MaxLon-box.MinLon), box.MinLat+r.Float64()*(box.MaxLat-box.MinLat))
+		id, ok := g.Locate(p)
+		if !ok {
+			t.Fatalf("point %v is not located", p)
+		}
+		cell, ok := g.Boundary(id)
+		if !ok || !calc.PointInPolygon(cell, p) {
+			t.Fatalf("point %v is located in %v, which does not contain it", p, id)
+		}
+	}
+	checkCells(t, g)
+}
+
+// checkCells проверяет, что ячейки принадлежат сетке, центр ячейки находится в самой ячейке,
+// а соседство ячеек симметрично
+func checkCells(t *testing.T, g Grid) {
+	t.Helper()
+	count := 0
+	for id := range g.Cells() {
+		count++
+		if !g.Contains(id) {
+			t.Fatalf("cell %v is not contained in the grid", id)
+		}
+		center, _ := g.Center(id)
+		if got, ok := g.Locate(center); !ok || got != id {
+			t.Fatalf("centre of %v is located in %v, %v", id, got, ok)
+		}
+		for _, n := range g.Neighbors(id) {
+			if !g.Contains(n) || n == id {
+				t.Fatalf("neighbour %v of %v is outside the grid", n, id)
+			}
+			if !containsCell(g.Neighbors(n), id) {
+				t.Fatalf("%v is not a neighbour of its neighbour %v", id, n)
+			}
+		}
+	}
+	if count != g.Len() {
+		t.Errorf("Cells returned %d cells, Len is %d", count, g.Len())
+	}
+}
+
+// containsCell проверяет, что идентификатор есть в списке
+func containsCell(ids []CellID, id CellID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedVertices возвращает количество общих вершин внешних контуров двух полигонов
+func sharedVertices(a, b types.Polygon) int {
+	n := 0
+	for _, p := range a[0][:len(a[0])-1] {
+		for _, q := range b[0][:len(b[0])-1] {
+			if math.Abs(p.GetLongitude()-q.GetLongitude()) < 1e-9 && math.Abs(p.GetLatitude()-q.GetLatitude()) < 1e-9 {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+func TestGridImplementations(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := make([]types.Point, 500)
+	for i := range points {
+		points[i] = types.NewPoint(37+r.Float64(), 55+r.Float64()*r.Float64())
+	}
+	moscow := calc.BoundingBox{MinLon: 37, MinLat: 55, MaxLon: 38, MaxLat: 56}
+	// Шестиугольники не покрывают края прямоугольника целиком
+	inner := calc.BoundingBox{MinLon: 37.1, MinLat: 55.1, MaxLon: 37.9, MaxLat: 55.9}
+	// Между рядами HexagonalGrid есть зазоры, ширина которых растет с широтой,
+	// поэтому эта сетка проверяется у экватора, где зазоры пренебрежимо малы
+	equator := calc.BoundingBox{MinLon: -0.4, MinLat: -0.4, MaxLon: 0.4, MaxLat: 0.4}
+	// Дуги радиальной сетки строятся хордами через метр, точки берутся из квадрата внутри внешнего кольца
+	radial := calc.BoundingBox{MinLon: -0.018, MinLat: -0.018, MaxLon: 0.018, MaxLat: 0.018}
+
+	tests := []struct {
+		name  string
+		build func() (Grid, error)
+		box   calc.BoundingBox
+	}{
+		{"rectangular", func() (Grid, error) { return NewRectangularGrid(37, 55, 38, 56, 0.1, 0.1) }, moscow},
+		{"hexagonal", func() (Grid, error) { return NewHexagonalGrid(-0.5, -0.5, 0.5, 0.5, 0.02) }, equator},
+		{"triangular", func() (Grid, error) { return NewTriangularGrid(37, 55, 38, 56, 0.1) }, moscow},
+		{"diamond", func() (Grid, error) { return NewDiamondGrid(37, 55, 38, 56, 0.1) }, moscow},
+		{"quadtree", func() (Grid, error) { return NewQuadtreeGrid(moscow, points, 10, 0.01) }, moscow},
+		{"radial", func() (Grid, error) {
+			return NewRadialGridWithOptions(0, 0, RadialOptions{
+				Radii:        []float64{1000, 2000, 3000},
+				SectorAngles: EqualSectors(6),
+				ArcSpacing:   1,
+			})
+		}, radial},
+		{"equal-area rectangular", func() (Grid, error) { return NewEqualAreaRectangularGrid(37, 55, 38, 56, 25) }, moscow},
+		{"equal-area hexagonal", func() (Grid, error) { return NewEqualAreaHexagonalGrid(37, 55, 38, 56, 25) }, inner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGrid(t, g, tt.box)
+
+			// Сетки с площадью ячеек возвращают ее для каждой ячейки
+			if a, ok := g.(AreaGrid); ok {
+				for id := range g.Cells() {
+					if area, ok := a.CellArea(id); !ok || !(area > 0) {
+						t.Fatalf("cell %v area = %g, %v", id, area, ok)
+					}
+				}
+			}
+		})
+	}
+}
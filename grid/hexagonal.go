@@ -1,8 +1,12 @@
 package grid
 
 import (
+	"fmt"
+	"iter"
 	"math"
+	"sort"
 
+	"github.com/Fliiiiii/go-geo/calc"
 	"github.com/Fliiiiii/go-geo/types"
 )
 
@@ -37,71 +41,210 @@ func CreateHexagon(lon, lat, r, k float64) types.Polygon {
 func CreateHexagonalGrid(minLon, minLat, maxLon, maxLat, r float64) types.MultiPolygon {
 	var M types.MultiPolygon
 
-	// Проверяем корректность границ
-	if minLat > maxLat || minLon > maxLon {
+	g, err := NewHexagonalGrid(minLon, minLat, maxLon, maxLat, r)
+	if err != nil {
 		return M // Возвращаем пустую карту при некорректных границах
 	}
+	return g.MultiPolygon()
+}
 
-	// Ограничиваем широту до диапазона [-90, 90]
-	if minLat < -90 {
-		minLat = -90
-	}
-	if maxLat > 90 {
-		maxLat = 90
-	}
+// hexagonalRow - ряд гексагональной сетки: два полуряда шестиугольников в шахматном порядке
+type hexagonalRow struct {
+	lat float64
+	// k - коэффициент Меркатора ряда
+	k float64
+}
 
-	// Ограничиваем долготу до диапазона [-180, 180]
-	if minLon < -180 {
-		minLon = -180
+// HexagonalGrid - гексагональная сетка, повторяющая ячейки CreateHexagonalGrid.
+// Столбцы с четным номером смещены на полтора радиуса к северу относительно нечетных.
+// Широты рядов вычисляются при создании, поэтому поиск ячейки точки не требует перебора ячеек
+type HexagonalGrid struct {
+	bounds calc.BoundingBox
+	radius float64
+	// deltaLon - шаг между центрами соседних столбцов по долготе
+	deltaLon float64
+	lons     []float64
+	rows     []hexagonalRow
+}
+
+// NewHexagonalGrid создает гексагональную сетку. Параметры совпадают с CreateHexagonalGrid:
+// r - радиус шестиугольника в градусах по широте, границы ограничиваются допустимым диапазоном координат
+func NewHexagonalGrid(minLon, minLat, maxLon, maxLat, r float64) (*HexagonalGrid, error) {
+	if minLat > maxLat || minLon > maxLon || r <= 0 {
+		return nil, fmt.Errorf("hexagonal grid [%g, %g, %g, %g] with radius %g: %w",
+			minLon, minLat, maxLon, maxLat, r, ErrInvalidGrid)
 	}
-	if maxLon > 180 {
-		maxLon = 180
+
+	// Ограничиваем широту до диапазона [-90, 90] и долготу до [-180, 180]
+	minLat, maxLat = max(minLat, -90), min(maxLat, 90)
+	minLon, maxLon = max(minLon, -180), min(maxLon, 180)
+
+	g := &HexagonalGrid{
+		bounds:   calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat},
+		radius:   r,
+		deltaLon: math.Sin(math.Pi/3) * r,
 	}
 
-	// Шаг между центрами шестиугольников по широте
+	// Шаг между рядами по широте до поправки Меркатора
 	deltaLat := r * 3
-	// Шаг между центрами шестиугольников по долготе
-	deltaLon := math.Sin(math.Pi/3) * r
-
-	// Цикл по широте - создаем ряды шестиугольников
 	for lat := minLat; lat <= maxLat; {
-		// Вычисляем коэффициент Меркатора для текущей широты
-		// Это нужно для компенсации искажений проекции (сжатие по долготе при удалении от экватора)
 		deltaMerc := math.Cos(lat * math.Pi / 180)
+		g.rows = append(g.rows, hexagonalRow{lat: lat, k: deltaMerc})
+		lat += deltaLat * deltaMerc
+	}
+	for lon := minLon; lon <= maxLon; lon += g.deltaLon {
+		g.lons = append(g.lons, lon)
+	}
+	return g, nil
+}
 
-		// флаг для смещения шестиугольников в четных/нечетных рядах (шахматный порядок)
-		b := false
-
-		// Цикл по долготе - создаем шестиугольники в текущем ряду
-		for lon := minLon; lon <= maxLon; lon += deltaLon {
-			var p types.Polygon
-			// В зависимости от флага b, создаем шестиугольник со смещением или без
-			if b {
-				p = CreateHexagon(lon, lat, r, deltaMerc)
-			} else {
-				p = CreateHexagon(lon, lat+r*1.5*deltaMerc, r, deltaMerc)
-			}
+// Bounds возвращает границы сетки после ограничения диапазоном координат
+func (g *HexagonalGrid) Bounds() calc.BoundingBox {
+	return g.bounds
+}
+
+// Radius возвращает радиус шестиугольников в градусах по широте
+func (g *HexagonalGrid) Radius() float64 {
+	return g.radius
+}
+
+// Rows возвращает количество рядов сетки
+func (g *HexagonalGrid) Rows() int {
+	return len(g.rows)
+}
+
+// Cols возвращает количество столбцов сетки
+func (g *HexagonalGrid) Cols() int {
+	return len(g.lons)
+}
+
+// Len возвращает общее количество ячеек сетки
+func (g *HexagonalGrid) Len() int {
+	return len(g.rows) * len(g.lons)
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *HexagonalGrid) Contains(id CellID) bool {
+	return id.Row() < len(g.rows) && id.Col() < len(g.lons)
+}
+
+// center возвращает центр ячейки без проверки идентификатора
+func (g *HexagonalGrid) center(row, col int) (float64, float64) {
+	hr := g.rows[row]
+	if col%2 == 0 {
+		return g.lons[col], hr.lat + g.radius*1.5*hr.k
+	}
+	return g.lons[col], hr.lat
+}
 
-			M = append(M, p)
-			// Инвертируем флаг для следующего шестиугольника в ряду
-			b = !b
+// Boundary возвращает шестиугольник ячейки. Возвращает false, если ячейки нет в сетке
+func (g *HexagonalGrid) Boundary(id CellID) (types.Polygon, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	lon, lat := g.center(id.Row(), id.Col())
+	return CreateHexagon(lon, lat, g.radius, g.rows[id.Row()].k), true
+}
+
+// Center возвращает центр ячейки. Возвращает false, если ячейки нет в сетке
+func (g *HexagonalGrid) Center(id CellID) (types.Point, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	lon, lat := g.center(id.Row(), id.Col())
+	return types.NewPoint(lon, lat), true
+}
+
+// Locate возвращает ячейку, шестиугольник которой содержит точку. Коэффициент Меркатора
+// меняется от ряда к ряду, поэтому между рядами возможны узкие зазоры и перекрытия:
+// в зазоре точка не принадлежит ни одной ячейке, при перекрытии выбирается ячейка,
+// сгенерированная раньше
+func (g *HexagonalGrid) Locate(p types.Point) (CellID, bool) {
+	if len(g.rows) == 0 || len(g.lons) == 0 {
+		return 0, false
+	}
+	lon, lat := p.GetLongitude(), p.GetLatitude()
+
+	// Последний ряд, начинающийся не севернее точки
+	row := sort.Search(len(g.rows), func(i int) bool { return g.rows[i].lat > lat }) - 1
+	col := int(math.Floor((lon - g.bounds.MinLon) / g.deltaLon))
+
+	for r := row - 1; r <= row+1; r++ {
+		if r < 0 || r >= len(g.rows) {
+			continue
 		}
+		for c := col - 1; c <= col+1; c++ {
+			if c < 0 || c >= len(g.lons) {
+				continue
+			}
+			if g.hexContains(r, c, lon, lat) {
+				return NewCellID(r, c), true
+			}
+		}
+	}
+	return 0, false
+}
 
-		// Увеличиваем широту с учетом коэффициента Меркатора
-		// Это компенсирует искажения проекции и обеспечивает равномерное распределение шестиугольников
-		lat += deltaLat * deltaMerc
+// hexContains проверяет, что точка лежит в шестиугольнике ячейки. В координатах,
+// нормированных на deltaLon по долготе и r*k по широте, вершины шестиугольника
+// находятся в точках (0, ±1) и (±1, ±1/2)
+func (g *HexagonalGrid) hexContains(row, col int, lon, lat float64) bool {
+	cLon, cLat := g.center(row, col)
+	x := math.Abs(lon-cLon) / g.deltaLon
+	y := math.Abs(lat-cLat) / (g.radius * g.rows[row].k)
+	return x <= 1 && y <= 1-x/2
+}
+
+// Neighbors возвращает до шести ячеек, имеющих с данной общую сторону. Соседи по долготе
+// находятся через столбец, соседи по диагонали - в соседних столбцах того же или соседнего ряда
+func (g *HexagonalGrid) Neighbors(id CellID) []CellID {
+	if !g.Contains(id) {
+		return nil
+	}
+	row, col := id.Row(), id.Col()
+
+	// Нечетные столбцы лежат в южном полуряду, их южные соседи - в предыдущем ряду,
+	// четные столбцы - в северном полуряду, их северные соседи - в следующем ряду
+	other := row - 1
+	if col%2 == 0 {
+		other = row + 1
+	}
+
+	candidates := [][2]int{
+		{row, col - 2}, {row, col + 2},
+		{row, col - 1}, {row, col + 1},
+		{other, col - 1}, {other, col + 1},
 	}
 
-	return M
+	result := make([]CellID, 0, len(candidates))
+	for _, rc := range candidates {
+		if rc[0] >= 0 && rc[0] < len(g.rows) && rc[1] >= 0 && rc[1] < len(g.lons) {
+			result = append(result, NewCellID(rc[0], rc[1]))
+		}
+	}
+	return result
+}
+
+// Cells перебирает ячейки сетки по рядам с юга на север
+func (g *HexagonalGrid) Cells() iter.Seq[CellID] {
+	return rowMajor(len(g.rows), func(int) int { return len(g.lons) })
+}
+
+// MultiPolygon возвращает все ячейки сетки в порядке рядов с юга на север
+func (g *HexagonalGrid) MultiPolygon() types.MultiPolygon {
+	return Polygons(g)
 }
 
 // CreateHexagonalGridWithDensity создает гексагональную сетку с заданной плотностью
 // minLon, minLat - координаты юго-западного угла
 // maxLon, maxLat - координаты северо-восточного угла
-// density - плотность сетки (количество шестиугольников на градус широты)
+// density - плотность сетки (количество шестиугольников на градус широты).
+// Раньше функция передавала границы в CreateHexagonalGrid в порядке minLat, maxLat, minLon, maxLon
+// и строила сетку не над заданным прямоугольником; теперь результат совпадает с CreateHexagonalGrid
+// с радиусом 1/(3·density)
 func CreateHexagonalGridWithDensity(minLon, minLat, maxLon, maxLat, density float64) types.MultiPolygon {
 	// Рассчитываем радиус шестиугольников на основе плотности
 	// Чем выше плотность, тем меньше радиус
 	r := 1.0 / (density * 3)
-	return CreateHexagonalGrid(minLat, maxLat, minLon, maxLon, r)
+	return CreateHexagonalGrid(minLon, minLat, maxLon, maxLat, r)
 }
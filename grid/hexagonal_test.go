@@ -0,0 +1,46 @@
+package grid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+)
+
+func TestCreateHexagonalGridWithDensity(t *testing.T) {
+	const minLon, minLat, maxLon, maxLat = 37, 55, 38.5, 56
+	cells := CreateHexagonalGridWithDensity(minLon, minLat, maxLon, maxLat, 2)
+	want := CreateHexagonalGrid(minLon, minLat, maxLon, maxLat, 1.0/6)
+	if len(cells) == 0 || len(cells) != len(want) {
+		t.Fatalf("%d cells, want %d", len(cells), len(want))
+	}
+
+	// Ячейки совпадают с сеткой CreateHexagonalGrid того же радиуса и лежат у заданного прямоугольника
+	for i, cell := range cells {
+		if !reflect.DeepEqual(cell, want[i]) {
+			t.Fatalf("cell %d = %v, want %v", i, cell, want[i])
+		}
+		box := calc.CalculatePlanarBoundingBox(cell)
+		if box.MinLon < minLon-0.2 || box.MaxLon > maxLon+0.2 || box.MinLat < minLat-0.2 || box.MaxLat > maxLat+0.2 {
+			t.Errorf("cell %d %+v is far from the grid bounds", i, box)
+		}
+	}
+}
+
+func TestHexagonalGridLocate(t *testing.T) {
+	g, err := NewHexagonalGrid(37, 55, 38, 56, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := range g.Cells() {
+		center, _ := g.Center(id)
+		if got, ok := g.Locate(center); !ok || got != id {
+			t.Errorf("centre of %v is located in %v, %v", id, got, ok)
+		}
+		for _, n := range g.Neighbors(id) {
+			if !g.Contains(n) {
+				t.Errorf("neighbour %v of %v is outside the grid", n, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package grid
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var json = jsoniter.ConfigFastest
+
+// RectangularGridPoints возвращает итератор по точкам CreateRectangularGrid.
+// Точки вычисляются по мере обхода, поэтому сетка не хранится в памяти целиком
+func RectangularGridPoints(minLon, minLat, maxLon, maxLat, stepLon, stepLat float64) iter.Seq[types.Point] {
+	return func(yield func(types.Point) bool) {
+		// Проверяем корректность границ
+		if minLon > maxLon || minLat > maxLat || stepLon <= 0 || stepLat <= 0 {
+			return
+		}
+
+		// Учитываем, что длина одного градуса долготы зависит от широты
+		for lat := minLat; lat <= maxLat; lat += stepLat {
+			adjustedStepLon := adjustedLonStep(lat, stepLon)
+
+			for lon := minLon; lon <= maxLon; lon += adjustedStepLon {
+				// Убедимся, что не выходим за пределы maxLon
+				if lon > maxLon {
+					lon = maxLon
+				}
+				if !yield(types.NewPoint(lon, lat)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RectangularGridCells возвращает итератор по ячейкам CreateRectangularGridCells
+// с идентификаторами, совпадающими с RectangularGrid. В отличие от RectangularGrid,
+// границы столбцов не хранятся, поэтому память не зависит от размера сетки
+func RectangularGridCells(minLon, minLat, maxLon, maxLat, stepLon, stepLat float64) iter.Seq2[CellID, types.Polygon] {
+	return func(yield func(CellID, types.Polygon) bool) {
+		// Проверяем корректность границ
+		if minLon > maxLon || minLat > maxLat || stepLon <= 0 || stepLat <= 0 {
+			return
+		}
+
+		row := 0
+		for lat := minLat; lat < maxLat; lat += stepLat {
+			nextLat := min(lat+stepLat, maxLat)
+			adjustedStepLon := adjustedLonStep(lat, stepLon)
+
+			col := 0
+			for lon := minLon; lon < maxLon; lon += adjustedStepLon {
+				box := calc.BoundingBox{MinLon: lon, MinLat: lat, MaxLon: min(lon+adjustedStepLon, maxLon), MaxLat: nextLat}
+				if !yield(NewCellID(row, col), boxPolygon(box)) {
+					return
+				}
+				col++
+			}
+			row++
+		}
+	}
+}
+
+// HexagonalGridCells возвращает итератор по ячейкам CreateHexagonalGrid с идентификаторами
+// HexagonalGrid. Сетка хранит только широты рядов и долготы столбцов, шестиугольники
+// создаются по мере обхода
+func HexagonalGridCells(minLon, minLat, maxLon, maxLat, r float64) iter.Seq2[CellID, types.Polygon] {
+	g, err := NewHexagonalGrid(minLon, minLat, maxLon, maxLat, r)
+	if err != nil {
+		return func(func(CellID, types.Polygon) bool) {}
+	}
+	return CellPolygons(g)
+}
+
+// CellPolygons возвращает итератор по идентификаторам и полигонам ячеек сетки
+func CellPolygons(g Grid) iter.Seq2[CellID, types.Polygon] {
+	return func(yield func(CellID, types.Polygon) bool) {
+		for id := range g.Cells() {
+			cell, _ := g.Boundary(id)
+			if !yield(id, cell) {
+				return
+			}
+		}
+	}
+}
+
+// WithContext останавливает обход seq после отмены ctx. Причину остановки
+// вызывающий код узнает по ctx.Err()
+func WithContext[V any](ctx context.Context, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range seq {
+			if ctx.Err() != nil || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// WithContext2 - вариант WithContext для итераторов пар
+func WithContext2[K, V any](ctx context.Context, seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if ctx.Err() != nil || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// WriteFeatureCollection записывает ячейки в w как GeoJSON FeatureCollection по мере обхода,
+// не собирая их в памяти. Объекты содержат свойства cell_id, row и col, как в Features;
+// площадь area_km2 не записывается, так как ячейки передаются без сетки. Возвращает количество
+// записанных ячеек; при отмене ctx запись прерывается и возвращается ошибка ctx.Err(),
+// а в w остается незавершенный документ
+func WriteFeatureCollection(ctx context.Context, w io.Writer, cells iter.Seq2[CellID, types.Polygon]) (int, error) {
+	if _, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`); err != nil {
+		return 0, fmt.Errorf("write feature collection: %w", err)
+	}
+
+	n := 0
+	var writeErr error
+	for id, cell := range WithContext2(ctx, cells) {
+		data, err := json.Marshal(types.NewFeature(types.NewPolygonGeometry(cell), cellProperties(id)))
+		if err != nil {
+			writeErr = fmt.Errorf("encode cell %s: %w", id, err)
+			break
+		}
+		if n > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				writeErr = fmt.Errorf("write cell %s: %w", id, err)
+				break
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			writeErr = fmt.Errorf("write cell %s: %w", id, err)
+			break
+		}
+		n++
+	}
+	if writeErr != nil {
+		return n, writeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return n, err
+	}
+
+	if _, err := io.WriteString(w, "]}\n"); err != nil {
+		return n, fmt.Errorf("write feature collection: %w", err)
+	}
+	return n, nil
+}
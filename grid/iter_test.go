@@ -0,0 +1,195 @@
+package grid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"reflect"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestIteratorsEarlyStop(t *testing.T) {
+	count := 0
+	for range RectangularGridPoints(37, 55, 38, 56, 0.1, 0.1) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("RectangularGridPoints stopped after %d points", count)
+	}
+
+	for name, seq := range map[string]iter.Seq2[CellID, types.Polygon]{
+		"RectangularGridCells": RectangularGridCells(37, 55, 38, 56, 0.1, 0.1),
+		"HexagonalGridCells":   HexagonalGridCells(37, 55, 38, 56, 0.05),
+	} {
+		count := 0
+		for range seq {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if count != 3 {
+			t.Errorf("%s stopped after %d cells", name, count)
+		}
+	}
+
+	// Ячейки итераторов совпадают с ячейками сеток
+	g, err := NewRectangularGrid(37, 55, 38, 56, 0.1, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for id, cell := range RectangularGridCells(37, 55, 38, 56, 0.1, 0.1) {
+		want, ok := g.Boundary(id)
+		if !ok || !reflect.DeepEqual(cell, want) {
+			t.Fatalf("cell %v = %v, want %v", id, cell, want)
+		}
+		n++
+	}
+	if n != g.Len() {
+		t.Errorf("RectangularGridCells returned %d cells, want %d", n, g.Len())
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	for range WithContext(ctx, RectangularGridPoints(37, 55, 38, 56, 0.1, 0.1)) {
+		count++
+		if count == 5 {
+			cancel()
+		}
+	}
+	if count != 5 || !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("WithContext returned %d points after cancellation, ctx.Err() = %v", count, ctx.Err())
+	}
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	count = 0
+	for range WithContext2(ctx2, RectangularGridCells(37, 55, 38, 56, 0.1, 0.1)) {
+		count++
+		if count == 5 {
+			cancel2()
+		}
+	}
+	if count != 5 {
+		t.Errorf("WithContext2 returned %d cells after cancellation", count)
+	}
+
+	// Досрочная остановка без отмены контекста
+	count = 0
+	for range WithContext2(context.Background(), RectangularGridCells(37, 55, 38, 56, 0.1, 0.1)) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("WithContext2 stopped after %d cells", count)
+	}
+}
+
+func TestWriteFeatureCollection(t *testing.T) {
+	g, err := NewHexagonalGrid(37, 55, 38, 56, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := WriteFeatureCollection(context.Background(), &buf, CellPolygons(g))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != g.Len() {
+		t.Errorf("%d cells written, want %d", n, g.Len())
+	}
+
+	var fc types.FeatureCollection
+	if err := json.Unmarshal(buf.Bytes(), &fc); err != nil {
+		t.Fatalf("output is not a feature collection: %v", err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != n {
+		t.Fatalf("decoded %q with %d features, want %d", fc.Type, len(fc.Features), n)
+	}
+
+	// Объекты совпадают с Features сетки без площади
+	want := Features(g)
+	for i := range fc.Features {
+		got, err := json.Marshal(fc.Features[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := json.Marshal(want.Features[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var a, b any
+		if err := json.Unmarshal(got, &a); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(expected, &b); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("feature %d = %s, want %s", i, got, expected)
+		}
+	}
+
+	// Пустой набор ячеек - корректный пустой документ
+	buf.Reset()
+	if n, err := WriteFeatureCollection(context.Background(), &buf, CellPolygons(&RectangularGrid{})); err != nil || n != 0 {
+		t.Errorf("empty grid: %d, %v", n, err)
+	}
+	if got := buf.String(); got != "{\"type\":\"FeatureCollection\",\"features\":[]}\n" {
+		t.Errorf("empty grid output = %q", got)
+	}
+}
+
+func TestWriteFeatureCollectionCancel(t *testing.T) {
+	g, err := NewRectangularGrid(37, 55, 38, 56, 0.1, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Отмена во время обхода прерывает запись после уже записанных ячеек
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seen := 0
+	var cells iter.Seq2[CellID, types.Polygon] = func(yield func(CellID, types.Polygon) bool) {
+		for id, cell := range CellPolygons(g) {
+			seen++
+			if seen == 4 {
+				cancel()
+			}
+			if !yield(id, cell) {
+				return
+			}
+		}
+	}
+	var buf bytes.Buffer
+	n, err := WriteFeatureCollection(ctx, &buf, cells)
+	if !errors.Is(err, context.Canceled) || err != ctx.Err() {
+		t.Errorf("error = %v, want ctx.Err()", err)
+	}
+	if n != 3 {
+		t.Errorf("%d cells written before cancellation, want 3", n)
+	}
+	if bytes.HasSuffix(buf.Bytes(), []byte("]}\n")) {
+		t.Error("cancelled output is a complete document")
+	}
+
+	// Контекст, отмененный до начала записи
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if n, err := WriteFeatureCollection(ctx, &buf, CellPolygons(g)); n != 0 || err != ctx.Err() {
+		t.Errorf("cancelled context: %d, %v", n, err)
+	}
+}
@@ -0,0 +1,183 @@
+package grid
+
+import (
+	"iter"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// MaskMode определяет, какие ячейки сетки остаются внутри области
+type MaskMode int
+
+const (
+	// MaskIntersects оставляет целые ячейки, имеющие общие точки с областью
+	MaskIntersects MaskMode = iota
+	// MaskCenter оставляет целые ячейки, центр которых лежит внутри области
+	MaskCenter
+	// MaskClip обрезает ячейки по границе области
+	MaskClip
+)
+
+// String возвращает название режима
+func (m MaskMode) String() string {
+	switch m {
+	case MaskIntersects:
+		return "intersects"
+	case MaskCenter:
+		return "center"
+	case MaskClip:
+		return "clip"
+	default:
+		return "unknown"
+	}
+}
+
+// MaskedCells перебирает ячейки сетки g, попадающие в область mask в режиме mode.
+// Идентификаторы ячеек совпадают с идентификаторами сетки, поэтому маска не меняет нумерацию.
+// В режиме MaskClip ячейка может распасться на несколько частей, в остальных режимах
+// возвращается полигон ячейки целиком
+func MaskedCells(g Grid, mask types.MultiPolygon, mode MaskMode) iter.Seq2[CellID, types.MultiPolygon] {
+	return func(yield func(CellID, types.MultiPolygon) bool) {
+		prepared := calc.NewPreparedMultiPolygon(mask)
+		bounds, ok := multiPolygonBounds(mask)
+		if !ok {
+			return
+		}
+
+		for id := range g.Cells() {
+			cell, _ := g.Boundary(id)
+			if !boxesIntersect(calc.CalculatePlanarBoundingBox(cell), bounds) {
+				continue
+			}
+
+			var parts types.MultiPolygon
+			switch mode {
+			case MaskCenter:
+				if center, _ := g.Center(id); prepared.ContainsPoint(center) {
+					parts = types.MultiPolygon{cell}
+				}
+			case MaskClip:
+				parts = clipCell(prepared, mask, cell)
+			default:
+				if prepared.IntersectsPolygon(cell) {
+					parts = types.MultiPolygon{cell}
+				}
+			}
+
+			if len(parts) > 0 && !yield(id, parts) {
+				return
+			}
+		}
+	}
+}
+
+// clipCell обрезает ячейку по области. Ячейки целиком внутри области не обрезаются
+func clipCell(prepared *calc.PreparedPolygon, mask types.MultiPolygon, cell types.Polygon) types.MultiPolygon {
+	if !prepared.IntersectsPolygon(cell) {
+		return nil
+	}
+	if prepared.ContainsPolygon(cell) {
+		return types.MultiPolygon{cell}
+	}
+
+	var parts types.MultiPolygon
+	for _, polygon := range mask {
+		parts = append(parts, calc.ClipPolygon(polygon, cell[0])...)
+	}
+	return parts
+}
+
+// MaskFeatures возвращает ячейки сетки внутри области в виде набора объектов со свойствами
+// Features. Обрезанная ячейка из нескольких частей записывается как MultiPolygon.
+// Для сетки AreaGrid в режиме MaskClip area_km2 содержит площадь обрезанной ячейки
+func MaskFeatures(g Grid, mask types.MultiPolygon, mode MaskMode) *types.FeatureCollection {
+	areas, _ := g.(AreaGrid)
+	fc := types.NewFeatureCollection()
+	for id, parts := range MaskedCells(g, mask, mode) {
+		geometry := types.NewMultiPolygonGeometry(parts)
+		if len(parts) == 1 {
+			geometry = types.NewPolygonGeometry(parts[0])
+		}
+
+		props := cellProperties(id)
+		if areas != nil {
+			area, _ := areas.CellArea(id)
+			if mode == MaskClip {
+				// Доля площади считается приближенно, поэтому целая ячейка сохраняет точную площадь
+				cell, _ := g.Boundary(id)
+				if whole := equalAreaKm2(types.MultiPolygon{cell}); whole > 0 {
+					area = math.Min(area, area*equalAreaKm2(parts)/whole)
+				}
+			}
+			props["area_km2"] = area
+		}
+		fc.Features = append(fc.Features, types.NewFeature(geometry, props))
+	}
+	return fc
+}
+
+// CreateRectangularGridInPolygon создает прямоугольную сетку внутри области.
+// Сетка строится по ограничивающему прямоугольнику области с шагом stepLon x stepLat,
+// как в CreateRectangularGridCells, поэтому для одной и той же области идентификаторы ячеек не меняются
+func CreateRectangularGridInPolygon(mask types.MultiPolygon, stepLon, stepLat float64, mode MaskMode) *types.FeatureCollection {
+	bounds, ok := multiPolygonBounds(mask)
+	if !ok {
+		return types.NewFeatureCollection()
+	}
+	g, err := NewRectangularGrid(bounds.MinLon, bounds.MinLat, bounds.MaxLon, bounds.MaxLat, stepLon, stepLat)
+	if err != nil {
+		return types.NewFeatureCollection()
+	}
+	return MaskFeatures(g, mask, mode)
+}
+
+// CreateHexagonalGridInPolygon создает гексагональную сетку внутри области.
+// Сетка строится по ограничивающему прямоугольнику области, как в CreateHexagonalGrid;
+// r - радиус шестиугольника в градусах по широте
+func CreateHexagonalGridInPolygon(mask types.MultiPolygon, r float64, mode MaskMode) *types.FeatureCollection {
+	bounds, ok := multiPolygonBounds(mask)
+	if !ok {
+		return types.NewFeatureCollection()
+	}
+	g, err := NewHexagonalGrid(bounds.MinLon, bounds.MinLat, bounds.MaxLon, bounds.MaxLat, r)
+	if err != nil {
+		return types.NewFeatureCollection()
+	}
+	return MaskFeatures(g, mask, mode)
+}
+
+// CreateRadialSectorsInPolygon создает радиальные секторы CreateRadialSectors внутри области
+func CreateRadialSectorsInPolygon(centerLon, centerLat, maxRadius float64, numSectors, numRings int,
+	mask types.MultiPolygon, mode MaskMode) *types.FeatureCollection {
+	g, err := NewRadialGrid(centerLon, centerLat, maxRadius, numSectors, numRings)
+	if err != nil {
+		return types.NewFeatureCollection()
+	}
+	return MaskFeatures(g, mask, mode)
+}
+
+// multiPolygonBounds вычисляет ограничивающий прямоугольник набора полигонов.
+// Возвращает false для пустого набора
+func multiPolygonBounds(mp types.MultiPolygon) (calc.BoundingBox, bool) {
+	bounds := calc.BoundingBox{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)}
+	ok := false
+	for _, polygon := range mp {
+		if len(polygon) == 0 || len(polygon[0]) == 0 {
+			continue
+		}
+		box := calc.CalculatePlanarBoundingBox(polygon)
+		bounds.MinLon = math.Min(bounds.MinLon, box.MinLon)
+		bounds.MinLat = math.Min(bounds.MinLat, box.MinLat)
+		bounds.MaxLon = math.Max(bounds.MaxLon, box.MaxLon)
+		bounds.MaxLat = math.Max(bounds.MaxLat, box.MaxLat)
+		ok = true
+	}
+	return bounds, ok
+}
+
+// boxesIntersect проверяет, имеют ли прямоугольники общие точки
+func boxesIntersect(a, b calc.BoundingBox) bool {
+	return a.MinLon <= b.MaxLon && b.MinLon <= a.MaxLon && a.MinLat <= b.MaxLat && b.MinLat <= a.MaxLat
+}
@@ -0,0 +1,62 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// band возвращает полосу вдоль всего круга широт от -180 до 180 по долготе
+func band(minLat, maxLat float64) types.MultiPolygon {
+	return types.MultiPolygon{types.NewPolygon(types.NewLineString(
+		types.NewPoint(-180, minLat), types.NewPoint(180, minLat),
+		types.NewPoint(180, maxLat), types.NewPoint(-180, maxLat),
+		types.NewPoint(-180, minLat),
+	))}
+}
+
+func TestMultiPolygonBoundsAcrossFullLongitude(t *testing.T) {
+	bounds, ok := multiPolygonBounds(band(-10, 10))
+	if !ok {
+		t.Fatal("bounds of a non-empty mask are not defined")
+	}
+	if bounds.MinLon != -180 || bounds.MaxLon != 180 || bounds.MinLat != -10 || bounds.MaxLat != 10 {
+		t.Fatalf("bounds = %+v, want [-180, -10, 180, 10]", bounds)
+	}
+}
+
+func TestMaskedGridNearAntimeridian(t *testing.T) {
+	for _, mode := range []MaskMode{MaskIntersects, MaskCenter, MaskClip} {
+		fc := CreateRectangularGridInPolygon(band(-10, 10), 10, 10, mode)
+		if got := len(fc.Features); got != 72 {
+			t.Errorf("%s: %d cells, want 72", mode, got)
+		}
+	}
+
+	// Ячейки у антимеридиана остаются в маске, прилегающей к нему с обеих сторон
+	mask := types.MultiPolygon{
+		types.NewPolygon(types.NewLineString(
+			types.NewPoint(170, 0), types.NewPoint(180, 0), types.NewPoint(180, 10),
+			types.NewPoint(170, 10), types.NewPoint(170, 0),
+		)),
+		types.NewPolygon(types.NewLineString(
+			types.NewPoint(-180, 0), types.NewPoint(-170, 0), types.NewPoint(-170, 10),
+			types.NewPoint(-180, 10), types.NewPoint(-180, 0),
+		)),
+	}
+	g, err := NewRectangularGrid(-180, -10, 180, 20, 5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := 0
+	for id := range MaskedCells(g, mask, MaskCenter) {
+		center, _ := g.Center(id)
+		if lon := center.GetLongitude(); lon > -170 && lon < 170 {
+			t.Errorf("cell %v with centre %v is outside the mask", id, center)
+		}
+		cells++
+	}
+	if cells != 8 {
+		t.Errorf("%d cells near the antimeridian, want 8", cells)
+	}
+}
@@ -0,0 +1,258 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// MaxQuadtreeDepth - максимальная глубина деления адаптивной сетки.
+// Код ячейки на этой глубине занимает все 32 бита столбца CellID
+const MaxQuadtreeDepth = 16
+
+// quadNode - узел дерева адаптивной сетки
+type quadNode struct {
+	box   calc.BoundingBox
+	depth int
+	// code - код Мортона ячейки на ее глубине: по два бита на уровень, старший - север, младший - восток
+	code  uint32
+	count int
+	// children - индексы дочерних узлов в порядке юго-запад, юго-восток, северо-запад, северо-восток;
+	// у листа равны нулю
+	children [4]int
+}
+
+// QuadtreeGrid - адаптивная сетка: прямоугольник рекурсивно делится на четыре равные
+// части, пока в ячейке больше maxPoints точек и ее половины не меньше minSize.
+// В разреженных областях ячейки крупные, в плотных - мелкие, поэтому количество точек
+// в ячейках выравнивается.
+// Ряд идентификатора ячейки - глубина деления, столбец - код Мортона (Z-порядок) ячейки
+// на этой глубине. Ячейки перебираются в Z-порядке
+type QuadtreeGrid struct {
+	nodes  []quadNode
+	leaves []int
+	index  map[CellID]int
+}
+
+// NewQuadtreeGrid строит адаптивную сетку по точкам внутри bounds.
+// maxPoints - максимальное количество точек в ячейке, minSize - минимальная ширина и высота
+// ячейки в градусах; ячейка с большим количеством точек остается неделенной, если ее половины
+// оказались бы меньше minSize или достигнута глубина MaxQuadtreeDepth. Точки вне bounds не учитываются.
+// Границы и minSize должны быть конечными числами
+func NewQuadtreeGrid(bounds calc.BoundingBox, points []types.Point, maxPoints int, minSize float64) (*QuadtreeGrid, error) {
+	if !finitePoint(bounds.MinLon, bounds.MinLat) || !finitePoint(bounds.MaxLon, bounds.MaxLat) ||
+		!(bounds.MinLon < bounds.MaxLon) || !(bounds.MinLat < bounds.MaxLat) ||
+		maxPoints < 0 || !(minSize >= 0) || math.IsInf(minSize, 0) {
+		return nil, fmt.Errorf("quadtree grid [%g, %g, %g, %g] with %d points per cell and min size %g: %w",
+			bounds.MinLon, bounds.MinLat, bounds.MaxLon, bounds.MaxLat, maxPoints, minSize, ErrInvalidGrid)
+	}
+
+	inside := make([]types.Point, 0, len(points))
+	for _, p := range points {
+		if boxContains(bounds, p.GetLongitude(), p.GetLatitude()) {
+			inside = append(inside, p)
+		}
+	}
+
+	g := &QuadtreeGrid{index: make(map[CellID]int)}
+	g.build(bounds, 0, 0, inside, maxPoints, minSize)
+	return g, nil
+}
+
+// build создает узел и при необходимости делит его. Возвращает индекс узла
+func (g *QuadtreeGrid) build(box calc.BoundingBox, depth int, code uint32, points []types.Point, maxPoints int, minSize float64) int {
+	i := len(g.nodes)
+	g.nodes = append(g.nodes, quadNode{box: box, depth: depth, code: code, count: len(points)})
+
+	midLon, midLat := (box.MinLon+box.MaxLon)/2, (box.MinLat+box.MaxLat)/2
+	if len(points) <= maxPoints || depth >= MaxQuadtreeDepth ||
+		midLon-box.MinLon < minSize || midLat-box.MinLat < minSize {
+		g.index[NewCellID(depth, int(code))] = len(g.leaves)
+		g.leaves = append(g.leaves, i)
+		return i
+	}
+
+	var parts [4][]types.Point
+	for _, p := range points {
+		q := quadrant(p.GetLongitude(), p.GetLatitude(), midLon, midLat)
+		parts[q] = append(parts[q], p)
+	}
+
+	boxes := [4]calc.BoundingBox{
+		{MinLon: box.MinLon, MinLat: box.MinLat, MaxLon: midLon, MaxLat: midLat},
+		{MinLon: midLon, MinLat: box.MinLat, MaxLon: box.MaxLon, MaxLat: midLat},
+		{MinLon: box.MinLon, MinLat: midLat, MaxLon: midLon, MaxLat: box.MaxLat},
+		{MinLon: midLon, MinLat: midLat, MaxLon: box.MaxLon, MaxLat: box.MaxLat},
+	}
+	for q := range boxes {
+		child := g.build(boxes[q], depth+1, code<<2|uint32(q), parts[q], maxPoints, minSize)
+		g.nodes[i].children[q] = child
+	}
+	return i
+}
+
+// quadrant возвращает номер четверти точки: бит 1 - северная половина, бит 0 - восточная.
+// Точка на линии деления относится к северной или восточной части
+func quadrant(lon, lat, midLon, midLat float64) int {
+	q := 0
+	if lon >= midLon {
+		q |= 1
+	}
+	if lat >= midLat {
+		q |= 2
+	}
+	return q
+}
+
+// boxContains проверяет, что точка лежит в прямоугольнике или на его границе
+func boxContains(box calc.BoundingBox, lon, lat float64) bool {
+	return lon >= box.MinLon && lon <= box.MaxLon && lat >= box.MinLat && lat <= box.MaxLat
+}
+
+// leaf возвращает лист дерева по идентификатору ячейки
+func (g *QuadtreeGrid) leaf(id CellID) (*quadNode, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return nil, false
+	}
+	return &g.nodes[g.leaves[i]], true
+}
+
+// Bounds возвращает границы сетки
+func (g *QuadtreeGrid) Bounds() calc.BoundingBox {
+	return g.nodes[0].box
+}
+
+// Len возвращает количество ячеек сетки
+func (g *QuadtreeGrid) Len() int {
+	return len(g.leaves)
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *QuadtreeGrid) Contains(id CellID) bool {
+	_, ok := g.index[id]
+	return ok
+}
+
+// Cells перебирает ячейки сетки в Z-порядке
+func (g *QuadtreeGrid) Cells() iter.Seq[CellID] {
+	return func(yield func(CellID) bool) {
+		for _, i := range g.leaves {
+			n := g.nodes[i]
+			if !yield(NewCellID(n.depth, int(n.code))) {
+				return
+			}
+		}
+	}
+}
+
+// Boundary возвращает прямоугольник ячейки. Возвращает false, если ячейки нет в сетке
+func (g *QuadtreeGrid) Boundary(id CellID) (types.Polygon, bool) {
+	n, ok := g.leaf(id)
+	if !ok {
+		return nil, false
+	}
+	return boxPolygon(n.box), true
+}
+
+// Center возвращает центр ячейки. Возвращает false, если ячейки нет в сетке
+func (g *QuadtreeGrid) Center(id CellID) (types.Point, bool) {
+	n, ok := g.leaf(id)
+	if !ok {
+		return nil, false
+	}
+	return types.NewPoint((n.box.MinLon+n.box.MaxLon)/2, (n.box.MinLat+n.box.MaxLat)/2), true
+}
+
+// Depth возвращает глубину деления ячейки. Возвращает false, если ячейки нет в сетке
+func (g *QuadtreeGrid) Depth(id CellID) (int, bool) {
+	n, ok := g.leaf(id)
+	if !ok {
+		return 0, false
+	}
+	return n.depth, true
+}
+
+// Count возвращает количество точек, попавших в ячейку при построении.
+// Возвращает false, если ячейки нет в сетке
+func (g *QuadtreeGrid) Count(id CellID) (int, bool) {
+	n, ok := g.leaf(id)
+	if !ok {
+		return 0, false
+	}
+	return n.count, true
+}
+
+// Locate возвращает ячейку, содержащую точку. Точка на общей границе ячеек относится
+// к северной или восточной ячейке, как и при подсчете точек
+func (g *QuadtreeGrid) Locate(p types.Point) (CellID, bool) {
+	lon, lat := p.GetLongitude(), p.GetLatitude()
+	if !boxContains(g.nodes[0].box, lon, lat) {
+		return 0, false
+	}
+
+	n := &g.nodes[0]
+	for n.children[0] != 0 {
+		midLon, midLat := (n.box.MinLon+n.box.MaxLon)/2, (n.box.MinLat+n.box.MaxLat)/2
+		n = &g.nodes[n.children[quadrant(lon, lat, midLon, midLat)]]
+	}
+	return NewCellID(n.depth, int(n.code)), true
+}
+
+// Neighbors возвращает ячейки, имеющие с данной общий участок границы или угол.
+// Соседние ячейки могут быть крупнее или мельче данной
+func (g *QuadtreeGrid) Neighbors(id CellID) []CellID {
+	cell, ok := g.leaf(id)
+	if !ok {
+		return nil
+	}
+
+	var result []CellID
+	var visit func(i int)
+	visit = func(i int) {
+		n := &g.nodes[i]
+		if !boxesIntersect(n.box, cell.box) {
+			return
+		}
+		if n.children[0] == 0 {
+			if n != cell {
+				result = append(result, NewCellID(n.depth, int(n.code)))
+			}
+			return
+		}
+		for _, child := range n.children {
+			visit(child)
+		}
+	}
+	visit(0)
+	return result
+}
+
+// Features возвращает ячейки сетки в виде набора объектов. Кроме свойств Features,
+// объект содержит depth - глубину деления и count - количество точек в ячейке
+func (g *QuadtreeGrid) Features() *types.FeatureCollection {
+	fc := types.NewFeatureCollection()
+	fc.Features = make([]types.Feature, 0, len(g.leaves))
+	for _, i := range g.leaves {
+		n := g.nodes[i]
+		id := NewCellID(n.depth, int(n.code))
+		props := cellProperties(id)
+		props["depth"] = n.depth
+		props["count"] = n.count
+		fc.Features = append(fc.Features, types.NewFeature(types.NewPolygonGeometry(boxPolygon(n.box)), props))
+	}
+	return fc
+}
+
+// CreateQuadtreeGrid создает адаптивную сетку NewQuadtreeGrid и возвращает ее ячейки
+// со свойствами depth и count. При некорректных параметрах возвращает пустой набор
+func CreateQuadtreeGrid(bounds calc.BoundingBox, points []types.Point, maxPoints int, minSize float64) *types.FeatureCollection {
+	g, err := NewQuadtreeGrid(bounds, points, maxPoints, minSize)
+	if err != nil {
+		return types.NewFeatureCollection()
+	}
+	return g.Features()
+}
@@ -0,0 +1,249 @@
+package grid
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// quadtreeBox - прямоугольник тестовых сеток
+var quadtreeBox = calc.BoundingBox{MinLon: 0, MinLat: 0, MaxLon: 4, MaxLat: 4}
+
+// clusteredPoints возвращает точки, сгущающиеся к юго-западному углу прямоугольника
+func clusteredPoints(r *rand.Rand, n int) []types.Point {
+	points := make([]types.Point, n)
+	for i := range points {
+		points[i] = types.NewPoint(4*math.Pow(r.Float64(), 3), 4*math.Pow(r.Float64(), 3))
+	}
+	return points
+}
+
+// cellBox возвращает прямоугольник ячейки адаптивной сетки
+func cellBox(t *testing.T, g *QuadtreeGrid, id CellID) calc.BoundingBox {
+	t.Helper()
+	n, ok := g.leaf(id)
+	if !ok {
+		t.Fatalf("cell %v is not in the grid", id)
+	}
+	return n.box
+}
+
+func TestQuadtreeGrid(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := clusteredPoints(r, 2000)
+	// Точки вне прямоугольника не учитываются
+	points = append(points, types.NewPoint(5, 1), types.NewPoint(-1, 1))
+
+	g, err := NewQuadtreeGrid(quadtreeBox, points, 20, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGrid(t, g, g.Bounds())
+
+	total, minDepth, maxDepth := 0, MaxQuadtreeDepth, 0
+	area := 0.0
+	for id := range g.Cells() {
+		count, _ := g.Count(id)
+		depth, _ := g.Depth(id)
+		box := cellBox(t, g, id)
+		total += count
+		minDepth, maxDepth = min(minDepth, depth), max(maxDepth, depth)
+		area += (box.MaxLon - box.MinLon) * (box.MaxLat - box.MinLat)
+
+		// Ячейка с лишними точками не делится, только если ее половины меньше minSize
+		if count > 20 && (box.MaxLon-box.MinLon)/2 >= 0.01 {
+			t.Errorf("cell %v with %d points is not split", id, count)
+		}
+		if id.Row() != depth {
+			t.Errorf("cell %v has depth %d", id, depth)
+		}
+	}
+	if total != 2000 {
+		t.Errorf("cells count %d points, want 2000", total)
+	}
+	if math.Abs(area-16) > 1e-9 {
+		t.Errorf("cells cover %g square degrees, want 16", area)
+	}
+	if minDepth == maxDepth {
+		t.Errorf("all cells have depth %d", minDepth)
+	}
+
+	// Свойства объектов совпадают с ячейками
+	fc := g.Features()
+	if len(fc.Features) != g.Len() {
+		t.Fatalf("%d features, want %d", len(fc.Features), g.Len())
+	}
+	i := 0
+	for id := range g.Cells() {
+		props := fc.Features[i].Properties
+		depth, _ := g.Depth(id)
+		count, _ := g.Count(id)
+		if props["cell_id"] != id.String() || props["depth"] != depth || props["count"] != count {
+			t.Errorf("feature %d properties = %v", i, props)
+		}
+		i++
+	}
+}
+
+func TestQuadtreeStopRules(t *testing.T) {
+	same := make([]types.Point, 100)
+	for i := range same {
+		same[i] = types.NewPoint(1.1, 1.1)
+	}
+
+	// Половины ячейки 0,5° меньше minSize 0,3°, поэтому деление останавливается на глубине 3
+	g, err := NewQuadtreeGrid(quadtreeBox, same, 10, 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := g.Locate(types.NewPoint(1.1, 1.1))
+	if depth, _ := g.Depth(id); depth != 3 {
+		t.Errorf("minSize: depth = %d, want 3", depth)
+	}
+	if count, _ := g.Count(id); count != 100 {
+		t.Errorf("minSize: count = %d, want 100", count)
+	}
+
+	// Без ограничения размера совпадающие точки делятся до MaxQuadtreeDepth
+	g, err = NewQuadtreeGrid(quadtreeBox, same, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ = g.Locate(types.NewPoint(1.1, 1.1))
+	if depth, _ := g.Depth(id); depth != MaxQuadtreeDepth {
+		t.Errorf("max depth: depth = %d, want %d", depth, MaxQuadtreeDepth)
+	}
+	if count, _ := g.Count(id); count != 100 {
+		t.Errorf("max depth: count = %d, want 100", count)
+	}
+	if g.Len() != 1+3*MaxQuadtreeDepth {
+		t.Errorf("max depth: %d cells, want %d", g.Len(), 1+3*MaxQuadtreeDepth)
+	}
+	checkGrid(t, g, g.Bounds())
+
+	// Не больше maxPoints точек - одна ячейка
+	g, err = NewQuadtreeGrid(quadtreeBox, same[:10], 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 1 || !g.Contains(NewCellID(0, 0)) {
+		t.Errorf("maxPoints: %d cells", g.Len())
+	}
+}
+
+func TestQuadtreeSplitLine(t *testing.T) {
+	// Точки на линиях деления и на границе прямоугольника считаются один раз
+	// и относятся к северной или восточной ячейке, как и в Locate
+	points := []types.Point{
+		types.NewPoint(2, 2), types.NewPoint(2, 1), types.NewPoint(1, 2),
+		types.NewPoint(4, 4), types.NewPoint(0, 0), types.NewPoint(1, 1),
+	}
+	g, err := NewQuadtreeGrid(quadtreeBox, points, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for id := range g.Cells() {
+		count, _ := g.Count(id)
+		total += count
+	}
+	if total != len(points) {
+		t.Errorf("cells count %d points, want %d", total, len(points))
+	}
+
+	for _, p := range points {
+		id, ok := g.Locate(p)
+		if !ok {
+			t.Fatalf("%v is not located", p)
+		}
+		if count, _ := g.Count(id); count == 0 {
+			t.Errorf("%v is located in %v without points", p, id)
+		}
+	}
+	id, _ := g.Locate(types.NewPoint(2, 2))
+	if box := cellBox(t, g, id); box.MinLon != 2 || box.MinLat != 2 {
+		t.Errorf("(2, 2) is located in %+v, want the north-east cell", box)
+	}
+	if _, ok := g.Locate(types.NewPoint(4.1, 2)); ok {
+		t.Error("point outside the grid is located")
+	}
+}
+
+func TestQuadtreeNeighbors(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	g, err := NewQuadtreeGrid(quadtreeBox, clusteredPoints(r, 500), 5, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Соседи - все ячейки, граница которых касается ячейки, в том числе углом
+	mixed := false
+	for id := range g.Cells() {
+		box := cellBox(t, g, id)
+		depth, _ := g.Depth(id)
+		neighbors := g.Neighbors(id)
+		for other := range g.Cells() {
+			touches := other != id && boxesIntersect(box, cellBox(t, g, other))
+			if touches != containsCell(neighbors, other) {
+				t.Fatalf("%v and %v: touch %v, neighbours %v", id, other, touches, !touches)
+			}
+			if otherDepth, _ := g.Depth(other); touches && otherDepth != depth {
+				mixed = true
+			}
+		}
+	}
+	if !mixed {
+		t.Error("no neighbours of different size")
+	}
+
+	// Точки только у юго-западного угла: северо-восточная четверть остается целой
+	// и граничит углом с мелкой ячейкой [1, 2]x[1, 2]
+	cluster := make([]types.Point, 20)
+	for i := range cluster {
+		cluster[i] = types.NewPoint(0.5+0.01*float64(i), 0.5)
+	}
+	g, err = NewQuadtreeGrid(quadtreeBox, cluster, 5, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ne, corner := NewCellID(1, 3), NewCellID(2, 3)
+	if box := cellBox(t, g, corner); box.MinLon != 1 || box.MaxLat != 2 {
+		t.Fatalf("cell %v is %+v", corner, box)
+	}
+	if !containsCell(g.Neighbors(ne), corner) || !containsCell(g.Neighbors(corner), ne) {
+		t.Errorf("%v and %v are not neighbours: %v, %v", ne, corner, g.Neighbors(ne), g.Neighbors(corner))
+	}
+	if g.Neighbors(NewCellID(MaxQuadtreeDepth, 1)) != nil {
+		t.Error("cell outside the grid has neighbours")
+	}
+}
+
+func TestQuadtreeInvalid(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name      string
+		bounds    calc.BoundingBox
+		maxPoints int
+		minSize   float64
+	}{
+		{"empty bounds", calc.BoundingBox{MinLon: 1, MinLat: 0, MaxLon: 1, MaxLat: 1}, 1, 0},
+		{"NaN bounds", calc.BoundingBox{MinLon: nan, MinLat: 0, MaxLon: 1, MaxLat: 1}, 1, 0},
+		{"infinite bounds", calc.BoundingBox{MinLon: 0, MinLat: 0, MaxLon: inf, MaxLat: 1}, 1, 0},
+		{"negative max points", quadtreeBox, -1, 0},
+		{"negative min size", quadtreeBox, 1, -1},
+		{"NaN min size", quadtreeBox, 1, nan},
+		{"infinite min size", quadtreeBox, 1, inf},
+	}
+	for _, tt := range tests {
+		if _, err := NewQuadtreeGrid(tt.bounds, nil, tt.maxPoints, tt.minSize); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("%s: error = %v, want ErrInvalidGrid", tt.name, err)
+		}
+		if fc := CreateQuadtreeGrid(tt.bounds, nil, tt.maxPoints, tt.minSize); len(fc.Features) != 0 {
+			t.Errorf("%s: %d cells, want none", tt.name, len(fc.Features))
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package grid
 
 import (
+	"fmt"
+	"iter"
 	"math"
+	"sort"
 
 	"github.com/Fliiiiii/go-geo/calc"
 	"github.com/Fliiiiii/go-geo/types"
@@ -53,71 +56,369 @@ func CreateRadialGrid(centerLon, centerLat, maxRadius float64, numRings, pointsP
 func CreateRadialSectors(centerLon, centerLat, maxRadius float64, numSectors, numRings int) types.MultiPolygon {
 	var sectors types.MultiPolygon
 
-	// Проверяем корректность входных данных
-	if maxRadius <= 0 || numSectors <= 0 || numRings <= 0 {
+	g, err := NewRadialGrid(centerLon, centerLat, maxRadius, numSectors, numRings)
+	if err != nil {
 		return sectors
 	}
+	return g.MultiPolygon()
+}
 
-	centerPoint := types.NewPoint(centerLon, centerLat)
+// Параметры аппроксимации дуг по умолчанию, совпадающие с CreateRadialSectors
+const (
+	// DefaultArcSpacing - максимальное расстояние между точками дуги в метрах
+	DefaultArcSpacing = 50.0
+	// DefaultMinArcSegments - минимальное количество отрезков дуги
+	DefaultMinArcSegments = 4
+)
+
+// RadialGrid - радиальная сетка секторов вокруг центральной точки, повторяющая ячейки
+// CreateRadialSectors. Номер ряда ячейки - номер кольца от центра, номер столбца - номер
+// сектора по часовой стрелке от начального азимута
+type RadialGrid struct {
+	center types.Point
+	// radii - границы колец в метрах, начиная с нуля
+	radii []float64
+	// bearings - границы секторов в радианах по часовой стрелке от севера, на одну больше
+	// количества секторов; последняя граница может превышать 2π
+	bearings []float64
+	// fullCircle - секторы покрывают полный круг, поэтому первый и последний соседствуют
+	fullCircle bool
+	// arcSpacing и minArcSegments задают аппроксимацию дуг
+	arcSpacing     float64
+	minArcSegments int
+}
+
+// RadialOptions - параметры радиальной сетки с произвольными кольцами и секторами
+type RadialOptions struct {
+	// Radii - внешние границы колец в метрах по возрастанию, например 500, 1000, 3000, 10000.
+	// Первое кольцо начинается в центре
+	Radii []float64
+	// SectorAngles - углы секторов в градусах по часовой стрелке. Сумма углов не больше 360;
+	// если она меньше, за последним сектором остается непокрытая часть круга.
+	// Пустой список означает один сектор в 360°
+	SectorAngles []float64
+	// StartBearing - азимут начала первого сектора в градусах от направления на север
+	StartBearing float64
+	// ArcSpacing - максимальное расстояние между точками дуги в метрах, по умолчанию DefaultArcSpacing
+	ArcSpacing float64
+	// MinArcSegments - минимальное количество отрезков дуги, по умолчанию DefaultMinArcSegments
+	MinArcSegments int
+}
+
+// EqualSectors возвращает углы numSectors секторов одинакового размера для RadialOptions.SectorAngles
+func EqualSectors(numSectors int) []float64 {
+	angles := make([]float64, max(numSectors, 0))
+	for i := range angles {
+		angles[i] = 360 / float64(numSectors)
+	}
+	return angles
+}
+
+// NewRadialGrid создает радиальную сетку из numRings колец одинаковой ширины
+// и numSectors секторов одинакового угла; maxRadius задается в метрах.
+// Колец и секторов может быть не больше 2^20
+func NewRadialGrid(centerLon, centerLat, maxRadius float64, numSectors, numRings int) (*RadialGrid, error) {
+	if !finitePoint(centerLon, centerLat) || !positiveFinite(maxRadius) ||
+		numSectors <= 0 || numRings <= 0 || numSectors > maxGridCells || numRings > maxGridCells {
+		return nil, fmt.Errorf("radial grid with radius %g, %d sectors and %d rings: %w",
+			maxRadius, numSectors, numRings, ErrInvalidGrid)
+	}
 
 	// Шаг радиуса между кольцами
 	radiusStep := maxRadius / float64(numRings)
-	// Угловой шаг между секторами
+	radii := make([]float64, numRings+1)
+	for ring := range radii {
+		radii[ring] = float64(ring) * radiusStep
+	}
+
 	angleStep := 2 * math.Pi / float64(numSectors)
+	bearings := make([]float64, numSectors+1)
+	for sector := range bearings {
+		bearings[sector] = float64(sector) * angleStep
+	}
 
-	// Создаем секторы
-	for sector := 0; sector < numSectors; sector++ {
-		startAngle := float64(sector) * angleStep
-		endAngle := float64(sector+1) * angleStep
+	return &RadialGrid{
+		center:         types.NewPoint(centerLon, centerLat),
+		radii:          radii,
+		bearings:       bearings,
+		fullCircle:     true,
+		arcSpacing:     DefaultArcSpacing,
+		minArcSegments: DefaultMinArcSegments,
+	}, nil
+}
 
-		for ring := 0; ring < numRings; ring++ {
-			innerRadius := float64(ring) * radiusStep
-			outerRadius := float64(ring+1) * radiusStep
+// NewRadialGridWithOptions создает радиальную сетку с заданными границами колец,
+// углами секторов и аппроксимацией дуг. Все значения должны быть конечными числами,
+// колец и секторов может быть не больше 2^20
+func NewRadialGridWithOptions(centerLon, centerLat float64, opts RadialOptions) (*RadialGrid, error) {
+	if !finitePoint(centerLon, centerLat) {
+		return nil, fmt.Errorf("radial grid centre [%g, %g]: %w", centerLon, centerLat, ErrInvalidGrid)
+	}
+	if len(opts.Radii) == 0 || len(opts.Radii) > maxGridCells || len(opts.SectorAngles) > maxGridCells ||
+		(opts.ArcSpacing != 0 && !positiveFinite(opts.ArcSpacing)) || opts.MinArcSegments < 0 {
+		return nil, fmt.Errorf("radial grid with %d rings, %d sectors, arc spacing %g and %d arc segments: %w",
+			len(opts.Radii), len(opts.SectorAngles), opts.ArcSpacing, opts.MinArcSegments, ErrInvalidGrid)
+	}
+	if math.IsNaN(opts.StartBearing) || math.IsInf(opts.StartBearing, 0) {
+		return nil, fmt.Errorf("radial grid start bearing %g: %w", opts.StartBearing, ErrInvalidGrid)
+	}
 
-			// Создаем полигон для сектора
-			var sectorPoints types.LineString
+	radii := make([]float64, 0, len(opts.Radii)+1)
+	radii = append(radii, 0)
+	for _, r := range opts.Radii {
+		if !positiveFinite(r) || r <= radii[len(radii)-1] {
+			return nil, fmt.Errorf("radial grid ring radius %g after %g: %w", r, radii[len(radii)-1], ErrInvalidGrid)
+		}
+		radii = append(radii, r)
+	}
 
-			if innerRadius == 0 {
-				// Особый случай для первого кольца с центральной точкой
-				sectorPoints = append(sectorPoints, centerPoint)
+	angles := opts.SectorAngles
+	if len(angles) == 0 {
+		angles = []float64{360}
+	}
+	start := math.Mod(math.Mod(opts.StartBearing, 360)+360, 360)
+	bearings := make([]float64, 0, len(angles)+1)
+	bearings = append(bearings, start*math.Pi/180)
+	total := 0.0
+	for _, a := range angles {
+		if !positiveFinite(a) {
+			return nil, fmt.Errorf("radial grid sector angle %g: %w", a, ErrInvalidGrid)
+		}
+		total += a
+		bearings = append(bearings, (start+total)*math.Pi/180)
+	}
+	// Допуск на погрешность суммы, например двенадцати углов по 30°
+	const eps = 1e-9
+	if total > 360+eps {
+		return nil, fmt.Errorf("radial grid sector angles sum to %g°: %w", total, ErrInvalidGrid)
+	}
+	fullCircle := total >= 360-eps
+	if fullCircle {
+		bearings[len(bearings)-1] = bearings[0] + 2*math.Pi
+	}
 
-				// Добавляем точки на внешнем радиусе
-				numPoints := int(math.Max(4, math.Ceil((endAngle-startAngle)*outerRadius/50)))
-				for i := 0; i <= numPoints; i++ {
-					angle := startAngle + (endAngle-startAngle)*float64(i)/float64(numPoints)
-					point := calc.CalculateDestinationPoint(centerPoint, outerRadius, angle)
-					sectorPoints = append(sectorPoints, point)
-				}
+	g := &RadialGrid{
+		center:         types.NewPoint(centerLon, centerLat),
+		radii:          radii,
+		bearings:       bearings,
+		fullCircle:     fullCircle,
+		arcSpacing:     opts.ArcSpacing,
+		minArcSegments: opts.MinArcSegments,
+	}
+	if g.arcSpacing == 0 {
+		g.arcSpacing = DefaultArcSpacing
+	}
+	if g.minArcSegments == 0 {
+		g.minArcSegments = DefaultMinArcSegments
+	}
+	return g, nil
+}
 
-				// Замыкаем полигон, добавляя первую точку внешнего радиуса
-				sectorPoints = append(sectorPoints, sectorPoints[0])
-			} else {
-				// Добавляем точки на внутреннем радиусе
-				numInnerPoints := int(math.Max(4, math.Ceil((endAngle-startAngle)*innerRadius/50)))
-				for i := 0; i <= numInnerPoints; i++ {
-					angle := startAngle + (endAngle-startAngle)*float64(i)/float64(numInnerPoints)
-					point := calc.CalculateDestinationPoint(centerPoint, innerRadius, angle)
-					sectorPoints = append(sectorPoints, point)
-				}
+// CreateRadialZones создает радиальные секторы с заданными границами колец и углами секторов
+// и возвращает их со свойствами RadialGrid.Features. При некорректных параметрах возвращает пустой набор
+func CreateRadialZones(centerLon, centerLat float64, opts RadialOptions) *types.FeatureCollection {
+	g, err := NewRadialGridWithOptions(centerLon, centerLat, opts)
+	if err != nil {
+		return types.NewFeatureCollection()
+	}
+	return g.Features()
+}
 
-				// Добавляем точки на внешнем радиусе (в обратном порядке)
-				numOuterPoints := int(math.Max(4, math.Ceil((endAngle-startAngle)*outerRadius/50)))
-				for i := numOuterPoints; i >= 0; i-- {
-					angle := startAngle + (endAngle-startAngle)*float64(i)/float64(numOuterPoints)
-					point := calc.CalculateDestinationPoint(centerPoint, outerRadius, angle)
-					sectorPoints = append(sectorPoints, point)
-				}
+// Rings возвращает количество колец сетки
+func (g *RadialGrid) Rings() int {
+	return len(g.radii) - 1
+}
 
-				// Замыкаем полигон
-				sectorPoints = append(sectorPoints, sectorPoints[0])
-			}
+// Sectors возвращает количество секторов сетки
+func (g *RadialGrid) Sectors() int {
+	return len(g.bearings) - 1
+}
+
+// RingRadii возвращает внутренний и внешний радиусы кольца в метрах
+func (g *RadialGrid) RingRadii(ring int) (float64, float64, bool) {
+	if ring < 0 || ring >= g.Rings() {
+		return 0, 0, false
+	}
+	return g.radii[ring], g.radii[ring+1], true
+}
+
+// SectorBearings возвращает азимуты начала и конца сектора в градусах по часовой стрелке
+// от севера. Азимут начала лежит в [0, 360), азимут конца превышает 360, если сектор
+// пересекает направление на север
+func (g *RadialGrid) SectorBearings(sector int) (float64, float64, bool) {
+	if sector < 0 || sector >= g.Sectors() {
+		return 0, 0, false
+	}
+	start := math.Mod(g.bearings[sector]*180/math.Pi, 360)
+	return start, start + (g.bearings[sector+1]-g.bearings[sector])*180/math.Pi, true
+}
 
-			// Проверяем, что полигон содержит минимум 4 точки (3 уникальные точки + замыкающая)
-			if len(sectorPoints) >= 4 {
-				sectors = append(sectors, types.Polygon{sectorPoints})
+// Len возвращает общее количество ячеек сетки
+func (g *RadialGrid) Len() int {
+	return g.Rings() * g.Sectors()
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *RadialGrid) Contains(id CellID) bool {
+	return id.Row() < g.Rings() && id.Col() < g.Sectors()
+}
+
+// Cells перебирает ячейки по секторам, а внутри сектора - от центра наружу,
+// в том же порядке, что и CreateRadialSectors
+func (g *RadialGrid) Cells() iter.Seq[CellID] {
+	return func(yield func(CellID) bool) {
+		for sector := 0; sector < g.Sectors(); sector++ {
+			for ring := 0; ring < g.Rings(); ring++ {
+				if !yield(NewCellID(ring, sector)) {
+					return
+				}
 			}
 		}
 	}
+}
+
+// Boundary возвращает полигон сектора кольца. Дуги аппроксимируются точками не реже чем
+// через заданное расстояние, по умолчанию 50 метров. Возвращает false, если ячейки нет в сетке
+func (g *RadialGrid) Boundary(id CellID) (types.Polygon, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+
+	startAngle := g.bearings[id.Col()]
+	endAngle := g.bearings[id.Col()+1]
+	innerRadius := g.radii[id.Row()]
+	outerRadius := g.radii[id.Row()+1]
+
+	// Создаем полигон для сектора
+	var sectorPoints types.LineString
+
+	if innerRadius == 0 {
+		// Особый случай для первого кольца с центральной точкой
+		sectorPoints = append(sectorPoints, g.center)
+
+		// Добавляем точки на внешнем радиусе
+		numPoints := g.arcSegments(endAngle-startAngle, outerRadius)
+		for i := 0; i <= numPoints; i++ {
+			angle := startAngle + (endAngle-startAngle)*float64(i)/float64(numPoints)
+			point := calc.CalculateDestinationPoint(g.center, outerRadius, angle)
+			sectorPoints = append(sectorPoints, point)
+		}
+
+		// Замыкаем полигон, добавляя первую точку внешнего радиуса
+		sectorPoints = append(sectorPoints, sectorPoints[0])
+	} else {
+		// Добавляем точки на внутреннем радиусе
+		numInnerPoints := g.arcSegments(endAngle-startAngle, innerRadius)
+		for i := 0; i <= numInnerPoints; i++ {
+			angle := startAngle + (endAngle-startAngle)*float64(i)/float64(numInnerPoints)
+			point := calc.CalculateDestinationPoint(g.center, innerRadius, angle)
+			sectorPoints = append(sectorPoints, point)
+		}
+
+		// Добавляем точки на внешнем радиусе (в обратном порядке)
+		numOuterPoints := g.arcSegments(endAngle-startAngle, outerRadius)
+		for i := numOuterPoints; i >= 0; i-- {
+			angle := startAngle + (endAngle-startAngle)*float64(i)/float64(numOuterPoints)
+			point := calc.CalculateDestinationPoint(g.center, outerRadius, angle)
+			sectorPoints = append(sectorPoints, point)
+		}
+
+		// Замыкаем полигон
+		sectorPoints = append(sectorPoints, sectorPoints[0])
+	}
+
+	return types.Polygon{sectorPoints}, true
+}
+
+// arcSegments возвращает количество отрезков дуги угла angle радиан на радиусе radius метров,
+// но не больше maxGridCells
+func (g *RadialGrid) arcSegments(angle, radius float64) int {
+	return int(math.Min(math.Max(float64(g.minArcSegments), math.Ceil(angle*radius/g.arcSpacing)), maxGridCells))
+}
 
-	return sectors
+// Center возвращает точку на середине кольца и биссектрисе сектора.
+// Возвращает false, если ячейки нет в сетке
+func (g *RadialGrid) Center(id CellID) (types.Point, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	radius := (g.radii[id.Row()] + g.radii[id.Row()+1]) / 2
+	bearing := (g.bearings[id.Col()] + g.bearings[id.Col()+1]) / 2
+	return calc.CalculateDestinationPoint(g.center, radius, bearing), true
+}
+
+// Locate возвращает ячейку по расстоянию от центра и азимуту точки.
+// Точки дальше внешнего кольца и вне секторов не принадлежат сетке
+func (g *RadialGrid) Locate(p types.Point) (CellID, bool) {
+	distance, bearing := calc.CalculateDistanceAndBearing(g.center, p)
+	if distance > g.radii[len(g.radii)-1] {
+		return 0, false
+	}
+
+	ring := sort.Search(g.Rings(), func(i int) bool { return g.radii[i+1] > distance })
+	ring = min(ring, g.Rings()-1)
+
+	// Азимут относительно начала первого сектора
+	offset := math.Mod(bearing-g.bearings[0]+2*math.Pi, 2*math.Pi)
+	sectors := g.Sectors()
+	if offset > g.bearings[sectors]-g.bearings[0] {
+		return 0, false
+	}
+	sector := sort.Search(sectors, func(i int) bool { return g.bearings[i+1]-g.bearings[0] > offset })
+	sector = min(sector, sectors-1)
+	return NewCellID(ring, sector), true
+}
+
+// Neighbors возвращает ячейки с общей стороной: соседние секторы того же кольца
+// и тот же сектор соседних колец. Первый и последний секторы соседствуют, только
+// если секторы покрывают полный круг
+func (g *RadialGrid) Neighbors(id CellID) []CellID {
+	if !g.Contains(id) {
+		return nil
+	}
+	ring, sector := id.Row(), id.Col()
+
+	var result []CellID
+	if ring > 0 {
+		result = append(result, NewCellID(ring-1, sector))
+	}
+	if ring+1 < g.Rings() {
+		result = append(result, NewCellID(ring+1, sector))
+	}
+	if sectors := g.Sectors(); sectors > 1 {
+		prev, next := sector-1, sector+1
+		if g.fullCircle {
+			prev, next = (prev+sectors)%sectors, next%sectors
+		}
+		if prev >= 0 {
+			result = append(result, NewCellID(ring, prev))
+		}
+		if next < sectors && next != prev {
+			result = append(result, NewCellID(ring, next))
+		}
+	}
+	return result
+}
+
+// MultiPolygon возвращает все ячейки сетки в порядке CreateRadialSectors
+func (g *RadialGrid) MultiPolygon() types.MultiPolygon {
+	return Polygons(g)
+}
+
+// Features возвращает ячейки сетки в виде набора объектов. Кроме свойств Features,
+// объект содержит ring и sector - номера кольца и сектора, inner_radius и outer_radius -
+// радиусы кольца в метрах, start_bearing и end_bearing - азимуты границ сектора в градусах
+func (g *RadialGrid) Features() *types.FeatureCollection {
+	fc := types.NewFeatureCollection()
+	fc.Features = make([]types.Feature, 0, g.Len())
+	for id := range g.Cells() {
+		cell, _ := g.Boundary(id)
+		props := cellProperties(id)
+		props["ring"] = id.Row()
+		props["sector"] = id.Col()
+		props["inner_radius"], props["outer_radius"], _ = g.RingRadii(id.Row())
+		props["start_bearing"], props["end_bearing"], _ = g.SectorBearings(id.Col())
+		fc.Features = append(fc.Features, types.NewFeature(types.NewPolygonGeometry(cell), props))
+	}
+	return fc
 }
@@ -0,0 +1,182 @@
+package grid
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// radialPoint возвращает точку на расстоянии distance метров и азимуте bearing градусов от центра
+func radialPoint(center types.Point, distance, bearing float64) types.Point {
+	return calc.CalculateDestinationPoint(center, distance, bearing*math.Pi/180)
+}
+
+func TestRadialGridOptions(t *testing.T) {
+	center := types.NewPoint(37.6173, 55.7558)
+	g, err := NewRadialGridWithOptions(37.6173, 55.7558, RadialOptions{
+		Radii:        []float64{500, 1000, 3000, 10000},
+		SectorAngles: []float64{90, 45, 45, 180},
+		StartBearing: -30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Rings() != 4 || g.Sectors() != 4 || g.Len() != 16 {
+		t.Fatalf("rings %d, sectors %d, len %d", g.Rings(), g.Sectors(), g.Len())
+	}
+	checkCells(t, g)
+
+	radii := [][2]float64{{0, 500}, {500, 1000}, {1000, 3000}, {3000, 10000}}
+	for ring, want := range radii {
+		inner, outer, ok := g.RingRadii(ring)
+		if !ok || inner != want[0] || outer != want[1] {
+			t.Errorf("RingRadii(%d) = %g, %g, %v, want %v", ring, inner, outer, ok, want)
+		}
+	}
+	if _, _, ok := g.RingRadii(4); ok {
+		t.Error("RingRadii(4) is ok")
+	}
+
+	// Первый сектор начинается на 330° и пересекает направление на север
+	bearings := [][2]float64{{330, 420}, {60, 105}, {105, 150}, {150, 330}}
+	for sector, want := range bearings {
+		start, end, ok := g.SectorBearings(sector)
+		if !ok || math.Abs(start-want[0]) > 1e-9 || math.Abs(end-want[1]) > 1e-9 {
+			t.Errorf("SectorBearings(%d) = %g, %g, %v, want %v", sector, start, end, ok, want)
+		}
+	}
+
+	tests := []struct {
+		distance, bearing float64
+		ring, sector      int
+	}{
+		{250, 0, 0, 0},
+		{250, 340, 0, 0},
+		{750, 59, 1, 0},
+		{750, 61, 1, 1},
+		{2000, 120, 2, 2},
+		{6000, 200, 3, 3},
+		{9900, 329, 3, 3},
+	}
+	for _, tt := range tests {
+		id, ok := g.Locate(radialPoint(center, tt.distance, tt.bearing))
+		if !ok || id != NewCellID(tt.ring, tt.sector) {
+			t.Errorf("Locate(%g m, %g°) = %v, %v, want ring %d sector %d",
+				tt.distance, tt.bearing, id, ok, tt.ring, tt.sector)
+		}
+	}
+	if _, ok := g.Locate(radialPoint(center, 10100, 90)); ok {
+		t.Error("point beyond the outer ring is located")
+	}
+
+	// Ячейки перебираются по секторам, внутри сектора - от центра наружу
+	i := 0
+	for id := range g.Cells() {
+		if want := NewCellID(i%4, i/4); id != want {
+			t.Fatalf("cell %d is %v, want %v", i, id, want)
+		}
+		i++
+	}
+
+	fc := g.Features()
+	if len(fc.Features) != g.Len() {
+		t.Fatalf("%d features, want %d", len(fc.Features), g.Len())
+	}
+	props := fc.Features[6].Properties
+	if props["ring"] != 2 || props["sector"] != 1 || props["inner_radius"] != 1000.0 ||
+		props["outer_radius"] != 3000.0 || props["cell_id"] != "2/1" {
+		t.Errorf("feature 6 properties = %v", props)
+	}
+	if start, end := props["start_bearing"].(float64), props["end_bearing"].(float64); math.Abs(start-60) > 1e-9 || math.Abs(end-105) > 1e-9 {
+		t.Errorf("feature 6 bearings = %g, %g, want 60, 105", start, end)
+	}
+}
+
+func TestRadialGridNeighbors(t *testing.T) {
+	full, err := NewRadialGrid(37.6173, 55.7558, 3000, 6, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, full)
+	// В полном круге первый и последний секторы соседствуют
+	if got := full.Neighbors(NewCellID(0, 0)); len(got) != 3 || !containsCell(got, NewCellID(0, 5)) ||
+		!containsCell(got, NewCellID(0, 1)) || !containsCell(got, NewCellID(1, 0)) {
+		t.Errorf("full circle neighbours of 0/0 = %v", got)
+	}
+	if got := full.Neighbors(NewCellID(1, 3)); len(got) != 4 {
+		t.Errorf("full circle neighbours of 1/3 = %v", got)
+	}
+
+	partial, err := NewRadialGridWithOptions(37.6173, 55.7558, RadialOptions{
+		Radii:        []float64{1000, 2000},
+		SectorAngles: []float64{60, 60, 60},
+		StartBearing: 45,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, partial)
+	if got := partial.Neighbors(NewCellID(0, 0)); len(got) != 2 || containsCell(got, NewCellID(0, 2)) {
+		t.Errorf("partial circle neighbours of 0/0 = %v", got)
+	}
+	// Вне секторов точка не принадлежит сетке
+	if _, ok := partial.Locate(radialPoint(partial.center, 500, 300)); ok {
+		t.Error("point outside the sectors is located")
+	}
+
+	// Два сектора полного круга соседствуют только один раз
+	two, err := NewRadialGrid(37.6173, 55.7558, 1000, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := two.Neighbors(NewCellID(0, 0)); len(got) != 1 || got[0] != NewCellID(0, 1) {
+		t.Errorf("two sector neighbours of 0/0 = %v", got)
+	}
+}
+
+func TestRadialGridInvalid(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name string
+		opts RadialOptions
+	}{
+		{"no rings", RadialOptions{}},
+		{"NaN radius", RadialOptions{Radii: []float64{100, nan}}},
+		{"infinite radius", RadialOptions{Radii: []float64{100, inf}}},
+		{"decreasing radii", RadialOptions{Radii: []float64{200, 100}}},
+		{"zero radius", RadialOptions{Radii: []float64{0, 100}}},
+		{"NaN sector angle", RadialOptions{Radii: []float64{100}, SectorAngles: []float64{nan}}},
+		{"infinite sector angle", RadialOptions{Radii: []float64{100}, SectorAngles: []float64{inf}}},
+		{"negative sector angle", RadialOptions{Radii: []float64{100}, SectorAngles: []float64{-90}}},
+		{"sectors over 360°", RadialOptions{Radii: []float64{100}, SectorAngles: []float64{180, 181}}},
+		{"NaN start bearing", RadialOptions{Radii: []float64{100}, StartBearing: nan}},
+		{"infinite start bearing", RadialOptions{Radii: []float64{100}, StartBearing: -inf}},
+		{"NaN arc spacing", RadialOptions{Radii: []float64{100}, ArcSpacing: nan}},
+		{"infinite arc spacing", RadialOptions{Radii: []float64{100}, ArcSpacing: inf}},
+		{"negative arc spacing", RadialOptions{Radii: []float64{100}, ArcSpacing: -1}},
+		{"negative arc segments", RadialOptions{Radii: []float64{100}, MinArcSegments: -1}},
+	}
+	for _, tt := range tests {
+		if _, err := NewRadialGridWithOptions(37, 55, tt.opts); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("%s: error = %v, want ErrInvalidGrid", tt.name, err)
+		}
+		if fc := CreateRadialZones(37, 55, tt.opts); len(fc.Features) != 0 {
+			t.Errorf("%s: %d zones, want none", tt.name, len(fc.Features))
+		}
+	}
+	if _, err := NewRadialGridWithOptions(nan, 55, RadialOptions{Radii: []float64{100}}); !errors.Is(err, ErrInvalidGrid) {
+		t.Errorf("NaN centre: error = %v, want ErrInvalidGrid", err)
+	}
+
+	for _, radius := range []float64{0, -1, nan, inf} {
+		if _, err := NewRadialGrid(37, 55, radius, 4, 2); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("radius %g: error = %v, want ErrInvalidGrid", radius, err)
+		}
+	}
+	if _, err := NewRadialGrid(37, 55, 1000, 4, maxGridCells+1); !errors.Is(err, ErrInvalidGrid) {
+		t.Errorf("too many rings: error = %v, want ErrInvalidGrid", err)
+	}
+}
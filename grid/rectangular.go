@@ -1,8 +1,13 @@
 package grid
 
 import (
+	"fmt"
+	"iter"
 	"math"
+	"slices"
+	"sort"
 
+	"github.com/Fliiiiii/go-geo/calc"
 	"github.com/Fliiiiii/go-geo/types"
 )
 
@@ -19,84 +24,214 @@ func CreateRectangularGrid(minLon, minLat, maxLon, maxLat, stepLon, stepLat floa
 	estimatedSize := latCount * lonCount
 	grid := make([]types.Point, 0, estimatedSize)
 
-	// Максимальный коэффициент корректировки для предотвращения слишком больших шагов вблизи полюсов
+	return slices.AppendSeq(grid, RectangularGridPoints(minLon, minLat, maxLon, maxLat, stepLon, stepLat))
+}
+
+// CreateRectangularGridCells создает сетку полигонов (ячеек) с заданными границами и шагом
+func CreateRectangularGridCells(minLon, minLat, maxLon, maxLat, stepLon, stepLat float64) types.MultiPolygon {
+	g, err := NewRectangularGrid(minLon, minLat, maxLon, maxLat, stepLon, stepLat)
+	if err != nil {
+		return types.MultiPolygon{}
+	}
+	return g.MultiPolygon()
+}
+
+// adjustedLonStep корректирует шаг по долготе для широты lat, чтобы ячейки сохраняли примерно
+// одинаковую ширину в километрах. Коэффициент ограничен, чтобы шаг не рос неограниченно у полюсов
+func adjustedLonStep(lat, stepLon float64) float64 {
+	// Максимальный коэффициент корректировки
 	const maxAdjustmentFactor = 10.0
 
-	// Учитываем, что длина одного градуса долготы зависит от широты
-	for lat := minLat; lat <= maxLat; lat += stepLat {
-		// Косинус широты для корректировки шага по долготе
-		cosLat := math.Cos(lat * math.Pi / 180.0)
+	// Косинус широты для корректировки шага по долготе
+	cosLat := math.Cos(lat * math.Pi / 180.0)
+	cosLat = max(cosLat, 0.1) // Увеличен минимальный косинус
 
-		// Предотвращаем деление на слишком маленькие значения
-		cosLat = max(cosLat, 0.1) // Увеличен минимальный косинус для предотвращения слишком больших шагов
+	// Корректируем шаг долготы с ограничением
+	adjustmentFactor := 1.0 / cosLat
+	adjustmentFactor = math.Min(adjustmentFactor, maxAdjustmentFactor)
+	return stepLon * adjustmentFactor
+}
 
-		// Корректируем шаг долготы с ограничением максимального фактора
-		adjustmentFactor := 1.0 / cosLat
-		adjustmentFactor = math.Min(adjustmentFactor, maxAdjustmentFactor)
-		adjustedStepLon := stepLon * adjustmentFactor
+// rectangularRow - ряд прямоугольной сетки
+type rectangularRow struct {
+	lat     float64
+	nextLat float64
+	stepLon float64
+	// lons - западные границы ячеек ряда
+	lons []float64
+}
 
-		for lon := minLon; lon <= maxLon; lon += adjustedStepLon {
-			// Убедимся, что не выходим за пределы maxLon
-			if lon > maxLon {
-				lon = maxLon
-			}
-			grid = append(grid, types.NewPoint(lon, lat))
+// RectangularGrid - прямоугольная сетка с коррекцией шага по долготе, повторяющая ячейки
+// CreateRectangularGridCells. Границы рядов и столбцов вычисляются при создании,
+// поэтому поиск ячейки точки не требует перебора ячеек
+type RectangularGrid struct {
+	bounds  calc.BoundingBox
+	stepLon float64
+	stepLat float64
+	rows    []rectangularRow
+}
+
+// NewRectangularGrid создает прямоугольную сетку с заданными границами и шагом в градусах.
+// Шаг по долготе увеличивается с широтой ряда так же, как в CreateRectangularGridCells
+func NewRectangularGrid(minLon, minLat, maxLon, maxLat, stepLon, stepLat float64) (*RectangularGrid, error) {
+	if minLon > maxLon || minLat > maxLat || stepLon <= 0 || stepLat <= 0 {
+		return nil, fmt.Errorf("rectangular grid [%g, %g, %g, %g] with step %g x %g: %w",
+			minLon, minLat, maxLon, maxLat, stepLon, stepLat, ErrInvalidGrid)
+	}
+
+	g := &RectangularGrid{
+		bounds:  calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat},
+		stepLon: stepLon,
+		stepLat: stepLat,
+	}
+
+	// Границы накапливаются сложением, как при генерации ячеек, чтобы совпадать с ними до бита
+	for lat := minLat; lat < maxLat; lat += stepLat {
+		row := rectangularRow{
+			lat:     lat,
+			nextLat: min(lat+stepLat, maxLat),
+			stepLon: adjustedLonStep(lat, stepLon),
 		}
+		for lon := minLon; lon < maxLon; lon += row.stepLon {
+			row.lons = append(row.lons, lon)
+		}
+		g.rows = append(g.rows, row)
 	}
+	return g, nil
+}
 
-	return grid
+// Bounds возвращает границы сетки
+func (g *RectangularGrid) Bounds() calc.BoundingBox {
+	return g.bounds
 }
 
-// CreateRectangularGridCells создает сетку полигонов (ячеек) с заданными границами и шагом
-func CreateRectangularGridCells(minLon, minLat, maxLon, maxLat, stepLon, stepLat float64) types.MultiPolygon {
-	// Проверяем корректность границ
-	if minLon > maxLon || minLat > maxLat || stepLon <= 0 || stepLat <= 0 {
-		return types.MultiPolygon{}
+// Rows возвращает количество рядов сетки
+func (g *RectangularGrid) Rows() int {
+	return len(g.rows)
+}
+
+// Cols возвращает количество ячеек в ряду row
+func (g *RectangularGrid) Cols(row int) int {
+	if row < 0 || row >= len(g.rows) {
+		return 0
 	}
+	return len(g.rows[row].lons)
+}
 
-	// Оценка размера сетки для предварительного выделения памяти
-	latCount := int(math.Ceil((maxLat - minLat) / stepLat))
-	lonCount := int(math.Ceil((maxLon - minLon) / stepLon))
-	estimatedSize := latCount * lonCount
-	gridCells := make(types.MultiPolygon, 0, estimatedSize)
+// Len возвращает общее количество ячеек сетки
+func (g *RectangularGrid) Len() int {
+	n := 0
+	for _, row := range g.rows {
+		n += len(row.lons)
+	}
+	return n
+}
 
-	// Максимальный коэффициент корректировки
-	const maxAdjustmentFactor = 10.0
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *RectangularGrid) Contains(id CellID) bool {
+	return id.Col() < g.Cols(id.Row())
+}
 
-	for lat := minLat; lat < maxLat; lat += stepLat {
-		// Верхняя граница ячейки
-		nextLat := lat + stepLat
-		if nextLat > maxLat {
-			nextLat = maxLat
-		}
+// cellBounds возвращает границы ячейки
+func (g *RectangularGrid) cellBounds(id CellID) (calc.BoundingBox, bool) {
+	if !g.Contains(id) {
+		return calc.BoundingBox{}, false
+	}
+	row := g.rows[id.Row()]
+	lon := row.lons[id.Col()]
+	return calc.BoundingBox{
+		MinLon: lon,
+		MinLat: row.lat,
+		MaxLon: min(lon+row.stepLon, g.bounds.MaxLon),
+		MaxLat: row.nextLat,
+	}, true
+}
+
+// Boundary возвращает полигон ячейки. Возвращает false, если ячейки нет в сетке
+func (g *RectangularGrid) Boundary(id CellID) (types.Polygon, bool) {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return nil, false
+	}
+	return boxPolygon(box), true
+}
+
+// Center возвращает центр ячейки. Возвращает false, если ячейки нет в сетке
+func (g *RectangularGrid) Center(id CellID) (types.Point, bool) {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return nil, false
+	}
+	return types.NewPoint((box.MinLon+box.MaxLon)/2, (box.MinLat+box.MaxLat)/2), true
+}
+
+// Locate возвращает ячейку, содержащую точку. Ячейка включает западную и южную границы;
+// восточная и северная границы сетки относятся к крайним ячейкам
+func (g *RectangularGrid) Locate(p types.Point) (CellID, bool) {
+	lon, lat := p.GetLongitude(), p.GetLatitude()
+	if len(g.rows) == 0 || lon < g.bounds.MinLon || lon > g.bounds.MaxLon ||
+		lat < g.bounds.MinLat || lat > g.bounds.MaxLat {
+		return 0, false
+	}
 
-		// Косинус широты для корректировки шага по долготе
-		cosLat := math.Cos(lat * math.Pi / 180.0)
-		cosLat = max(cosLat, 0.1) // Увеличен минимальный косинус
+	r := locateStep(len(g.rows), (lat-g.bounds.MinLat)/g.stepLat, func(i int) float64 { return g.rows[i].lat }, lat)
+	row := g.rows[r]
+	c := locateStep(len(row.lons), (lon-g.bounds.MinLon)/row.stepLon, func(i int) float64 { return row.lons[i] }, lon)
+	return NewCellID(r, c), true
+}
 
-		// Корректируем шаг долготы с ограничением
-		adjustmentFactor := 1.0 / cosLat
-		adjustmentFactor = math.Min(adjustmentFactor, maxAdjustmentFactor)
-		adjustedStepLon := stepLon * adjustmentFactor
+// locateStep находит индекс интервала, содержащего v, по оценке estimate и уточняет его
+// по накопленным границам start
+func locateStep(n int, estimate float64, start func(int) float64, v float64) int {
+	i := min(max(int(estimate), 0), n-1)
+	for i > 0 && v < start(i) {
+		i--
+	}
+	for i < n-1 && v >= start(i+1) {
+		i++
+	}
+	return i
+}
 
-		for lon := minLon; lon < maxLon; lon += adjustedStepLon {
-			// Правая граница ячейки
-			nextLon := lon + adjustedStepLon
-			if nextLon > maxLon {
-				nextLon = maxLon
+// Neighbors возвращает ячейки, имеющие с данной общую сторону или угол. Ширина ячеек
+// соседних рядов отличается, поэтому соседей сверху и снизу может быть больше трех
+func (g *RectangularGrid) Neighbors(id CellID) []CellID {
+	box, ok := g.cellBounds(id)
+	if !ok {
+		return nil
+	}
+
+	var result []CellID
+	for r := id.Row() - 1; r <= id.Row()+1; r++ {
+		if r < 0 || r >= len(g.rows) {
+			continue
+		}
+		if r == id.Row() {
+			if id.Col() > 0 {
+				result = append(result, NewCellID(r, id.Col()-1))
 			}
+			if id.Col()+1 < len(g.rows[r].lons) {
+				result = append(result, NewCellID(r, id.Col()+1))
+			}
+			continue
+		}
 
-			// Создаем полигон напрямую по координатам углов
-			cell := types.NewPolygon(types.NewLineString(
-				types.NewPoint(lon, lat),         // Нижний левый угол
-				types.NewPoint(nextLon, lat),     // Нижний правый угол
-				types.NewPoint(nextLon, nextLat), // Верхний правый угол
-				types.NewPoint(lon, nextLat),     // Верхний левый угол
-				types.NewPoint(lon, lat),         // Замыкаем полигон
-			))
-			gridCells = append(gridCells, cell)
+		row := g.rows[r]
+		first := sort.Search(len(row.lons), func(i int) bool { return row.lons[i]+row.stepLon >= box.MinLon })
+		last := sort.Search(len(row.lons), func(i int) bool { return row.lons[i] > box.MaxLon })
+		for c := first; c < last; c++ {
+			result = append(result, NewCellID(r, c))
 		}
 	}
+	return result
+}
+
+// Cells перебирает ячейки сетки по рядам с юга на север
+func (g *RectangularGrid) Cells() iter.Seq[CellID] {
+	return rowMajor(len(g.rows), g.Cols)
+}
 
-	return gridCells
+// MultiPolygon возвращает все ячейки сетки в порядке рядов с юга на север
+func (g *RectangularGrid) MultiPolygon() types.MultiPolygon {
+	return Polygons(g)
 }
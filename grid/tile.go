@@ -0,0 +1,278 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/proj"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// MaxTileZoom - максимальный поддерживаемый уровень масштаба тайлов
+const MaxTileZoom = 30
+
+// DefaultTileSize - размер тайла в пикселях, используемый веб-картами
+const DefaultTileSize = 256
+
+// ErrInvalidTile возвращается для тайла с координатами вне допустимого диапазона
+var ErrInvalidTile = errors.New("invalid tile")
+
+// Tile - тайл веб-карты в схеме XYZ (ось Y направлена на юг, тайл 0/0/0 покрывает весь мир)
+type Tile struct {
+	X, Y, Z int
+}
+
+// NewTile создает тайл и проверяет его координаты
+func NewTile(x, y, z int) (Tile, error) {
+	t := Tile{X: x, Y: y, Z: z}
+	if !t.IsValid() {
+		return Tile{}, fmt.Errorf("tile %d/%d/%d: %w", z, x, y, ErrInvalidTile)
+	}
+	return t, nil
+}
+
+// NewTileFromTMS создает тайл по координатам схемы TMS (ось Y направлена на север)
+func NewTileFromTMS(x, y, z int) (Tile, error) {
+	if z < 0 || z > MaxTileZoom {
+		return Tile{}, fmt.Errorf("tms tile %d/%d/%d: %w", z, x, y, ErrInvalidTile)
+	}
+	return NewTile(x, 1<<z-1-y, z)
+}
+
+// PointToTile возвращает тайл заданного масштаба, содержащий точку.
+// Широта ограничивается пределом проекции Web Mercator, масштаб - диапазоном [0, MaxTileZoom]
+func PointToTile(p types.Point, zoom int) Tile {
+	zoom = max(0, min(zoom, MaxTileZoom))
+	fx, fy := tileFraction(p, zoom)
+	n := 1 << zoom
+	return Tile{
+		X: max(0, min(n-1, int(math.Floor(fx)))),
+		Y: max(0, min(n-1, int(math.Floor(fy)))),
+		Z: zoom,
+	}
+}
+
+// tileFraction вычисляет дробные координаты точки в сетке тайлов масштаба zoom
+func tileFraction(p types.Point, zoom int) (float64, float64) {
+	n := float64(uint64(1) << zoom)
+	lon := p.GetLongitude()
+	lat := math.Max(-proj.MaxWebMercatorLatitude, math.Min(proj.MaxWebMercatorLatitude, p.GetLatitude()))
+
+	latRad := lat * math.Pi / 180
+	x := (lon + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n
+	return x, y
+}
+
+// tileLongitude возвращает долготу западной границы столбца тайлов x
+func tileLongitude(x float64, zoom int) float64 {
+	return x/float64(uint64(1)<<zoom)*360 - 180
+}
+
+// tileLatitude возвращает широту северной границы строки тайлов y
+func tileLatitude(y float64, zoom int) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/float64(uint64(1)<<zoom)))) * 180 / math.Pi
+}
+
+// IsValid проверяет, что координаты тайла лежат в допустимом диапазоне
+func (t Tile) IsValid() bool {
+	if t.Z < 0 || t.Z > MaxTileZoom {
+		return false
+	}
+	n := 1 << t.Z
+	return t.X >= 0 && t.X < n && t.Y >= 0 && t.Y < n
+}
+
+// String возвращает запись тайла вида "z/x/y"
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// TMSY возвращает координату Y тайла в схеме TMS
+func (t Tile) TMSY() int {
+	return 1<<t.Z - 1 - t.Y
+}
+
+// Bounds возвращает границы тайла в градусах
+func (t Tile) Bounds() calc.BoundingBox {
+	return calc.BoundingBox{
+		MinLon: tileLongitude(float64(t.X), t.Z),
+		MinLat: tileLatitude(float64(t.Y+1), t.Z),
+		MaxLon: tileLongitude(float64(t.X+1), t.Z),
+		MaxLat: tileLatitude(float64(t.Y), t.Z),
+	}
+}
+
+// Polygon возвращает тайл в виде полигона
+func (t Tile) Polygon() types.Polygon {
+	return boxPolygon(t.Bounds())
+}
+
+// Center возвращает точку, соответствующую центру тайла на карте в проекции Web Mercator
+func (t Tile) Center() types.Point {
+	return types.NewPoint(tileLongitude(float64(t.X)+0.5, t.Z), tileLatitude(float64(t.Y)+0.5, t.Z))
+}
+
+// Parent возвращает родительский тайл. Для тайла нулевого масштаба возвращается он сам
+func (t Tile) Parent() Tile {
+	if t.Z == 0 {
+		return t
+	}
+	return Tile{X: t.X >> 1, Y: t.Y >> 1, Z: t.Z - 1}
+}
+
+// Children возвращает четыре дочерних тайла: северо-западный, северо-восточный,
+// юго-восточный и юго-западный. У тайла масштаба MaxTileZoom дочерних тайлов нет:
+// возвращаемые тайлы имеют масштаб MaxTileZoom+1 и не проходят проверку IsValid
+func (t Tile) Children() [4]Tile {
+	x, y, z := t.X<<1, t.Y<<1, t.Z+1
+	return [4]Tile{
+		{X: x, Y: y, Z: z},
+		{X: x + 1, Y: y, Z: z},
+		{X: x + 1, Y: y + 1, Z: z},
+		{X: x, Y: y + 1, Z: z},
+	}
+}
+
+// QuadKey возвращает ключ тайла в схеме Bing Maps. Для тайла нулевого масштаба ключ пустой
+func (t Tile) QuadKey() string {
+	var sb strings.Builder
+	sb.Grow(t.Z)
+	for z := t.Z; z > 0; z-- {
+		digit := byte('0')
+		mask := 1 << (z - 1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		sb.WriteByte(digit)
+	}
+	return sb.String()
+}
+
+// TileFromQuadKey разбирает ключ тайла в схеме Bing Maps
+func TileFromQuadKey(quadKey string) (Tile, error) {
+	if len(quadKey) > MaxTileZoom {
+		return Tile{}, fmt.Errorf("quadkey %q longer than %d digits: %w", quadKey, MaxTileZoom, ErrInvalidTile)
+	}
+
+	t := Tile{Z: len(quadKey)}
+	for i := 0; i < len(quadKey); i++ {
+		mask := 1 << (t.Z - i - 1)
+		switch quadKey[i] {
+		case '0':
+		case '1':
+			t.X |= mask
+		case '2':
+			t.Y |= mask
+		case '3':
+			t.X |= mask
+			t.Y |= mask
+		default:
+			return Tile{}, fmt.Errorf("unexpected digit %q in quadkey %q: %w", quadKey[i], quadKey, ErrInvalidTile)
+		}
+	}
+	return t, nil
+}
+
+// PointToPixel возвращает координаты точки в пикселях относительно левого верхнего угла тайла.
+// Для точек вне тайла координаты выходят за пределы [0, tileSize]
+func (t Tile) PointToPixel(p types.Point, tileSize int) (float64, float64) {
+	fx, fy := tileFraction(p, t.Z)
+	return (fx - float64(t.X)) * float64(tileSize), (fy - float64(t.Y)) * float64(tileSize)
+}
+
+// PixelToPoint возвращает географические координаты пикселя тайла
+func (t Tile) PixelToPoint(px, py float64, tileSize int) types.Point {
+	x := float64(t.X) + px/float64(tileSize)
+	y := float64(t.Y) + py/float64(tileSize)
+	return types.NewPoint(tileLongitude(x, t.Z), tileLatitude(y, t.Z))
+}
+
+// TilesCoveringBoundingBox возвращает тайлы масштаба zoom, пересекающие прямоугольник.
+// Если MinLon больше MaxLon, прямоугольник считается пересекающим 180-й меридиан
+func TilesCoveringBoundingBox(bbox calc.BoundingBox, zoom int) []Tile {
+	zoom = max(0, min(zoom, MaxTileZoom))
+	if bbox.MinLon > bbox.MaxLon {
+		east := calc.BoundingBox{MinLon: bbox.MinLon, MinLat: bbox.MinLat, MaxLon: 180, MaxLat: bbox.MaxLat}
+		west := calc.BoundingBox{MinLon: -180, MinLat: bbox.MinLat, MaxLon: bbox.MaxLon, MaxLat: bbox.MaxLat}
+		return append(TilesCoveringBoundingBox(east, zoom), TilesCoveringBoundingBox(west, zoom)...)
+	}
+
+	x0, y0, x1, y1 := tileRange(bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat, zoom)
+	result := make([]Tile, 0, (x1-x0+1)*(y1-y0+1))
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			result = append(result, Tile{X: x, Y: y, Z: zoom})
+		}
+	}
+	return result
+}
+
+// TilesCoveringPolygon возвращает тайлы масштаба zoom, имеющие общие точки с полигоном
+func TilesCoveringPolygon(polygon types.Polygon, zoom int) []Tile {
+	if len(polygon) == 0 || len(polygon[0]) < 3 {
+		return nil
+	}
+	zoom = max(0, min(zoom, MaxTileZoom))
+
+	minLon, minLat := math.Inf(1), math.Inf(1)
+	maxLon, maxLat := math.Inf(-1), math.Inf(-1)
+	for _, p := range polygon[0] {
+		minLon = math.Min(minLon, p.GetLongitude())
+		minLat = math.Min(minLat, p.GetLatitude())
+		maxLon = math.Max(maxLon, p.GetLongitude())
+		maxLat = math.Max(maxLat, p.GetLatitude())
+	}
+
+	region := calc.NewPreparedPolygon(polygon)
+	x0, y0, x1, y1 := tileRange(minLon, minLat, maxLon, maxLat, zoom)
+
+	var result []Tile
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			t := Tile{X: x, Y: y, Z: zoom}
+			if region.IntersectsPolygon(t.Polygon()) {
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
+
+// CreateTileGrid создает сетку ячеек, совпадающих с тайлами масштаба zoom
+// minLon, minLat - координаты юго-западного угла
+// maxLon, maxLat - координаты северо-восточного угла
+func CreateTileGrid(minLon, minLat, maxLon, maxLat float64, zoom int) types.MultiPolygon {
+	var M types.MultiPolygon
+
+	// Проверяем корректность границ
+	if minLat > maxLat || minLon > maxLon {
+		return M
+	}
+
+	bbox := calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat}
+	for _, t := range TilesCoveringBoundingBox(bbox, zoom) {
+		M = append(M, t.Polygon())
+	}
+	return M
+}
+
+// tileRange возвращает диапазон номеров тайлов, покрывающих прямоугольник.
+// Тайлы, которых прямоугольник касается только восточной или южной границей, не включаются
+func tileRange(minLon, minLat, maxLon, maxLat float64, zoom int) (x0, y0, x1, y1 int) {
+	n := 1 << zoom
+	fx0, fy0 := tileFraction(types.NewPoint(minLon, maxLat), zoom)
+	fx1, fy1 := tileFraction(types.NewPoint(maxLon, minLat), zoom)
+
+	x0 = max(0, min(n-1, int(math.Floor(fx0))))
+	y0 = max(0, min(n-1, int(math.Floor(fy0))))
+	x1 = max(x0, min(n-1, int(math.Ceil(fx1))-1))
+	y1 = max(y0, min(n-1, int(math.Ceil(fy1))-1))
+	return x0, y0, x1, y1
+}
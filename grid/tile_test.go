@@ -0,0 +1,176 @@
+package grid
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestTileQuadKey(t *testing.T) {
+	// Пример из описания схемы Bing Maps
+	tile := Tile{X: 3, Y: 5, Z: 3}
+	if got := tile.QuadKey(); got != "213" {
+		t.Errorf("QuadKey = %q, want 213", got)
+	}
+	if got := (Tile{}).QuadKey(); got != "" {
+		t.Errorf("zoom 0 QuadKey = %q, want empty", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		z := r.Intn(MaxTileZoom + 1)
+		tile := Tile{X: r.Intn(1 << z), Y: r.Intn(1 << z), Z: z}
+		got, err := TileFromQuadKey(tile.QuadKey())
+		if err != nil || got != tile {
+			t.Fatalf("round trip of %v gives %v, %v", tile, got, err)
+		}
+	}
+
+	for _, key := range []string{"0124", "12a", "0000000000000000000000000000000"} {
+		if _, err := TileFromQuadKey(key); !errors.Is(err, ErrInvalidTile) {
+			t.Errorf("TileFromQuadKey(%q) error = %v, want ErrInvalidTile", key, err)
+		}
+	}
+}
+
+func TestTileTMS(t *testing.T) {
+	// В масштабе 1 северная строка XYZ - верхняя строка TMS
+	tile, err := NewTileFromTMS(1, 1, 1)
+	if err != nil || tile != (Tile{X: 1, Y: 0, Z: 1}) {
+		t.Errorf("NewTileFromTMS(1, 1, 1) = %v, %v", tile, err)
+	}
+
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		z := r.Intn(MaxTileZoom + 1)
+		tile := Tile{X: r.Intn(1 << z), Y: r.Intn(1 << z), Z: z}
+		got, err := NewTileFromTMS(tile.X, tile.TMSY(), tile.Z)
+		if err != nil || got != tile {
+			t.Fatalf("TMS round trip of %v gives %v, %v", tile, got, err)
+		}
+	}
+
+	for _, c := range [][3]int{{0, 2, 1}, {-1, 0, 1}, {0, 0, -1}, {0, 0, MaxTileZoom + 1}} {
+		if _, err := NewTileFromTMS(c[0], c[1], c[2]); !errors.Is(err, ErrInvalidTile) {
+			t.Errorf("NewTileFromTMS%v error = %v, want ErrInvalidTile", c, err)
+		}
+		if _, err := NewTile(c[0], c[1], c[2]); !errors.Is(err, ErrInvalidTile) {
+			t.Errorf("NewTile%v error = %v, want ErrInvalidTile", c, err)
+		}
+	}
+}
+
+func TestTileHierarchy(t *testing.T) {
+	tile := Tile{X: 618, Y: 320, Z: 10}
+	bounds := tile.Bounds()
+	for _, child := range tile.Children() {
+		if !child.IsValid() || child.Parent() != tile {
+			t.Errorf("child %v of %v", child, tile)
+		}
+		b := child.Bounds()
+		if b.MinLon < bounds.MinLon || b.MaxLon > bounds.MaxLon || b.MinLat < bounds.MinLat || b.MaxLat > bounds.MaxLat {
+			t.Errorf("child %v is outside of %v", child, tile)
+		}
+	}
+	if (Tile{}).Parent() != (Tile{}) {
+		t.Error("parent of the zoom 0 tile is not itself")
+	}
+
+	// У тайла наибольшего масштаба дочерние тайлы недопустимы
+	deepest := Tile{X: 1 << 29, Y: 1 << 29, Z: MaxTileZoom}
+	if !deepest.IsValid() {
+		t.Fatalf("%v is invalid", deepest)
+	}
+	for _, child := range deepest.Children() {
+		if child.IsValid() || child.Z != MaxTileZoom+1 {
+			t.Errorf("child %v of the deepest tile is valid", child)
+		}
+	}
+}
+
+func TestPointToTile(t *testing.T) {
+	if got := PointToTile(types.NewPoint(37.6173, 55.7558), 10); got != (Tile{X: 619, Y: 320, Z: 10}) {
+		t.Errorf("Moscow tile = %v, want 10/619/320", got)
+	}
+	// Широта за пределом Web Mercator и масштаб вне диапазона ограничиваются
+	if got := PointToTile(types.NewPoint(180, 89.9), 2); got != (Tile{X: 3, Y: 0, Z: 2}) {
+		t.Errorf("north-east corner tile = %v, want 2/3/0", got)
+	}
+	if got := PointToTile(types.NewPoint(-180, -89.9), 40); got != (Tile{X: 0, Y: 1<<MaxTileZoom - 1, Z: MaxTileZoom}) {
+		t.Errorf("south-west corner tile = %v", got)
+	}
+
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 1000; i++ {
+		p := types.NewPoint(r.Float64()*360-180, r.Float64()*170-85)
+		tile := PointToTile(p, r.Intn(MaxTileZoom+1))
+		b := tile.Bounds()
+		if p.GetLongitude() < b.MinLon || p.GetLongitude() > b.MaxLon || p.GetLatitude() < b.MinLat || p.GetLatitude() > b.MaxLat {
+			t.Fatalf("%v is outside of its tile %v %+v", p, tile, b)
+		}
+	}
+}
+
+func TestTilePixels(t *testing.T) {
+	tile := Tile{X: 618, Y: 320, Z: 10}
+	b := tile.Bounds()
+
+	// Левый верхний угол тайла - северо-западная вершина, правый нижний - юго-восточная
+	corners := []struct {
+		px, py   float64
+		lon, lat float64
+	}{
+		{0, 0, b.MinLon, b.MaxLat},
+		{DefaultTileSize, DefaultTileSize, b.MaxLon, b.MinLat},
+		{0, DefaultTileSize, b.MinLon, b.MinLat},
+	}
+	for _, c := range corners {
+		p := tile.PixelToPoint(c.px, c.py, DefaultTileSize)
+		if math.Abs(p.GetLongitude()-c.lon) > 1e-9 || math.Abs(p.GetLatitude()-c.lat) > 1e-9 {
+			t.Errorf("PixelToPoint(%g, %g) = %v, want [%g %g]", c.px, c.py, p, c.lon, c.lat)
+		}
+	}
+	center := tile.PixelToPoint(DefaultTileSize/2, DefaultTileSize/2, DefaultTileSize)
+	if math.Abs(center.GetLongitude()-tile.Center().GetLongitude()) > 1e-9 || math.Abs(center.GetLatitude()-tile.Center().GetLatitude()) > 1e-9 {
+		t.Errorf("centre pixel = %v, tile centre %v", center, tile.Center())
+	}
+
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 1000; i++ {
+		px, py := r.Float64()*512, r.Float64()*512
+		x, y := tile.PointToPixel(tile.PixelToPoint(px, py, 512), 512)
+		if math.Abs(x-px) > 1e-6 || math.Abs(y-py) > 1e-6 {
+			t.Fatalf("pixel round trip of (%g, %g) gives (%g, %g)", px, py, x, y)
+		}
+	}
+
+	// Точка вне тайла дает пиксели за пределами тайла
+	if x, y := tile.PointToPixel(types.NewPoint(b.MaxLon+1, b.MaxLat+1), DefaultTileSize); x <= DefaultTileSize || y >= 0 {
+		t.Errorf("pixel of a point north-east of the tile = (%g, %g)", x, y)
+	}
+}
+
+func TestTilesCoveringBoundingBox(t *testing.T) {
+	box := calc.BoundingBox{MinLon: 10, MinLat: -10, MaxLon: 100, MaxLat: 10}
+	got := TilesCoveringBoundingBox(box, 2)
+	want := []Tile{{X: 2, Y: 1, Z: 2}, {X: 3, Y: 1, Z: 2}, {X: 2, Y: 2, Z: 2}, {X: 3, Y: 2, Z: 2}}
+	if !slices.Equal(got, want) {
+		t.Errorf("tiles = %v, want %v", got, want)
+	}
+
+	// Прямоугольник через 180-й меридиан покрывается тайлами по обе стороны от него
+	across := TilesCoveringBoundingBox(calc.BoundingBox{MinLon: 170, MinLat: 1, MaxLon: -170, MaxLat: 2}, 3)
+	if want := []Tile{{X: 7, Y: 3, Z: 3}, {X: 0, Y: 3, Z: 3}}; !slices.Equal(across, want) {
+		t.Errorf("antimeridian tiles = %v, want %v", across, want)
+	}
+
+	// Касание восточной и южной границы тайла не добавляет соседний тайл
+	if got := TilesCoveringBoundingBox(calc.BoundingBox{MinLon: -180, MinLat: 0, MaxLon: 0, MaxLat: 85}, 1); !slices.Equal(got, []Tile{{X: 0, Y: 0, Z: 1}}) {
+		t.Errorf("quarter tiles = %v, want [1/0/0]", got)
+	}
+}
@@ -0,0 +1,240 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"sort"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// CreateTriangularGrid создает сетку треугольников с заданными параметрами
+// minLon, minLat - координаты юго-западного угла
+// maxLon, maxLat - координаты северо-восточного угла
+// side - сторона треугольника в градусах долготы
+func CreateTriangularGrid(minLon, minLat, maxLon, maxLat, side float64) types.MultiPolygon {
+	var M types.MultiPolygon
+
+	g, err := NewTriangularGrid(minLon, minLat, maxLon, maxLat, side)
+	if err != nil {
+		return M // Возвращаем пустую карту при некорректных границах
+	}
+	return g.MultiPolygon()
+}
+
+// latitudeFactor возвращает коэффициент коррекции высоты ряда на широте lat.
+// Как и в гексагональной сетке, высота ряда в градусах уменьшается пропорционально
+// косинусу широты, чтобы ячейки сохраняли форму; у полюсов коэффициент ограничен снизу
+func latitudeFactor(lat float64) float64 {
+	return max(math.Cos(lat*math.Pi/180), 0.1)
+}
+
+// TriangularGrid - сетка треугольников, близких к правильным. Каждый ряд состоит из
+// треугольников, попеременно обращенных вершиной на север и на юг; соседние ряды
+// имеют общие вершины, поэтому сетка не содержит зазоров.
+// Ячейка с четной суммой номеров ряда и столбца обращена вершиной на север
+type TriangularGrid struct {
+	bounds calc.BoundingBox
+	side   float64
+	// lats - широты границ рядов, на одну больше количества рядов
+	lats []float64
+	cols int
+}
+
+// NewTriangularGrid создает треугольную сетку, покрывающую прямоугольник.
+// side - сторона треугольника в градусах долготы, высота ряда равна side·√3/2
+// с поправкой на широту. Границы ограничиваются допустимым диапазоном координат.
+// Возвращает ErrInvalidGrid, если рядов или ячеек в ряду получается больше 2^20
+func NewTriangularGrid(minLon, minLat, maxLon, maxLat, side float64) (*TriangularGrid, error) {
+	if !(minLat <= maxLat) || !(minLon <= maxLon) || !positiveFinite(side) {
+		return nil, fmt.Errorf("triangular grid [%g, %g, %g, %g] with side %g: %w",
+			minLon, minLat, maxLon, maxLat, side, ErrInvalidGrid)
+	}
+
+	// Ограничиваем широту до диапазона [-90, 90] и долготу до [-180, 180]
+	minLat, maxLat = max(minLat, -90), min(maxLat, 90)
+	minLon, maxLon = max(minLon, -180), min(maxLon, 180)
+
+	g := &TriangularGrid{
+		bounds: calc.BoundingBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat},
+		side:   side,
+		lats:   []float64{minLat},
+	}
+
+	height := side * math.Sqrt(3) / 2
+	for lat := minLat; lat < maxLat; {
+		lat = min(lat+height*latitudeFactor(lat), 90)
+		g.lats = append(g.lats, lat)
+		if len(g.lats) > maxGridCells+1 {
+			return nil, fmt.Errorf("triangular grid [%g, %g, %g, %g] with side %g needs more than %d rows: %w",
+				minLon, minLat, maxLon, maxLat, side, maxGridCells, ErrInvalidGrid)
+		}
+	}
+	if len(g.lats) == 1 {
+		// Вырожденный по широте прямоугольник покрывается одним рядом
+		g.lats = append(g.lats, min(minLat+height*latitudeFactor(minLat), 90))
+	}
+
+	// Первый треугольник начинается на полстороны западнее границы, чтобы ряды
+	// покрывали прямоугольник по всей высоте
+	cols := math.Ceil((maxLon-g.originLon())/(side/2)) + 1
+	if cols > maxGridCells {
+		return nil, fmt.Errorf("triangular grid [%g, %g, %g, %g] with side %g needs %g cells in a row: %w",
+			minLon, minLat, maxLon, maxLat, side, cols, ErrInvalidGrid)
+	}
+	g.cols = int(cols)
+	return g, nil
+}
+
+// originLon возвращает долготу западной вершины первого треугольника ряда
+func (g *TriangularGrid) originLon() float64 {
+	return g.bounds.MinLon - g.side/2
+}
+
+// Bounds возвращает границы сетки после ограничения диапазоном координат
+func (g *TriangularGrid) Bounds() calc.BoundingBox {
+	return g.bounds
+}
+
+// Side возвращает сторону треугольников в градусах долготы
+func (g *TriangularGrid) Side() float64 {
+	return g.side
+}
+
+// Rows возвращает количество рядов сетки
+func (g *TriangularGrid) Rows() int {
+	return len(g.lats) - 1
+}
+
+// Cols возвращает количество треугольников в ряду
+func (g *TriangularGrid) Cols() int {
+	return g.cols
+}
+
+// Len возвращает общее количество ячеек сетки
+func (g *TriangularGrid) Len() int {
+	return g.Rows() * g.cols
+}
+
+// Contains проверяет, что идентификатор соответствует ячейке сетки
+func (g *TriangularGrid) Contains(id CellID) bool {
+	return id.Row() < g.Rows() && id.Col() < g.cols
+}
+
+// pointsNorth проверяет, что треугольник обращен вершиной на север
+func pointsNorth(row, col int) bool {
+	return (row+col)%2 == 0
+}
+
+// Boundary возвращает треугольник ячейки. Возвращает false, если ячейки нет в сетке
+func (g *TriangularGrid) Boundary(id CellID) (types.Polygon, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	row, col := id.Row(), id.Col()
+	west := g.originLon() + float64(col)*g.side/2
+	south, north := g.lats[row], g.lats[row+1]
+
+	var ring types.LineString
+	if pointsNorth(row, col) {
+		ring = types.NewLineString(
+			types.NewPoint(west, south),
+			types.NewPoint(west+g.side, south),
+			types.NewPoint(west+g.side/2, north),
+			types.NewPoint(west, south),
+		)
+	} else {
+		ring = types.NewLineString(
+			types.NewPoint(west+g.side/2, south),
+			types.NewPoint(west+g.side, north),
+			types.NewPoint(west, north),
+			types.NewPoint(west+g.side/2, south),
+		)
+	}
+	return types.NewPolygon(ring), true
+}
+
+// Center возвращает центр тяжести треугольника ячейки. Возвращает false, если ячейки нет в сетке
+func (g *TriangularGrid) Center(id CellID) (types.Point, bool) {
+	if !g.Contains(id) {
+		return nil, false
+	}
+	row, col := id.Row(), id.Col()
+	lon := g.originLon() + float64(col)*g.side/2 + g.side/2
+	south, north := g.lats[row], g.lats[row+1]
+	if pointsNorth(row, col) {
+		return types.NewPoint(lon, south+(north-south)/3), true
+	}
+	return types.NewPoint(lon, north-(north-south)/3), true
+}
+
+// Locate возвращает ячейку, треугольник которой содержит точку. Точка на общей стороне
+// двух треугольников относится к восточному, на границе рядов - к северному ряду
+func (g *TriangularGrid) Locate(p types.Point) (CellID, bool) {
+	lon, lat := p.GetLongitude(), p.GetLatitude()
+	rows := g.Rows()
+	if lat < g.lats[0] || lat > g.lats[rows] {
+		return 0, false
+	}
+
+	row := sort.Search(rows, func(i int) bool { return g.lats[i+1] > lat })
+	if row == rows {
+		row-- // северная граница сетки относится к последнему ряду
+	}
+
+	// Положение точки внутри ряда: x - в половинах стороны от начала ряда, t - доля высоты ряда
+	x := (lon - g.originLon()) / (g.side / 2)
+	t := (lat - g.lats[row]) / (g.lats[row+1] - g.lats[row])
+
+	// Точка лежит в треугольнике floor(x) или в предыдущем: граница между ними -
+	// сторона, идущая из (col, 0) в (col+1, 1) или из (col, 1) в (col+1, 0)
+	col := int(math.Floor(x))
+	edge := t
+	if !pointsNorth(row, col) {
+		edge = 1 - t
+	}
+	if x-float64(col) < edge {
+		col--
+	}
+
+	if col < 0 || col >= g.cols {
+		return 0, false
+	}
+	return NewCellID(row, col), true
+}
+
+// Neighbors возвращает до трех ячеек, имеющих с данной общую сторону: западный и восточный
+// треугольники ряда и треугольник соседнего ряда, примыкающий к горизонтальной стороне
+func (g *TriangularGrid) Neighbors(id CellID) []CellID {
+	if !g.Contains(id) {
+		return nil
+	}
+	row, col := id.Row(), id.Col()
+
+	other := row + 1
+	if pointsNorth(row, col) {
+		other = row - 1
+	}
+
+	candidates := [][2]int{{row, col - 1}, {row, col + 1}, {other, col}}
+
+	result := make([]CellID, 0, len(candidates))
+	for _, rc := range candidates {
+		if rc[0] >= 0 && rc[0] < g.Rows() && rc[1] >= 0 && rc[1] < g.cols {
+			result = append(result, NewCellID(rc[0], rc[1]))
+		}
+	}
+	return result
+}
+
+// Cells перебирает ячейки сетки по рядам с юга на север
+func (g *TriangularGrid) Cells() iter.Seq[CellID] {
+	return rowMajor(g.Rows(), func(int) int { return g.cols })
+}
+
+// MultiPolygon возвращает все ячейки сетки в порядке рядов с юга на север
+func (g *TriangularGrid) MultiPolygon() types.MultiPolygon {
+	return Polygons(g)
+}
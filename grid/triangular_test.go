@@ -0,0 +1,50 @@
+package grid
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+func TestTriangularGrid(t *testing.T) {
+	g, err := NewTriangularGrid(37, 55, 38, 56, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGrid(t, g, g.Bounds())
+
+	// Соседние треугольники имеют общую сторону
+	for id := range g.Cells() {
+		cell, _ := g.Boundary(id)
+		neighbors := g.Neighbors(id)
+		if len(neighbors) == 0 || len(neighbors) > 3 {
+			t.Fatalf("%v has %d neighbours", id, len(neighbors))
+		}
+		for _, n := range neighbors {
+			other, _ := g.Boundary(n)
+			if sharedVertices(cell, other) != 2 {
+				t.Fatalf("%v and %v do not share a side", id, n)
+			}
+		}
+	}
+
+	if _, ok := g.Locate(types.NewPoint(37.5, 60)); ok {
+		t.Error("point north of the grid is located")
+	}
+}
+
+func TestTriangularGridInvalid(t *testing.T) {
+	for _, side := range []float64{0, -1, math.NaN(), math.Inf(1), 1e-9} {
+		if _, err := NewTriangularGrid(37, 55, 38, 56, side); !errors.Is(err, ErrInvalidGrid) {
+			t.Errorf("side %g: error = %v, want ErrInvalidGrid", side, err)
+		}
+		if cells := CreateTriangularGrid(37, 55, 38, 56, side); len(cells) != 0 {
+			t.Errorf("side %g: %d cells, want none", side, len(cells))
+		}
+	}
+	if _, err := NewTriangularGrid(math.NaN(), 55, 38, 56, 0.1); !errors.Is(err, ErrInvalidGrid) {
+		t.Errorf("NaN bounds: error = %v, want ErrInvalidGrid", err)
+	}
+}
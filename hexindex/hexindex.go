@@ -0,0 +1,195 @@
+// Package hexindex реализует иерархический индекс шестиугольных ячеек с 64-битными идентификаторами,
+// аналогичный H3.
+//
+// Ячейки строятся на плоскости равновеликой цилиндрической проекции Берманна,
+// поэтому ячейки одного разрешения имеют одинаковую площадь на сфере,
+// а форма ячеек искажается по мере удаления от широты ±30°.
+// Каждое следующее разрешение уменьшает площадь ячейки в 7 раз; родителем ячейки
+// является ячейка грубого разрешения, содержащая ее центр, поэтому дочерние ячейки
+// покрывают родительскую приближенно, как и в H3
+package hexindex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// MaxResolution - максимальное разрешение индекса (площадь ячейки порядка квадратного метра)
+const MaxResolution = 15
+
+// boundaryStepLat - наибольший шаг по широте между вершинами границы ячейки в градусах
+const boundaryStepLat = 0.5
+
+// Раскладка битов идентификатора ячейки:
+// бит 62 - признак ячейки, биты 58-61 - разрешение,
+// биты 29-57 и 0-28 - осевые координаты i и j со смещением
+const (
+	cellMarker     = uint64(1) << 62
+	resolutionBits = 58
+	coordBits      = 29
+	coordMask      = uint64(1)<<coordBits - 1
+	coordOffset    = int64(1) << (coordBits - 1)
+)
+
+var (
+	// ErrInvalidCell возвращается для идентификатора, не соответствующего ячейке индекса
+	ErrInvalidCell = errors.New("invalid cell")
+	// ErrInvalidResolution возвращается для разрешения вне диапазона [0, MaxResolution]
+	ErrInvalidResolution = errors.New("invalid resolution")
+)
+
+// Cell - идентификатор ячейки индекса
+type Cell uint64
+
+// PointToCell возвращает ячейку заданного разрешения, содержащую точку
+func PointToCell(p types.Point, res int) (Cell, error) {
+	if err := checkResolution(res); err != nil {
+		return 0, err
+	}
+	l := &levels[res]
+	x, y := project(p)
+	return newCell(res, l.canonical(l.locate(x, y))), nil
+}
+
+// ParseCell разбирает шестнадцатеричное представление идентификатора ячейки
+func ParseCell(s string) (Cell, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse cell %q: %w", s, ErrInvalidCell)
+	}
+	c := Cell(v)
+	if !c.IsValid() {
+		return 0, fmt.Errorf("parse cell %q: %w", s, ErrInvalidCell)
+	}
+	return c, nil
+}
+
+// String возвращает шестнадцатеричное представление идентификатора
+func (c Cell) String() string {
+	return strconv.FormatUint(uint64(c), 16)
+}
+
+// Resolution возвращает разрешение ячейки
+func (c Cell) Resolution() int {
+	return int(uint64(c) >> resolutionBits & 0xF)
+}
+
+// IsValid проверяет, что идентификатор соответствует существующей ячейке
+func (c Cell) IsValid() bool {
+	if uint64(c)&^(cellMarker|uint64(0xF)<<resolutionBits|coordMask<<coordBits|coordMask) != 0 ||
+		uint64(c)&cellMarker == 0 {
+		return false
+	}
+	res := c.Resolution()
+	if res > MaxResolution {
+		return false
+	}
+	l := &levels[res]
+	a := c.axial()
+	return l.canonical(a) == a && l.inDomain(a)
+}
+
+// Center возвращает центр ячейки. Для ячеек у полюсов, центр которых лежит за полюсом,
+// возвращается точка полюса
+func (c Cell) Center() types.Point {
+	l := &levels[c.Resolution()]
+	return unproject(l.center(c.axial()))
+}
+
+// Boundary возвращает границу ячейки в виде полигона с обходом против часовой стрелки.
+// Ячейки у полюсов обрезаются по линии полюса. Долготы вершин ячеек у 180-го меридиана
+// могут выходить за пределы [-180, 180], чтобы контур оставался непрерывным
+func (c Cell) Boundary() types.Polygon {
+	ring := c.planeRing()
+	points := make([]types.Point, 0, len(ring)+1)
+	for i, v := range ring {
+		next := ring[(i+1)%len(ring)]
+
+		// Прямые ребра плоскости проекции искривляются по широте, поэтому крупные ячейки
+		// получают промежуточные вершины
+		dLat := unproject(next[0], next[1]).GetLatitude() - unproject(v[0], v[1]).GetLatitude()
+		steps := max(1, int(math.Ceil(math.Abs(dLat)/boundaryStepLat)))
+		for s := 0; s < steps; s++ {
+			t := float64(s) / float64(steps)
+			points = append(points, unproject(v[0]+t*(next[0]-v[0]), v[1]+t*(next[1]-v[1])))
+		}
+	}
+	if len(points) > 0 {
+		points = append(points, types.NewPoint(points[0].GetLongitude(), points[0].GetLatitude()))
+	}
+	return types.NewPolygon(types.NewLineString(points...))
+}
+
+// Area возвращает площадь ячейки в квадратных километрах
+func (c Cell) Area() float64 {
+	// Площадь на плоскости равновеликой проекции совпадает с площадью на единичной сфере
+	ring := c.planeRing()
+	area := 0.0
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		area += ring[j][0]*ring[i][1] - ring[i][0]*ring[j][1]
+	}
+	return math.Abs(area) / 2 * calc.EarthRadiusKm * calc.EarthRadiusKm
+}
+
+// planeRing возвращает вершины ячейки на плоскости проекции, обрезанные по линиям полюсов
+func (c Cell) planeRing() [][2]float64 {
+	l := &levels[c.Resolution()]
+	cx, cy := l.center(c.axial())
+
+	ring := make([][2]float64, 0, 8)
+	for _, v := range l.vertices {
+		ring = append(ring, [2]float64{cx + v[0], cy + v[1]})
+	}
+	ring = clipY(ring, planeHalfHeight, 1)
+	return clipY(ring, -planeHalfHeight, -1)
+}
+
+// newCell упаковывает координаты ячейки в идентификатор
+func newCell(res int, a axial) Cell {
+	return Cell(cellMarker |
+		uint64(res)<<resolutionBits |
+		uint64(a.i+coordOffset)&coordMask<<coordBits |
+		uint64(a.j+coordOffset)&coordMask)
+}
+
+// axial распаковывает осевые координаты ячейки
+func (c Cell) axial() axial {
+	return axial{
+		int64(uint64(c)>>coordBits&coordMask) - coordOffset,
+		int64(uint64(c)&coordMask) - coordOffset,
+	}
+}
+
+// checkResolution проверяет допустимость разрешения
+func checkResolution(res int) error {
+	if res < 0 || res > MaxResolution {
+		return fmt.Errorf("resolution %d: %w", res, ErrInvalidResolution)
+	}
+	return nil
+}
+
+// clipY отсекает часть многоугольника за горизонтальной линией y = limit.
+// sign = 1 оставляет точки с y <= limit, sign = -1 - точки с y >= limit
+func clipY(ring [][2]float64, limit, sign float64) [][2]float64 {
+	var result [][2]float64
+	for i := range ring {
+		cur := ring[i]
+		prev := ring[(i+len(ring)-1)%len(ring)]
+		curIn := sign*(cur[1]-limit) <= 0
+		prevIn := sign*(prev[1]-limit) <= 0
+
+		if curIn != prevIn {
+			t := (limit - prev[1]) / (cur[1] - prev[1])
+			result = append(result, [2]float64{prev[0] + t*(cur[0]-prev[0]), limit})
+		}
+		if curIn {
+			result = append(result, cur)
+		}
+	}
+	return result
+}
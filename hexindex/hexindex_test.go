@@ -0,0 +1,205 @@
+package hexindex
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// randomPoint возвращает случайную точку вдали от полюсов и 180-го меридиана
+func randomPoint(r *rand.Rand) types.Point {
+	return types.NewPoint(-170+r.Float64()*340, -75+r.Float64()*150)
+}
+
+func TestPointToCellContainsPoint(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		p := randomPoint(r)
+		for _, res := range []int{3, 6, 9, 12} {
+			c, err := PointToCell(p, res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !c.IsValid() || c.Resolution() != res {
+				t.Fatalf("PointToCell(%v, %d) = %s, invalid cell", p, res, c)
+			}
+			if !calc.PointInPolygon(c.Boundary(), p) {
+				t.Fatalf("cell %s at resolution %d does not contain %v", c, res, p)
+			}
+			if got, _ := PointToCell(c.Center(), res); got != c {
+				t.Fatalf("centre of %s maps to %s", c, got)
+			}
+
+			parsed, err := ParseCell(c.String())
+			if err != nil || parsed != c {
+				t.Fatalf("ParseCell(%q) = %s, %v", c.String(), parsed, err)
+			}
+		}
+	}
+}
+
+func TestParseCellInvalid(t *testing.T) {
+	for _, s := range []string{"", "xyz", "0", "ffffffffffffffff"} {
+		if _, err := ParseCell(s); !errors.Is(err, ErrInvalidCell) {
+			t.Errorf("ParseCell(%q) error = %v, want ErrInvalidCell", s, err)
+		}
+	}
+	if _, err := PointToCell(types.NewPoint(0, 0), MaxResolution+1); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf("PointToCell error = %v, want ErrInvalidResolution", err)
+	}
+}
+
+func TestParentChildren(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		c, _ := PointToCell(randomPoint(r), 4+r.Intn(8))
+		res := c.Resolution()
+
+		children, err := c.Children(res + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(children) != 7 {
+			t.Fatalf("%s has %d children, want 7", c, len(children))
+		}
+		for _, child := range children {
+			if parent, _ := child.Parent(res); parent != c {
+				t.Fatalf("parent of child %s is %s, want %s", child, parent, c)
+			}
+		}
+
+		grandchildren, _ := c.Children(res + 2)
+		if len(grandchildren) != 49 {
+			t.Fatalf("%s has %d grandchildren, want 49", c, len(grandchildren))
+		}
+		for _, child := range grandchildren {
+			if parent, _ := child.Parent(res); parent != c {
+				t.Fatalf("parent of grandchild %s is %s, want %s", child, parent, c)
+			}
+		}
+
+		if _, err := c.Parent(res + 1); !errors.Is(err, ErrInvalidResolution) {
+			t.Errorf("Parent at finer resolution error = %v", err)
+		}
+	}
+}
+
+func TestCompactUncompact(t *testing.T) {
+	c, _ := PointToCell(types.NewPoint(37.6173, 55.7558), 6)
+	cells, err := c.Children(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ячейка без одной дочерней ячейки сжимается только частично
+	partial, err := Compact(cells[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Шесть полных групп заменяются родителями, оставшиеся шесть внуков сохраняются
+	if len(partial) != 6+6 {
+		t.Errorf("Compact of incomplete set returned %d cells, want 12", len(partial))
+	}
+
+	compacted, err := Compact(append(cells, cells[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(compacted, []Cell{c}) {
+		t.Fatalf("Compact = %v, want [%s]", compacted, c)
+	}
+
+	expanded, err := Uncompact(partial, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(expanded)
+	want := slices.Clone(cells[1:])
+	slices.Sort(want)
+	if !slices.Equal(expanded, want) {
+		t.Errorf("Uncompact(Compact(cells)) returned %d cells, want %d", len(expanded), len(want))
+	}
+}
+
+func TestGridDiskAndDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		origin, _ := PointToCell(randomPoint(r), 7)
+		disk, err := GridDisk(origin, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(disk) != 1+3*3*4 {
+			t.Fatalf("GridDisk(%s, 3) returned %d cells, want 37", origin, len(disk))
+		}
+		if disk[0] != origin {
+			t.Fatalf("GridDisk starts with %s, want %s", disk[0], origin)
+		}
+
+		prev := 0
+		for _, c := range disk {
+			d, err := GridDistance(origin, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d > 3 || d < prev {
+				t.Fatalf("distance to %s is %d after %d", c, d, prev)
+			}
+			if back, _ := GridDistance(c, origin); back != d {
+				t.Fatalf("GridDistance is not symmetric: %d and %d", d, back)
+			}
+			prev = d
+		}
+	}
+}
+
+func TestGridDistanceAcrossAntimeridian(t *testing.T) {
+	west, _ := PointToCell(types.NewPoint(179.9, 10), 5)
+	east, _ := PointToCell(types.NewPoint(-179.9, 10), 5)
+	d, err := GridDistance(west, east)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d > 1 {
+		t.Errorf("GridDistance across 180th meridian = %d, want at most 1", d)
+	}
+}
+
+func TestPolyfill(t *testing.T) {
+	polygon := types.NewPolygon(types.NewLineString(
+		types.NewPoint(37, 55), types.NewPoint(38.5, 55.2), types.NewPoint(38, 56.3),
+		types.NewPoint(37.2, 56), types.NewPoint(37, 55),
+	))
+	cells, err := Polyfill(polygon, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) == 0 {
+		t.Fatal("Polyfill returned no cells")
+	}
+
+	set := make(map[Cell]struct{}, len(cells))
+	for _, c := range cells {
+		if !calc.PointInPolygon(polygon, c.Center()) {
+			t.Fatalf("centre of %s lies outside the polygon", c)
+		}
+		set[c] = struct{}{}
+	}
+
+	// Ячейка, целиком лежащая внутри полигона, должна войти в результат
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 500; i++ {
+		p := types.NewPoint(37+r.Float64()*1.5, 55+r.Float64()*1.3)
+		c, _ := PointToCell(p, 7)
+		if !calc.PolygonContainsPolygon(polygon, c.Boundary()) {
+			continue
+		}
+		if _, ok := set[c]; !ok {
+			t.Fatalf("cell %s inside the polygon is missing", c)
+		}
+	}
+}
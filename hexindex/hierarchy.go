@@ -0,0 +1,122 @@
+package hexindex
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Parent возвращает родительскую ячейку заданного разрешения
+func (c Cell) Parent(res int) (Cell, error) {
+	if !c.IsValid() {
+		return 0, fmt.Errorf("parent of %s: %w", c, ErrInvalidCell)
+	}
+	if err := checkResolution(res); err != nil {
+		return 0, err
+	}
+	if res > c.Resolution() {
+		return 0, fmt.Errorf("parent of %s at resolution %d finer than cell: %w", c, res, ErrInvalidResolution)
+	}
+
+	a := c.axial()
+	for r := c.Resolution() - 1; r >= res; r-- {
+		a = levels[r].canonical(levels[r].up(a))
+	}
+	return newCell(res, a), nil
+}
+
+// Children возвращает дочерние ячейки заданного разрешения.
+// Дочерние ячейки за линией полюса не возвращаются
+func (c Cell) Children(res int) ([]Cell, error) {
+	if !c.IsValid() {
+		return nil, fmt.Errorf("children of %s: %w", c, ErrInvalidCell)
+	}
+	if err := checkResolution(res); err != nil {
+		return nil, err
+	}
+	if res < c.Resolution() {
+		return nil, fmt.Errorf("children of %s at resolution %d coarser than cell: %w", c, res, ErrInvalidResolution)
+	}
+
+	current := []axial{c.axial()}
+	for r := c.Resolution(); r < res; r++ {
+		l, next := &levels[r], &levels[r+1]
+		children := make([]axial, 0, len(current)*7)
+		for _, a := range current {
+			center := l.down(a)
+			for _, offset := range append([]axial{{0, 0}}, axialNeighbors[:]...) {
+				child := next.canonical(axial{center.i + offset.i, center.j + offset.j})
+				if next.inDomain(child) {
+					children = append(children, child)
+				}
+			}
+		}
+		current = children
+	}
+
+	result := make([]Cell, len(current))
+	for i, a := range current {
+		result[i] = newCell(res, a)
+	}
+	return result, nil
+}
+
+// Compact заменяет группы ячеек, содержащие все дочерние ячейки родителя, на родительскую ячейку.
+// Ячейки могут иметь разное разрешение, повторы удаляются
+func Compact(cells []Cell) ([]Cell, error) {
+	set := make(map[Cell]struct{}, len(cells))
+	for _, c := range cells {
+		if !c.IsValid() {
+			return nil, fmt.Errorf("compact %s: %w", c, ErrInvalidCell)
+		}
+		set[c] = struct{}{}
+	}
+
+	for res := MaxResolution; res > 0; res-- {
+		groups := make(map[Cell][]Cell)
+		for c := range set {
+			if c.Resolution() != res {
+				continue
+			}
+			parent, _ := c.Parent(res - 1)
+			groups[parent] = append(groups[parent], c)
+		}
+
+		for parent, group := range groups {
+			if !parent.IsValid() {
+				continue
+			}
+			children, _ := parent.Children(res)
+			if len(group) != len(children) {
+				continue
+			}
+			for _, child := range group {
+				delete(set, child)
+			}
+			set[parent] = struct{}{}
+		}
+	}
+
+	result := make([]Cell, 0, len(set))
+	for c := range set {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// Uncompact раскрывает набор ячеек до заданного разрешения
+func Uncompact(cells []Cell, res int) ([]Cell, error) {
+	if err := checkResolution(res); err != nil {
+		return nil, err
+	}
+
+	var result []Cell
+	for _, c := range cells {
+		children, err := c.Children(res)
+		if err != nil {
+			return nil, fmt.Errorf("uncompact: %w", err)
+		}
+		result = append(result, children...)
+	}
+	return result, nil
+}
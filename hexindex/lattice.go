@@ -0,0 +1,233 @@
+package hexindex
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+const (
+	// baseColumns - количество ячеек нулевого разрешения вдоль экватора
+	baseColumns = 16
+	// standardParallel - стандартная параллель равновеликой цилиндрической проекции (проекция Берманна)
+	standardParallel = 30.0
+)
+
+var (
+	// cosStandard - косинус стандартной параллели
+	cosStandard = math.Cos(standardParallel * math.Pi / 180)
+	// planeWidth - ширина плоскости проекции (период по x)
+	planeWidth = 2 * math.Pi * cosStandard
+	// degToPlane - масштаб перевода долготы в градусах в координату x
+	degToPlane = math.Pi / 180 * cosStandard
+	// planeHalfHeight - половина высоты плоскости проекции (широта ±90°)
+	planeHalfHeight = 1 / cosStandard
+	// levels - параметры решетки для каждого разрешения
+	levels = buildLevels()
+)
+
+// axial - осевые координаты центра ячейки в решетке шестиугольников.
+// Базисные векторы решетки повернуты друг относительно друга на 60°
+type axial struct {
+	i, j int64
+}
+
+// axialNeighbors - смещения шести соседей в осевых координатах против часовой стрелки
+var axialNeighbors = [6]axial{{1, 0}, {0, 1}, {-1, 1}, {-1, 0}, {0, -1}, {1, -1}}
+
+// level - решетка одного разрешения
+type level struct {
+	// b1, b2 - базисные векторы решетки на плоскости проекции
+	b1, b2 [2]float64
+	// inv - обратная матрица перехода от плоскости к осевым координатам
+	inv [2][2]float64
+	// downA, downB - образы базисных векторов при переходе к следующему разрешению
+	downA, downB axial
+	// period - сдвиг на ширину плоскости в осевых координатах
+	period axial
+	// xi, xj, xPeriod - целочисленная форма координаты x для точной нормализации по долготе
+	xi, xj, xPeriod int64
+	// vertices - смещения вершин шестиугольника относительно центра
+	vertices [6][2]float64
+	// extentY - наибольшее смещение вершины по y
+	extentY float64
+}
+
+// buildLevels вычисляет решетки всех разрешений.
+// Решетка разрешения r+1 получается поворотом на ±19.1° и уменьшением в √7 раз,
+// направление поворота чередуется, как в апертуре 7 системы H3.
+// Решетка разрешения r является подрешеткой разрешения r+1, поэтому сдвиг на ширину
+// плоскости остается целочисленным на всех разрешениях
+func buildLevels() [MaxResolution + 1]level {
+	var ls [MaxResolution + 1]level
+
+	d := planeWidth / baseColumns
+	ls[0].b1 = [2]float64{d, 0}
+	ls[0].b2 = [2]float64{d / 2, d * math.Sqrt(3) / 2}
+	ls[0].period = axial{baseColumns, 0}
+
+	for r := 0; r <= MaxResolution; r++ {
+		l := &ls[r]
+
+		if r%2 == 0 {
+			l.downA = axial{2, 1}
+		} else {
+			l.downA = axial{3, -1}
+		}
+		l.downB = rotate60(l.downA)
+
+		det := l.b1[0]*l.b2[1] - l.b2[0]*l.b1[1]
+		l.inv = [2][2]float64{
+			{l.b2[1] / det, -l.b2[0] / det},
+			{-l.b1[1] / det, l.b1[0] / det},
+		}
+
+		// Вершины ячейки Вороного: (b1+b2)/3, повернутый на k·60°
+		offsets := [6][2]float64{}
+		for k := 0; k < 2; k++ {
+			offsets[0][k] = (l.b1[k] + l.b2[k]) / 3
+			offsets[1][k] = (2*l.b2[k] - l.b1[k]) / 3
+			offsets[2][k] = (l.b2[k] - 2*l.b1[k]) / 3
+			offsets[3][k] = -offsets[0][k]
+			offsets[4][k] = -offsets[1][k]
+			offsets[5][k] = -offsets[2][k]
+		}
+		l.vertices = offsets
+		for _, v := range offsets {
+			l.extentY = math.Max(l.extentY, math.Abs(v[1]))
+		}
+
+		// Координата x на разрешении r кратна d / (2·7^r)
+		unit := d / (2 * math.Pow(7, float64(r)))
+		l.xi = int64(math.Round(l.b1[0] / unit))
+		l.xj = int64(math.Round(l.b2[0] / unit))
+		l.xPeriod = l.xi*l.period.i + l.xj*l.period.j
+
+		if r == MaxResolution {
+			break
+		}
+
+		// Базис следующего разрешения: b1 = A·(b1', b2'), b2 = B·(b1', b2')
+		a, b := l.downA, l.downB
+		next := &ls[r+1]
+		for k := 0; k < 2; k++ {
+			next.b1[k] = (float64(b.j)*l.b1[k] - float64(a.j)*l.b2[k]) / 7
+			next.b2[k] = (float64(a.i)*l.b2[k] - float64(b.i)*l.b1[k]) / 7
+		}
+		next.period = l.down(l.period)
+	}
+	return ls
+}
+
+// rotate60 поворачивает вектор в осевых координатах на 60° против часовой стрелки
+func rotate60(a axial) axial {
+	return axial{-a.j, a.i + a.j}
+}
+
+// down переводит центр ячейки в осевые координаты ее центральной дочерней ячейки
+func (l *level) down(a axial) axial {
+	return axial{
+		a.i*l.downA.i + a.j*l.downB.i,
+		a.i*l.downA.j + a.j*l.downB.j,
+	}
+}
+
+// up находит родительскую ячейку для ячейки следующего разрешения
+func (l *level) up(c axial) axial {
+	a, b := l.downA, l.downB
+	fi := float64(c.i*b.j-c.j*b.i) / 7
+	fj := float64(c.j*a.i-c.i*a.j) / 7
+	return roundAxial(fi, fj)
+}
+
+// center вычисляет центр ячейки на плоскости проекции
+func (l *level) center(a axial) (float64, float64) {
+	x := float64(a.i)*l.b1[0] + float64(a.j)*l.b2[0]
+	y := float64(a.i)*l.b1[1] + float64(a.j)*l.b2[1]
+	return x, y
+}
+
+// locate находит ячейку, содержащую точку плоскости
+func (l *level) locate(x, y float64) axial {
+	fi := l.inv[0][0]*x + l.inv[0][1]*y
+	fj := l.inv[1][0]*x + l.inv[1][1]*y
+	return roundAxial(fi, fj)
+}
+
+// canonical приводит ячейку к представителю с центром в полосе x ∈ [-W/2, W/2)
+func (l *level) canonical(a axial) axial {
+	x := l.xi*a.i + l.xj*a.j
+	k := floorDiv(x+l.xPeriod/2, l.xPeriod)
+	if k == 0 {
+		return a
+	}
+	return axial{a.i - k*l.period.i, a.j - k*l.period.j}
+}
+
+// inDomain проверяет, что шестиугольник ячейки пересекает полосу между полюсами
+func (l *level) inDomain(a axial) bool {
+	_, y := l.center(a)
+	return y-l.extentY < planeHalfHeight && y+l.extentY > -planeHalfHeight
+}
+
+// roundAxial округляет дробные осевые координаты до ближайшего центра ячейки
+func roundAxial(fi, fj float64) axial {
+	fk := -fi - fj
+	ri, rj, rk := math.Round(fi), math.Round(fj), math.Round(fk)
+	di, dj, dk := math.Abs(ri-fi), math.Abs(rj-fj), math.Abs(rk-fk)
+
+	if di > dj && di > dk {
+		ri = -rj - rk
+	} else if dj > dk {
+		rj = -ri - rk
+	}
+	return axial{int64(ri), int64(rj)}
+}
+
+// axialDistance возвращает количество шагов между ячейками решетки
+func axialDistance(a, b axial) int64 {
+	di := a.i - b.i
+	dj := a.j - b.j
+	return (abs64(di) + abs64(dj) + abs64(di+dj)) / 2
+}
+
+// project переводит географическую точку на плоскость равновеликой цилиндрической проекции
+func project(p types.Point) (float64, float64) {
+	lon := math.Mod(p.GetLongitude()+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	lon -= 180
+
+	lat := math.Max(-90, math.Min(90, p.GetLatitude()))
+	return lon * degToPlane, math.Sin(lat*math.Pi/180) / cosStandard
+}
+
+// unproject переводит точку плоскости проекции в географические координаты.
+// Долгота не нормализуется, чтобы границы ячеек у 180-го меридиана оставались непрерывными
+func unproject(x, y float64) types.Point {
+	s := math.Max(-1, math.Min(1, y*cosStandard))
+	return types.NewPoint(x/degToPlane, math.Asin(s)*180/math.Pi)
+}
+
+// floorDiv выполняет целочисленное деление с округлением вниз
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floor64 округляет число вниз до целого
+func floor64(v float64) int64 {
+	return int64(math.Floor(v))
+}
+
+// abs64 возвращает модуль целого числа
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
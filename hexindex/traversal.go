@@ -0,0 +1,130 @@
+package hexindex
+
+import (
+	"fmt"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// GridDisk возвращает ячейки на расстоянии не более k шагов от исходной (k-кольцо),
+// упорядоченные по возрастанию расстояния. Исходная ячейка идет первой
+func GridDisk(origin Cell, k int) ([]Cell, error) {
+	if !origin.IsValid() {
+		return nil, fmt.Errorf("grid disk of %s: %w", origin, ErrInvalidCell)
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("grid disk of %s: negative k %d", origin, k)
+	}
+
+	res := origin.Resolution()
+	l := &levels[res]
+	center := origin.axial()
+
+	seen := map[Cell]struct{}{origin: {}}
+	result := []Cell{origin}
+	for ring := 1; ring <= k; ring++ {
+		// Обход кольца: начинаем с ячейки в направлении пятого соседа и идем вдоль шести сторон
+		r := int64(ring)
+		a := axial{center.i + axialNeighbors[4].i*r, center.j + axialNeighbors[4].j*r}
+		for side := 0; side < 6; side++ {
+			for step := 0; step < ring; step++ {
+				cell := l.canonical(a)
+				if l.inDomain(cell) {
+					c := newCell(res, cell)
+					if _, ok := seen[c]; !ok {
+						seen[c] = struct{}{}
+						result = append(result, c)
+					}
+				}
+				a = axial{a.i + axialNeighbors[side].i, a.j + axialNeighbors[side].j}
+			}
+		}
+	}
+	return result, nil
+}
+
+// GridDistance возвращает количество шагов между ячейками одного разрешения
+// с учетом перехода через 180-й меридиан
+func GridDistance(a, b Cell) (int, error) {
+	if !a.IsValid() {
+		return 0, fmt.Errorf("grid distance from %s: %w", a, ErrInvalidCell)
+	}
+	if !b.IsValid() {
+		return 0, fmt.Errorf("grid distance to %s: %w", b, ErrInvalidCell)
+	}
+	if a.Resolution() != b.Resolution() {
+		return 0, fmt.Errorf("grid distance between resolutions %d and %d: %w",
+			a.Resolution(), b.Resolution(), ErrInvalidResolution)
+	}
+
+	period := levels[a.Resolution()].period
+	from, to := a.axial(), b.axial()
+	best := axialDistance(from, to)
+	for _, k := range [2]int64{-1, 1} {
+		shifted := axial{to.i + k*period.i, to.j + k*period.j}
+		best = min(best, axialDistance(from, shifted))
+	}
+	return int(best), nil
+}
+
+// Polyfill возвращает ячейки заданного разрешения, центры которых лежат внутри полигона.
+// Вычисления выполняются на плоскости долгота/широта
+func Polyfill(polygon types.Polygon, res int) ([]Cell, error) {
+	if err := checkResolution(res); err != nil {
+		return nil, err
+	}
+	if len(polygon) == 0 || len(polygon[0]) < 3 {
+		return nil, nil
+	}
+
+	region := calc.NewPreparedPolygon(polygon)
+	l := &levels[res]
+
+	// Габариты полигона на плоскости проекции. Долготы не нормализуются,
+	// чтобы полигоны, заданные за пределами [-180, 180], обрабатывались непрерывно
+	minX, minY := 1e300, 1e300
+	maxX, maxY := -1e300, -1e300
+	for _, p := range polygon[0] {
+		x := p.GetLongitude() * degToPlane
+		_, y := project(p)
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+
+	// Диапазон осевых координат, покрывающий габариты
+	minI, minJ := int64(1)<<62, int64(1)<<62
+	maxI, maxJ := -minI, -minJ
+	for _, corner := range [4][2]float64{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}} {
+		fi := l.inv[0][0]*corner[0] + l.inv[0][1]*corner[1]
+		fj := l.inv[1][0]*corner[0] + l.inv[1][1]*corner[1]
+		minI, maxI = min(minI, floor64(fi)), max(maxI, floor64(fi)+1)
+		minJ, maxJ = min(minJ, floor64(fj)), max(maxJ, floor64(fj)+1)
+	}
+
+	seen := make(map[Cell]struct{})
+	var result []Cell
+	for i := minI; i <= maxI; i++ {
+		for j := minJ; j <= maxJ; j++ {
+			a := axial{i, j}
+			x, y := l.center(a)
+			if x < minX || x > maxX || y < minY || y > maxY {
+				continue
+			}
+			if !region.ContainsPoint(unproject(x, y)) {
+				continue
+			}
+
+			canonical := l.canonical(a)
+			if !l.inDomain(canonical) {
+				continue
+			}
+			c := newCell(res, canonical)
+			if _, ok := seen[c]; !ok {
+				seen[c] = struct{}{}
+				result = append(result, c)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,238 @@
+package index
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+	"sort"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// kdLeafSize - максимальное количество точек в листе k-d дерева
+const kdLeafSize = 8
+
+// KDTreeItem представляет точку с произвольным значением для построения k-d дерева
+type KDTreeItem[T any] struct {
+	Point types.Point
+	Value T
+}
+
+// kdPoint - точка, представленная единичным вектором в трехмерном пространстве
+type kdPoint[T any] struct {
+	vec   [3]float64
+	point types.Point
+	value T
+}
+
+// kdNode - узел k-d дерева, охватывающий диапазон точек [lo, hi)
+type kdNode struct {
+	lo, hi      int
+	left, right int // индексы дочерних узлов, -1 у листьев
+	min, max    [3]float64
+}
+
+// KDTree - статический индекс точек для поиска ближайших соседей по расстоянию большого круга.
+// Точки хранятся как единичные векторы, поэтому индекс корректно работает у полюсов
+// и при пересечении 180-го меридиана. После построения дерево не изменяется,
+// поэтому его методы безопасны для одновременного вызова из нескольких горутин
+type KDTree[T any] struct {
+	points []kdPoint[T]
+	nodes  []kdNode
+}
+
+// NewKDTree строит k-d дерево по набору точек со значениями
+func NewKDTree[T any](items []KDTreeItem[T]) *KDTree[T] {
+	t := &KDTree[T]{points: make([]kdPoint[T], len(items))}
+	for i, it := range items {
+		t.points[i] = kdPoint[T]{vec: toUnitVector(it.Point), point: it.Point, value: it.Value}
+	}
+
+	if len(t.points) > 0 {
+		t.build(0, len(t.points))
+	}
+	return t
+}
+
+// NewPointKDTree строит k-d дерево по набору точек. Значением каждой точки является ее индекс в points
+func NewPointKDTree(points []types.Point) *KDTree[int] {
+	items := make([]KDTreeItem[int], len(points))
+	for i, p := range points {
+		items[i] = KDTreeItem[int]{Point: p, Value: i}
+	}
+	return NewKDTree(items)
+}
+
+// Len возвращает количество точек в дереве
+func (t *KDTree[T]) Len() int {
+	return len(t.points)
+}
+
+// Nearest возвращает до k ближайших к точке объектов в порядке возрастания расстояния (в километрах)
+func (t *KDTree[T]) Nearest(point types.Point, k int) []Neighbor[T] {
+	if k <= 0 {
+		return nil
+	}
+
+	result := make([]Neighbor[T], 0, min(k, len(t.points)))
+	for n := range t.ByDistance(point) {
+		result = append(result, n)
+		if len(result) == k {
+			break
+		}
+	}
+	return result
+}
+
+// WithinRadius возвращает все объекты на расстоянии не более radius (в километрах)
+// в порядке возрастания расстояния
+func (t *KDTree[T]) WithinRadius(point types.Point, radius float64) []Neighbor[T] {
+	var result []Neighbor[T]
+	if radius < 0 {
+		return result
+	}
+
+	for n := range t.ByDistance(point) {
+		if n.Distance > radius {
+			break
+		}
+		result = append(result, n)
+	}
+	return result
+}
+
+// ByDistance возвращает итератор по всем объектам дерева в порядке возрастания расстояния до точки.
+// Узлы дерева раскрываются лениво, поэтому досрочная остановка обхода не требует просмотра всех точек
+func (t *KDTree[T]) ByDistance(point types.Point) iter.Seq[Neighbor[T]] {
+	return func(yield func(Neighbor[T]) bool) {
+		if len(t.nodes) == 0 {
+			return
+		}
+
+		q := toUnitVector(point)
+		queue := &kdQueue{}
+		heap.Push(queue, kdQueueItem{node: 0, point: -1, dist: t.nodes[0].chordTo(q)})
+
+		for queue.Len() > 0 {
+			next := heap.Pop(queue).(kdQueueItem)
+
+			if next.point >= 0 {
+				p := t.points[next.point]
+				if !yield(Neighbor[T]{Value: p.value, Distance: calc.CalculateDistance(point, p.point)}) {
+					return
+				}
+				continue
+			}
+
+			node := t.nodes[next.node]
+			if node.left < 0 {
+				for i := node.lo; i < node.hi; i++ {
+					heap.Push(queue, kdQueueItem{node: -1, point: i, dist: chord(q, t.points[i].vec)})
+				}
+				continue
+			}
+
+			for _, child := range [2]int{node.left, node.right} {
+				heap.Push(queue, kdQueueItem{node: child, point: -1, dist: t.nodes[child].chordTo(q)})
+			}
+		}
+	}
+}
+
+// build рекурсивно строит узел для диапазона точек [lo, hi) и возвращает его индекс
+func (t *KDTree[T]) build(lo, hi int) int {
+	idx := len(t.nodes)
+	t.nodes = append(t.nodes, kdNode{lo: lo, hi: hi, left: -1, right: -1})
+
+	// Границы узла по всем трем осям
+	var bmin, bmax [3]float64
+	for axis := 0; axis < 3; axis++ {
+		bmin[axis] = math.Inf(1)
+		bmax[axis] = math.Inf(-1)
+	}
+	for i := lo; i < hi; i++ {
+		for axis := 0; axis < 3; axis++ {
+			bmin[axis] = math.Min(bmin[axis], t.points[i].vec[axis])
+			bmax[axis] = math.Max(bmax[axis], t.points[i].vec[axis])
+		}
+	}
+	t.nodes[idx].min = bmin
+	t.nodes[idx].max = bmax
+
+	if hi-lo <= kdLeafSize {
+		return idx
+	}
+
+	// Делим по оси с наибольшим разбросом
+	axis := 0
+	for a := 1; a < 3; a++ {
+		if bmax[a]-bmin[a] > bmax[axis]-bmin[axis] {
+			axis = a
+		}
+	}
+
+	part := t.points[lo:hi]
+	sort.Slice(part, func(i, j int) bool { return part[i].vec[axis] < part[j].vec[axis] })
+	mid := lo + (hi-lo)/2
+
+	left := t.build(lo, mid)
+	right := t.build(mid, hi)
+	t.nodes[idx].left = left
+	t.nodes[idx].right = right
+	return idx
+}
+
+// chordTo вычисляет минимальную длину хорды от вектора до прямоугольного параллелепипеда узла
+func (n kdNode) chordTo(v [3]float64) float64 {
+	sum := 0.0
+	for axis := 0; axis < 3; axis++ {
+		d := 0.0
+		if v[axis] < n.min[axis] {
+			d = n.min[axis] - v[axis]
+		} else if v[axis] > n.max[axis] {
+			d = v[axis] - n.max[axis]
+		}
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// toUnitVector преобразует точку в единичный вектор геоцентрической системы координат
+func toUnitVector(p types.Point) [3]float64 {
+	lon := p.GetLongitude() * math.Pi / 180.0
+	lat := p.GetLatitude() * math.Pi / 180.0
+	cosLat := math.Cos(lat)
+	return [3]float64{cosLat * math.Cos(lon), cosLat * math.Sin(lon), math.Sin(lat)}
+}
+
+// chord вычисляет длину хорды между двумя единичными векторами.
+// Длина хорды монотонно возрастает вместе с расстоянием по большому кругу
+func chord(a, b [3]float64) float64 {
+	dx := a[0] - b[0]
+	dy := a[1] - b[1]
+	dz := a[2] - b[2]
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// kdQueueItem - элемент очереди обхода: узел (point < 0) или точка (node < 0)
+type kdQueueItem struct {
+	node  int
+	point int
+	dist  float64
+}
+
+// kdQueue - очередь с приоритетом по длине хорды
+type kdQueue []kdQueueItem
+
+func (q kdQueue) Len() int            { return len(q) }
+func (q kdQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q kdQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *kdQueue) Push(x interface{}) { *q = append(*q, x.(kdQueueItem)) }
+func (q *kdQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
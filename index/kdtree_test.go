@@ -0,0 +1,191 @@
+package index
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// kdTestPoints возвращает случайные точки по всему миру и скопления у 180-го меридиана и полюсов
+func kdTestPoints(r *rand.Rand) []types.Point {
+	var points []types.Point
+	for i := 0; i < 1000; i++ {
+		points = append(points, types.NewPoint(r.Float64()*360-180, r.Float64()*180-90))
+	}
+	for i := 0; i < 200; i++ {
+		// Скопление по обе стороны от 180-го меридиана
+		lon := 180 - r.Float64()*2
+		if i%2 == 1 {
+			lon = -lon
+		}
+		points = append(points, types.NewPoint(lon, r.Float64()*10-5))
+	}
+	for i := 0; i < 200; i++ {
+		lat := 90 - r.Float64()*1
+		if i%2 == 1 {
+			lat = -lat
+		}
+		points = append(points, types.NewPoint(r.Float64()*360-180, lat))
+	}
+	// Сами полюса с разной долготой
+	points = append(points, types.NewPoint(0, 90), types.NewPoint(120, 90), types.NewPoint(-45, -90))
+	return points
+}
+
+// kdTestQueries возвращает точки запросов, включая 180-й меридиан и полюса
+func kdTestQueries(r *rand.Rand) []types.Point {
+	queries := []types.Point{
+		types.NewPoint(180, 0), types.NewPoint(-180, 0), types.NewPoint(179.9, 1), types.NewPoint(-179.9, -1),
+		types.NewPoint(0, 90), types.NewPoint(77, 90), types.NewPoint(0, -90), types.NewPoint(-100, 89.5),
+	}
+	for i := 0; i < 50; i++ {
+		queries = append(queries, types.NewPoint(r.Float64()*360-180, r.Float64()*180-90))
+	}
+	return queries
+}
+
+// bruteDistances возвращает расстояния от точки запроса до всех точек
+func bruteDistances(query types.Point, points []types.Point) []float64 {
+	distances := make([]float64, len(points))
+	for i, p := range points {
+		distances[i] = calc.CalculateDistance(query, p)
+	}
+	return distances
+}
+
+func TestKDTreeNearestMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := kdTestPoints(r)
+	tree := NewPointKDTree(points)
+	if tree.Len() != len(points) {
+		t.Fatalf("Len = %d, want %d", tree.Len(), len(points))
+	}
+
+	for _, query := range kdTestQueries(r) {
+		distances := bruteDistances(query, points)
+		want := slices.Clone(distances)
+		slices.Sort(want)
+
+		for _, k := range []int{1, 5, 50} {
+			got := tree.Nearest(query, k)
+			if len(got) != k {
+				t.Fatalf("Nearest(%v, %d) returned %d items", query, k, len(got))
+			}
+			// При равных расстояниях порядок точек не определен, поэтому сравниваются расстояния
+			for i, n := range got {
+				if math.Abs(n.Distance-want[i]) > 1e-6 || math.Abs(distances[n.Value]-n.Distance) > 1e-6 {
+					t.Fatalf("Nearest(%v, %d)[%d] = %d at %g km, want distance %g", query, k, i, n.Value, n.Distance, want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestKDTreeWithinRadiusMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := kdTestPoints(r)
+	tree := NewPointKDTree(points)
+
+	for _, query := range kdTestQueries(r) {
+		distances := bruteDistances(query, points)
+		for _, radius := range []float64{0, 50, 300, 2000} {
+			var want []int
+			for i, d := range distances {
+				if d <= radius {
+					want = append(want, i)
+				}
+			}
+
+			got := tree.WithinRadius(query, radius)
+			values := make([]int, len(got))
+			for i, n := range got {
+				values[i] = n.Value
+				if i > 0 && n.Distance < got[i-1].Distance {
+					t.Fatalf("WithinRadius(%v, %g) is not ordered at %d", query, radius, i)
+				}
+			}
+			if !slices.Equal(sorted(values), want) {
+				t.Fatalf("WithinRadius(%v, %g) = %v, want %v", query, radius, sorted(values), want)
+			}
+		}
+	}
+
+	// Точки по обе стороны от 180-го меридиана находятся рядом
+	near := tree.WithinRadius(types.NewPoint(180, 0), 300)
+	east, west := false, false
+	for _, n := range near {
+		lon := points[n.Value].GetLongitude()
+		east = east || lon > 0
+		west = west || lon < 0
+	}
+	if !east || !west {
+		t.Errorf("points around the antimeridian: east %v, west %v", east, west)
+	}
+}
+
+func TestKDTreeByDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	points := kdTestPoints(r)
+	tree := NewPointKDTree(points)
+
+	for _, query := range kdTestQueries(r)[:20] {
+		seen := make([]bool, len(points))
+		prev := 0.0
+		count := 0
+		for n := range tree.ByDistance(query) {
+			if n.Distance < prev-1e-9 {
+				t.Fatalf("ByDistance(%v) is not ordered: %g after %g", query, n.Distance, prev)
+			}
+			if seen[n.Value] {
+				t.Fatalf("ByDistance(%v) returned %d twice", query, n.Value)
+			}
+			seen[n.Value] = true
+			prev = n.Distance
+			count++
+		}
+		if count != len(points) {
+			t.Fatalf("ByDistance(%v) returned %d points, want %d", query, count, len(points))
+		}
+	}
+
+	// Досрочная остановка обхода
+	count := 0
+	for range tree.ByDistance(types.NewPoint(0, 0)) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("iteration stopped after %d points", count)
+	}
+}
+
+func TestKDTreeEmptyAndLimits(t *testing.T) {
+	empty := NewPointKDTree(nil)
+	if empty.Len() != 0 || len(empty.Nearest(types.NewPoint(0, 0), 3)) != 0 ||
+		len(empty.WithinRadius(types.NewPoint(0, 0), 100)) != 0 {
+		t.Error("empty tree returns points")
+	}
+	for range empty.ByDistance(types.NewPoint(0, 0)) {
+		t.Fatal("empty tree iterates")
+	}
+
+	tree := NewKDTree([]KDTreeItem[string]{
+		{Point: types.NewPoint(37.6173, 55.7558), Value: "moscow"},
+		{Point: types.NewPoint(30.3141, 59.9386), Value: "petersburg"},
+	})
+	if got := tree.Nearest(types.NewPoint(37, 55), 0); got != nil {
+		t.Errorf("Nearest with k = 0 = %v", got)
+	}
+	if got := tree.Nearest(types.NewPoint(37, 55), 10); len(got) != 2 || got[0].Value != "moscow" {
+		t.Errorf("Nearest with k over Len = %v", got)
+	}
+	if got := tree.WithinRadius(types.NewPoint(37, 55), -1); len(got) != 0 {
+		t.Errorf("WithinRadius with negative radius = %v", got)
+	}
+}
@@ -0,0 +1,670 @@
+// Package index содержит пространственные индексы для быстрого поиска объектов
+package index
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Параметры узлов R-дерева по умолчанию
+const (
+	DefaultMaxEntries = 16
+	minMaxEntries     = 4
+)
+
+// rect - прямоугольник в градусах без пересечения 180-го меридиана
+type rect struct {
+	minLon, minLat, maxLon, maxLat float64
+}
+
+// rtreeItem хранит объект и его исходный ограничивающий прямоугольник
+type rtreeItem[T any] struct {
+	bbox  calc.BoundingBox
+	value T
+	// split - прямоугольник объекта разделен по 180-му меридиану на две записи
+	split bool
+}
+
+// rtreeNode - узел R-дерева. У листьев заполнено поле items, у внутренних узлов - children
+type rtreeNode[T any] struct {
+	bounds   rect
+	leaf     bool
+	height   int
+	children []*rtreeNode[T]
+	items    []rtreeEntry[T]
+}
+
+// rtreeEntry - запись листа: часть прямоугольника объекта и ссылка на сам объект
+type rtreeEntry[T any] struct {
+	bounds rect
+	item   *rtreeItem[T]
+}
+
+// RTreeItem представляет объект для пакетной загрузки в R-дерево
+type RTreeItem[T any] struct {
+	BBox  calc.BoundingBox
+	Value T
+}
+
+// Neighbor представляет результат поиска ближайших соседей
+type Neighbor[T any] struct {
+	Value T
+	// Distance - геодезическое расстояние в километрах: для RTree - до ограничивающего
+	// прямоугольника объекта, для KDTree - до точки объекта
+	Distance float64
+}
+
+// RTree - R-дерево над calc.BoundingBox с произвольными значениями.
+// Прямоугольники, пересекающие 180-й меридиан (MinLon > MaxLon), поддерживаются.
+// Методы поиска безопасны для одновременного вызова из нескольких горутин
+type RTree[T any] struct {
+	mu         sync.RWMutex
+	root       *rtreeNode[T]
+	maxEntries int
+	minEntries int
+	size       int
+}
+
+// NewRTree создает пустое R-дерево с указанным максимальным числом записей в узле.
+// При maxEntries <= 0 используется DefaultMaxEntries
+func NewRTree[T any](maxEntries int) *RTree[T] {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	maxEntries = max(maxEntries, minMaxEntries)
+
+	t := &RTree[T]{
+		maxEntries: maxEntries,
+		minEntries: max(2, int(math.Ceil(float64(maxEntries)*0.4))),
+	}
+	t.root = newLeaf[T]()
+	return t
+}
+
+// Len возвращает количество объектов в дереве
+func (t *RTree[T]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.size
+}
+
+// Clear удаляет все объекты из дерева
+func (t *RTree[T]) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.root = newLeaf[T]()
+	t.size = 0
+}
+
+// BulkLoad заменяет содержимое дерева указанными объектами,
+// строя дерево алгоритмом Sort-Tile-Recursive (STR)
+func (t *RTree[T]) BulkLoad(items []RTreeItem[T]) {
+	entries := make([]rtreeEntry[T], 0, len(items))
+	for _, it := range items {
+		entries = append(entries, makeEntries(it.BBox, it.Value)...)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.size = len(items)
+	if len(entries) == 0 {
+		t.root = newLeaf[T]()
+		return
+	}
+	t.root = t.buildSTR(entries)
+}
+
+// Insert добавляет объект с указанным ограничивающим прямоугольником
+func (t *RTree[T]) Insert(bbox calc.BoundingBox, value T) {
+	entries := makeEntries(bbox, value)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, e := range entries {
+		t.insertEntry(e)
+	}
+	t.size++
+}
+
+// Delete удаляет первый объект, прямоугольник которого совпадает с bbox
+// и для которого match возвращает true. Возвращает false, если объект не найден
+func (t *RTree[T]) Delete(bbox calc.BoundingBox, match func(T) bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parts := splitBoundingBox(bbox)
+
+	var target *rtreeItem[T]
+	t.visit(t.root, parts[0], func(e rtreeEntry[T]) bool {
+		if e.item.bbox == bbox && match(e.item.value) {
+			target = e.item
+			return false
+		}
+		return true
+	})
+	if target == nil {
+		return false
+	}
+
+	for _, part := range parts {
+		t.removeEntry(part, target)
+	}
+	t.size--
+	return true
+}
+
+// Search возвращает все объекты, прямоугольники которых пересекаются с bbox
+func (t *RTree[T]) Search(bbox calc.BoundingBox) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var result []T
+	var seen map[*rtreeItem[T]]struct{}
+
+	for _, part := range splitBoundingBox(bbox) {
+		t.visit(t.root, part, func(e rtreeEntry[T]) bool {
+			if e.item.split {
+				if seen == nil {
+					seen = make(map[*rtreeItem[T]]struct{})
+				}
+				if _, ok := seen[e.item]; ok {
+					return true
+				}
+				seen[e.item] = struct{}{}
+			}
+			result = append(result, e.item.value)
+			return true
+		})
+	}
+
+	return result
+}
+
+// SearchPoint возвращает все объекты, прямоугольники которых содержат точку
+func (t *RTree[T]) SearchPoint(point types.Point) []T {
+	lon, lat := point.GetLongitude(), point.GetLatitude()
+	return t.Search(calc.BoundingBox{MinLon: lon, MinLat: lat, MaxLon: lon, MaxLat: lat})
+}
+
+// Nearest возвращает до k объектов, ближайших к точке по геодезическому расстоянию
+// до их ограничивающих прямоугольников, в порядке возрастания расстояния
+func (t *RTree[T]) Nearest(point types.Point, k int) []Neighbor[T] {
+	if k <= 0 {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := make([]Neighbor[T], 0, k)
+	seen := make(map[*rtreeItem[T]]struct{})
+
+	queue := &rtreeQueue[T]{}
+	heap.Push(queue, rtreeQueueItem[T]{node: t.root, dist: 0})
+
+	for queue.Len() > 0 && len(result) < k {
+		next := heap.Pop(queue).(rtreeQueueItem[T])
+
+		if next.node == nil {
+			if _, ok := seen[next.entry.item]; ok {
+				continue
+			}
+			seen[next.entry.item] = struct{}{}
+			result = append(result, Neighbor[T]{Value: next.entry.item.value, Distance: next.dist})
+			continue
+		}
+
+		if next.node.leaf {
+			for _, e := range next.node.items {
+				heap.Push(queue, rtreeQueueItem[T]{entry: e, dist: rectDistance(point, e.bounds)})
+			}
+		} else {
+			for _, child := range next.node.children {
+				heap.Push(queue, rtreeQueueItem[T]{node: child, dist: rectDistance(point, child.bounds)})
+			}
+		}
+	}
+
+	return result
+}
+
+// visit обходит записи листьев, пересекающиеся с прямоугольником r.
+// Обход прекращается, если fn возвращает false
+func (t *RTree[T]) visit(node *rtreeNode[T], r rect, fn func(rtreeEntry[T]) bool) bool {
+	if !node.bounds.intersects(r) {
+		return true
+	}
+
+	if node.leaf {
+		for _, e := range node.items {
+			if e.bounds.intersects(r) && !fn(e) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, child := range node.children {
+		if !t.visit(child, r, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildSTR строит дерево из записей алгоритмом Sort-Tile-Recursive
+func (t *RTree[T]) buildSTR(entries []rtreeEntry[T]) *rtreeNode[T] {
+	// Формируем листья
+	var nodes []*rtreeNode[T]
+	for _, group := range strTiles(entries, t.maxEntries, func(e rtreeEntry[T]) rect { return e.bounds }) {
+		leaf := newLeaf[T]()
+		leaf.items = group
+		leaf.recalc()
+		nodes = append(nodes, leaf)
+	}
+
+	// Поднимаемся вверх, пока не останется один корень
+	for len(nodes) > 1 {
+		var parents []*rtreeNode[T]
+		for _, group := range strTiles(nodes, t.maxEntries, func(n *rtreeNode[T]) rect { return n.bounds }) {
+			parent := &rtreeNode[T]{children: group, height: group[0].height + 1}
+			parent.recalc()
+			parents = append(parents, parent)
+		}
+		nodes = parents
+	}
+
+	return nodes[0]
+}
+
+// strTiles разбивает элементы на группы не более чем по m элементов:
+// сортирует по долготе центра, делит на вертикальные полосы и сортирует каждую полосу по широте
+func strTiles[E any](elems []E, m int, bounds func(E) rect) [][]E {
+	n := len(elems)
+	leafCount := int(math.Ceil(float64(n) / float64(m)))
+	sliceCount := int(math.Ceil(math.Sqrt(float64(leafCount))))
+	sliceSize := sliceCount * m
+
+	sort.Slice(elems, func(i, j int) bool {
+		return bounds(elems[i]).centerLon() < bounds(elems[j]).centerLon()
+	})
+
+	var groups [][]E
+	for start := 0; start < n; start += sliceSize {
+		end := min(start+sliceSize, n)
+		slice := elems[start:end]
+		sort.Slice(slice, func(i, j int) bool {
+			return bounds(slice[i]).centerLat() < bounds(slice[j]).centerLat()
+		})
+
+		for s := 0; s < len(slice); s += m {
+			e := min(s+m, len(slice))
+			group := make([]E, e-s)
+			copy(group, slice[s:e])
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// insertEntry вставляет запись в дерево с разделением переполненных узлов
+func (t *RTree[T]) insertEntry(e rtreeEntry[T]) {
+	// Спускаемся к листу, выбирая поддерево с минимальным увеличением площади
+	path := []*rtreeNode[T]{t.root}
+	node := t.root
+	for !node.leaf {
+		node = chooseSubtree(node, e.bounds)
+		path = append(path, node)
+	}
+
+	node.items = append(node.items, e)
+
+	// Поднимаемся вверх, разделяя переполненные узлы и расширяя границы
+	for level := len(path) - 1; level >= 0; level-- {
+		n := path[level]
+		if n.count() <= t.maxEntries {
+			n.recalc()
+			continue
+		}
+
+		sibling := t.split(n)
+		if level == 0 {
+			t.root = &rtreeNode[T]{children: []*rtreeNode[T]{n, sibling}, height: n.height + 1}
+			t.root.recalc()
+		} else {
+			parent := path[level-1]
+			parent.children = append(parent.children, sibling)
+		}
+	}
+}
+
+// chooseSubtree выбирает дочерний узел с минимальным увеличением площади
+func chooseSubtree[T any](node *rtreeNode[T], r rect) *rtreeNode[T] {
+	var best *rtreeNode[T]
+	bestEnlargement := math.Inf(1)
+	bestArea := math.Inf(1)
+
+	for _, child := range node.children {
+		area := child.bounds.area()
+		enlargement := child.bounds.extend(r, false).area() - area
+		if enlargement < bestEnlargement || (enlargement == bestEnlargement && area < bestArea) {
+			best = child
+			bestEnlargement = enlargement
+			bestArea = area
+		}
+	}
+
+	return best
+}
+
+// split делит переполненный узел на два по оси с наименьшим суммарным периметром
+// и возвращает новый узел-сосед
+func (t *RTree[T]) split(n *rtreeNode[T]) *rtreeNode[T] {
+	count := n.count()
+	boundsAt := func(i int) rect {
+		if n.leaf {
+			return n.items[i].bounds
+		}
+		return n.children[i].bounds
+	}
+
+	order := make([]int, count)
+	bestOrder := make([]int, count)
+	bestIndex := 0
+	bestScore := math.Inf(1)
+
+	for axis := 0; axis < 2; axis++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			ra, rb := boundsAt(order[a]), boundsAt(order[b])
+			if axis == 0 {
+				return ra.minLon < rb.minLon
+			}
+			return ra.minLat < rb.minLat
+		})
+
+		for k := t.minEntries; k <= count-t.minEntries; k++ {
+			left, right := rect{}, rect{}
+			for i, idx := range order {
+				if i < k {
+					left = left.extend(boundsAt(idx), i == 0)
+				} else {
+					right = right.extend(boundsAt(idx), i == k)
+				}
+			}
+
+			score := left.overlap(right)*1e6 + left.margin() + right.margin()
+			if score < bestScore {
+				bestScore = score
+				bestIndex = k
+				copy(bestOrder, order)
+			}
+		}
+	}
+
+	sibling := &rtreeNode[T]{leaf: n.leaf, height: n.height}
+	if n.leaf {
+		items := n.items
+		n.items = make([]rtreeEntry[T], 0, t.maxEntries+1)
+		for i, idx := range bestOrder {
+			if i < bestIndex {
+				n.items = append(n.items, items[idx])
+			} else {
+				sibling.items = append(sibling.items, items[idx])
+			}
+		}
+	} else {
+		children := n.children
+		n.children = make([]*rtreeNode[T], 0, t.maxEntries+1)
+		for i, idx := range bestOrder {
+			if i < bestIndex {
+				n.children = append(n.children, children[idx])
+			} else {
+				sibling.children = append(sibling.children, children[idx])
+			}
+		}
+	}
+
+	n.recalc()
+	sibling.recalc()
+	return sibling
+}
+
+// removeEntry удаляет запись объекта и перевставляет записи из недозаполненных узлов
+func (t *RTree[T]) removeEntry(r rect, target *rtreeItem[T]) {
+	var orphans []rtreeEntry[T]
+
+	var remove func(node *rtreeNode[T]) bool
+	remove = func(node *rtreeNode[T]) bool {
+		if !node.bounds.intersects(r) {
+			return false
+		}
+
+		if node.leaf {
+			for i, e := range node.items {
+				if e.item == target && e.bounds == r {
+					node.items = append(node.items[:i], node.items[i+1:]...)
+					node.recalc()
+					return true
+				}
+			}
+			return false
+		}
+
+		for i, child := range node.children {
+			if !remove(child) {
+				continue
+			}
+			if child.count() < t.minEntries {
+				node.children = append(node.children[:i], node.children[i+1:]...)
+				orphans = append(orphans, child.entries()...)
+			}
+			node.recalc()
+			return true
+		}
+		return false
+	}
+
+	remove(t.root)
+
+	// Сокращаем высоту дерева, если у корня остался один потомок
+	for !t.root.leaf && len(t.root.children) == 1 {
+		t.root = t.root.children[0]
+	}
+	if !t.root.leaf && len(t.root.children) == 0 {
+		t.root = newLeaf[T]()
+	}
+
+	for _, e := range orphans {
+		t.insertEntry(e)
+	}
+}
+
+// newLeaf создает пустой лист
+func newLeaf[T any]() *rtreeNode[T] {
+	return &rtreeNode[T]{leaf: true}
+}
+
+// count возвращает количество записей или дочерних узлов
+func (n *rtreeNode[T]) count() int {
+	if n.leaf {
+		return len(n.items)
+	}
+	return len(n.children)
+}
+
+// entries возвращает все записи листьев поддерева
+func (n *rtreeNode[T]) entries() []rtreeEntry[T] {
+	if n.leaf {
+		return n.items
+	}
+	var result []rtreeEntry[T]
+	for _, child := range n.children {
+		result = append(result, child.entries()...)
+	}
+	return result
+}
+
+// recalc пересчитывает границы узла по его содержимому
+func (n *rtreeNode[T]) recalc() {
+	n.bounds = rect{}
+	if n.leaf {
+		for i, e := range n.items {
+			n.bounds = n.bounds.extend(e.bounds, i == 0)
+		}
+		return
+	}
+	for i, child := range n.children {
+		n.bounds = n.bounds.extend(child.bounds, i == 0)
+	}
+}
+
+// makeEntries создает записи листьев для объекта, разделяя прямоугольник по 180-му меридиану
+func makeEntries[T any](bbox calc.BoundingBox, value T) []rtreeEntry[T] {
+	parts := splitBoundingBox(bbox)
+	item := &rtreeItem[T]{bbox: bbox, value: value, split: len(parts) > 1}
+
+	entries := make([]rtreeEntry[T], len(parts))
+	for i, part := range parts {
+		entries[i] = rtreeEntry[T]{bounds: part, item: item}
+	}
+	return entries
+}
+
+// splitBoundingBox разделяет прямоугольник, пересекающий 180-й меридиан, на две части
+func splitBoundingBox(b calc.BoundingBox) []rect {
+	if b.MinLon <= b.MaxLon {
+		return []rect{{b.MinLon, b.MinLat, b.MaxLon, b.MaxLat}}
+	}
+	return []rect{
+		{b.MinLon, b.MinLat, 180, b.MaxLat},
+		{-180, b.MinLat, b.MaxLon, b.MaxLat},
+	}
+}
+
+func (r rect) intersects(o rect) bool {
+	return r.minLon <= o.maxLon && o.minLon <= r.maxLon &&
+		r.minLat <= o.maxLat && o.minLat <= r.maxLat
+}
+
+// extend возвращает прямоугольник, охватывающий r и o. При first возвращается o
+func (r rect) extend(o rect, first bool) rect {
+	if first {
+		return o
+	}
+	return rect{
+		minLon: math.Min(r.minLon, o.minLon),
+		minLat: math.Min(r.minLat, o.minLat),
+		maxLon: math.Max(r.maxLon, o.maxLon),
+		maxLat: math.Max(r.maxLat, o.maxLat),
+	}
+}
+
+func (r rect) area() float64 {
+	return (r.maxLon - r.minLon) * (r.maxLat - r.minLat)
+}
+
+func (r rect) margin() float64 {
+	return (r.maxLon - r.minLon) + (r.maxLat - r.minLat)
+}
+
+func (r rect) overlap(o rect) float64 {
+	w := math.Min(r.maxLon, o.maxLon) - math.Max(r.minLon, o.minLon)
+	h := math.Min(r.maxLat, o.maxLat) - math.Max(r.minLat, o.minLat)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+func (r rect) centerLon() float64 {
+	return (r.minLon + r.maxLon) / 2
+}
+
+func (r rect) centerLat() float64 {
+	return (r.minLat + r.maxLat) / 2
+}
+
+// rectDistance вычисляет минимальное геодезическое расстояние (в километрах) от точки до прямоугольника
+func rectDistance(p types.Point, r rect) float64 {
+	lon := p.GetLongitude()
+	lat := p.GetLatitude()
+
+	// Точка внутри диапазона долгот - ближайшая точка лежит на том же меридиане
+	if lonInRange(lon, r.minLon, r.maxLon) {
+		if lat >= r.minLat && lat <= r.maxLat {
+			return 0
+		}
+		return calc.CalculateDistance(p, types.NewPoint(lon, math.Max(r.minLat, math.Min(lat, r.maxLat))))
+	}
+
+	// Иначе ближайшая точка лежит на одном из граничных меридианов
+	return math.Min(
+		meridianDistance(p, r.minLon, r.minLat, r.maxLat),
+		meridianDistance(p, r.maxLon, r.minLat, r.maxLat),
+	)
+}
+
+// lonInRange проверяет попадание долготы в диапазон с учетом периодичности
+func lonInRange(lon, minLon, maxLon float64) bool {
+	for _, shift := range []float64{0, -360, 360} {
+		l := lon + shift
+		if l >= minLon && l <= maxLon {
+			return true
+		}
+	}
+	return false
+}
+
+// meridianDistance вычисляет расстояние от точки до отрезка меридиана [minLat, maxLat]
+func meridianDistance(p types.Point, meridian, minLat, maxLat float64) float64 {
+	dLon := (p.GetLongitude() - meridian) * math.Pi / 180
+	dLon = math.Mod(dLon+3*math.Pi, 2*math.Pi) - math.Pi
+
+	// Широта ближайшей точки большого круга меридиана
+	var lat float64
+	if math.Abs(dLon) >= math.Pi/2 {
+		if p.GetLatitude() >= 0 {
+			lat = 90
+		} else {
+			lat = -90
+		}
+	} else {
+		lat = math.Atan(math.Tan(p.GetLatitude()*math.Pi/180)/math.Cos(dLon)) * 180 / math.Pi
+	}
+
+	lat = math.Max(minLat, math.Min(lat, maxLat))
+	return calc.CalculateDistance(p, types.NewPoint(meridian, lat))
+}
+
+// rtreeQueueItem - элемент очереди поиска ближайших соседей: узел или запись листа
+type rtreeQueueItem[T any] struct {
+	node  *rtreeNode[T]
+	entry rtreeEntry[T]
+	dist  float64
+}
+
+// rtreeQueue - очередь с приоритетом по расстоянию
+type rtreeQueue[T any] []rtreeQueueItem[T]
+
+func (q rtreeQueue[T]) Len() int            { return len(q) }
+func (q rtreeQueue[T]) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q rtreeQueue[T]) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *rtreeQueue[T]) Push(x interface{}) { *q = append(*q, x.(rtreeQueueItem[T])) }
+func (q *rtreeQueue[T]) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
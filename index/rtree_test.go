@@ -0,0 +1,141 @@
+package index
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// randomBoxes возвращает n случайных прямоугольников размером до size градусов
+func randomBoxes(r *rand.Rand, n int, size float64) []RTreeItem[int] {
+	items := make([]RTreeItem[int], n)
+	for i := range items {
+		lon, lat := r.Float64()*340-170, r.Float64()*160-80
+		items[i] = RTreeItem[int]{
+			BBox:  calc.BoundingBox{MinLon: lon, MinLat: lat, MaxLon: lon + r.Float64()*size, MaxLat: lat + r.Float64()*size},
+			Value: i,
+		}
+	}
+	return items
+}
+
+// bruteSearch возвращает индексы прямоугольников, пересекающихся с box (без перехода через 180-й меридиан)
+func bruteSearch(items []RTreeItem[int], box calc.BoundingBox) []int {
+	var result []int
+	for _, it := range items {
+		b := it.BBox
+		if b.MinLon <= box.MaxLon && b.MaxLon >= box.MinLon && b.MinLat <= box.MaxLat && b.MaxLat >= box.MinLat {
+			result = append(result, it.Value)
+		}
+	}
+	return result
+}
+
+func sorted(values []int) []int {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values
+}
+
+func TestRTreeSearchMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	items := randomBoxes(r, 2000, 5)
+
+	bulk := NewRTree[int](0)
+	bulk.BulkLoad(items)
+	inserted := NewRTree[int](8)
+	for _, it := range items {
+		inserted.Insert(it.BBox, it.Value)
+	}
+
+	for _, tree := range []*RTree[int]{bulk, inserted} {
+		if tree.Len() != len(items) {
+			t.Fatalf("Len = %d, want %d", tree.Len(), len(items))
+		}
+		for q := 0; q < 200; q++ {
+			lon, lat := r.Float64()*340-170, r.Float64()*160-80
+			box := calc.BoundingBox{MinLon: lon, MinLat: lat, MaxLon: lon + r.Float64()*20, MaxLat: lat + r.Float64()*10}
+			if got, want := sorted(tree.Search(box)), bruteSearch(items, box); !slices.Equal(got, want) {
+				t.Fatalf("Search(%+v) = %v, want %v", box, got, want)
+			}
+		}
+	}
+}
+
+func TestRTreeAntimeridian(t *testing.T) {
+	tree := NewRTree[string](0)
+	tree.Insert(calc.BoundingBox{MinLon: 170, MinLat: -10, MaxLon: -170, MaxLat: 10}, "fiji")
+	tree.Insert(calc.BoundingBox{MinLon: 0, MinLat: -10, MaxLon: 10, MaxLat: 10}, "gulf")
+
+	for _, lon := range []float64{175, -175, 180, -180} {
+		if got := tree.SearchPoint(types.NewPoint(lon, 0)); !slices.Equal(got, []string{"fiji"}) {
+			t.Errorf("SearchPoint(%g, 0) = %v, want [fiji]", lon, got)
+		}
+	}
+	if got := tree.Search(calc.BoundingBox{MinLon: 179, MinLat: -1, MaxLon: -179, MaxLat: 1}); !slices.Equal(got, []string{"fiji"}) {
+		t.Errorf("Search across the antimeridian = %v, want [fiji]", got)
+	}
+	if got := tree.SearchPoint(types.NewPoint(90, 0)); len(got) != 0 {
+		t.Errorf("SearchPoint(90, 0) = %v, want none", got)
+	}
+}
+
+func TestRTreeNearestPoints(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := make([]types.Point, 500)
+	tree := NewRTree[int](0)
+	for i := range points {
+		points[i] = types.NewPoint(r.Float64()*360-180, r.Float64()*170-85)
+		lon, lat := points[i].GetLongitude(), points[i].GetLatitude()
+		tree.Insert(calc.BoundingBox{MinLon: lon, MinLat: lat, MaxLon: lon, MaxLat: lat}, i)
+	}
+
+	for q := 0; q < 50; q++ {
+		query := types.NewPoint(r.Float64()*360-180, r.Float64()*170-85)
+		distances := make([]float64, len(points))
+		for i, p := range points {
+			distances[i] = calc.CalculateDistance(query, p)
+		}
+		want := slices.Clone(distances)
+		slices.Sort(want)
+
+		got := tree.Nearest(query, 5)
+		if len(got) != 5 {
+			t.Fatalf("Nearest returned %d items, want 5", len(got))
+		}
+		for i, n := range got {
+			if math.Abs(n.Distance-want[i]) > 1e-6 || math.Abs(distances[n.Value]-n.Distance) > 1e-6 {
+				t.Errorf("neighbour %d: %d at %g km, want distance %g", i, n.Value, n.Distance, want[i])
+			}
+		}
+	}
+}
+
+func TestRTreeDelete(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	items := randomBoxes(r, 300, 3)
+	tree := NewRTree[int](4)
+	tree.BulkLoad(items)
+
+	for _, it := range items[:150] {
+		value := it.Value
+		if !tree.Delete(it.BBox, func(v int) bool { return v == value }) {
+			t.Fatalf("item %d is not deleted", value)
+		}
+	}
+	if tree.Delete(items[0].BBox, func(int) bool { return true }) {
+		t.Error("deleted item is deleted again")
+	}
+	if tree.Len() != 150 {
+		t.Errorf("Len = %d, want 150", tree.Len())
+	}
+
+	world := calc.BoundingBox{MinLon: -180, MinLat: -90, MaxLon: 180, MaxLat: 90}
+	if got, want := sorted(tree.Search(world)), bruteSearch(items[150:], world); !slices.Equal(got, want) {
+		t.Errorf("remaining items = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package mvt
+
+import "math"
+
+// clipLine обрезает ломаную квадратом [lo, hi] x [lo, hi] (алгоритм Лианга-Барски).
+// Ломаная может распасться на несколько частей
+func clipLine(line [][2]float64, lo, hi float64) [][][2]float64 {
+	var parts [][][2]float64
+	var current [][2]float64
+
+	for i := 0; i+1 < len(line); i++ {
+		a, b, ok := clipSegment(line[i], line[i+1], lo, hi)
+		if !ok {
+			if len(current) > 0 {
+				parts = append(parts, current)
+				current = nil
+			}
+			continue
+		}
+
+		if len(current) == 0 {
+			current = append(current, a)
+		} else if current[len(current)-1] != a {
+			// Отрезок вошел в квадрат заново - начинаем новую часть
+			parts = append(parts, current)
+			current = [][2]float64{a}
+		}
+		current = append(current, b)
+
+		// Отрезок вышел из квадрата - часть закончилась
+		if b != line[i+1] {
+			parts = append(parts, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// clipSegment обрезает отрезок AB квадратом. Возвращает false, если отрезок лежит вне квадрата
+func clipSegment(a, b [2]float64, lo, hi float64) ([2]float64, [2]float64, bool) {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b[0]-a[0], b[1]-a[1]
+
+	for _, edge := range [4][2]float64{
+		{-dx, a[0] - lo},
+		{dx, hi - a[0]},
+		{-dy, a[1] - lo},
+		{dy, hi - a[1]},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return a, b, false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		if t0 > t1 {
+			return a, b, false
+		}
+	}
+
+	start, end := a, b
+	if t0 > 0 {
+		start = [2]float64{a[0] + t0*dx, a[1] + t0*dy}
+	}
+	if t1 < 1 {
+		end = [2]float64{a[0] + t1*dx, a[1] + t1*dy}
+	}
+	return start, end, true
+}
+
+// clipRing обрезает замкнутое кольцо квадратом (алгоритм Сазерленда-Ходжмана).
+// Кольцо передается без повторения первой точки в конце
+func clipRing(ring [][2]float64, lo, hi float64) [][2]float64 {
+	result := ring
+	for edge := 0; edge < 4 && len(result) > 0; edge++ {
+		axis := edge / 2
+		limit, keepBelow := lo, false
+		if edge%2 == 1 {
+			limit, keepBelow = hi, true
+		}
+
+		inside := func(p [2]float64) bool {
+			if keepBelow {
+				return p[axis] <= limit
+			}
+			return p[axis] >= limit
+		}
+
+		input := result
+		result = make([][2]float64, 0, len(input)+4)
+		for i := range input {
+			cur := input[i]
+			prev := input[(i+len(input)-1)%len(input)]
+			if inside(cur) != inside(prev) {
+				t := (limit - prev[axis]) / (cur[axis] - prev[axis])
+				p := [2]float64{prev[0] + t*(cur[0]-prev[0]), prev[1] + t*(cur[1]-prev[1])}
+				p[axis] = limit
+				result = append(result, p)
+			}
+			if inside(cur) {
+				result = append(result, cur)
+			}
+		}
+	}
+	return result
+}
+
+// quantize округляет координаты до целочисленной сетки и удаляет повторяющиеся подряд точки
+func quantize(points [][2]float64) [][2]int64 {
+	result := make([][2]int64, 0, len(points))
+	for _, p := range points {
+		q := [2]int64{int64(math.Round(p[0])), int64(math.Round(p[1]))}
+		if len(result) > 0 && result[len(result)-1] == q {
+			continue
+		}
+		result = append(result, q)
+	}
+	return result
+}
+
+// ringArea вычисляет удвоенную площадь кольца по формуле площади Гаусса в координатах тайла.
+// Ось Y тайла направлена вниз, поэтому положительная площадь соответствует обходу по часовой стрелке на экране
+func ringArea(ring [][2]int64) int64 {
+	var area int64
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		area += ring[j][0]*ring[i][1] - ring[i][0]*ring[j][1]
+	}
+	return area
+}
@@ -0,0 +1,317 @@
+package mvt
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/grid"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Decode разбирает векторный тайл и переводит геометрию объектов в географические координаты тайла.
+// Типов MultiPoint и MultiLineString в пакете types нет, поэтому объект MVT из нескольких точек
+// или линий превращается в несколько объектов с одинаковыми свойствами.
+// Полигоны из нескольких внешних контуров возвращаются как MultiPolygon
+func Decode(data []byte, tile grid.Tile, opts Options) ([]Layer, error) {
+	if !tile.IsValid() {
+		return nil, fmt.Errorf("decode tile %s: %w", tile, grid.ErrInvalidTile)
+	}
+
+	var layers []Layer
+	r := protoReader{buf: data}
+	for {
+		f, ok, err := r.next()
+		if err != nil {
+			return nil, fmt.Errorf("decode tile %s: %w", tile, err)
+		}
+		if !ok {
+			break
+		}
+		if f.number != tileLayers || f.wire != wireBytes {
+			continue
+		}
+
+		layer, err := decodeLayer(f.data, tile, opts)
+		if err != nil {
+			return nil, fmt.Errorf("decode tile %s: %w", tile, err)
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// rawFeature - объект слоя до разрешения словарей
+type rawFeature struct {
+	id       uint64
+	hasID    bool
+	tags     []uint32
+	geomType int
+	geometry []uint32
+}
+
+// decodeLayer разбирает слой
+func decodeLayer(data []byte, tile grid.Tile, opts Options) (Layer, error) {
+	var (
+		name     string
+		extent   uint64 = DefaultExtent
+		keys     []string
+		values   []interface{}
+		features []rawFeature
+	)
+
+	r := protoReader{buf: data}
+	for {
+		f, ok, err := r.next()
+		if err != nil {
+			return Layer{}, err
+		}
+		if !ok {
+			break
+		}
+
+		switch {
+		case f.number == layerName && f.wire == wireBytes:
+			name = string(f.data)
+		case f.number == layerExtent && f.wire == wireVarint:
+			extent = f.value
+		case f.number == layerKeys && f.wire == wireBytes:
+			keys = append(keys, string(f.data))
+		case f.number == layerValues && f.wire == wireBytes:
+			v, err := decodeValue(f.data)
+			if err != nil {
+				return Layer{}, fmt.Errorf("layer %q: %w", name, err)
+			}
+			values = append(values, v)
+		case f.number == layerFeatures && f.wire == wireBytes:
+			rf, err := decodeRawFeature(f.data)
+			if err != nil {
+				return Layer{}, fmt.Errorf("layer %q: %w", name, err)
+			}
+			features = append(features, rf)
+		}
+	}
+	if extent == 0 {
+		return Layer{}, fmt.Errorf("layer %q has zero extent: %w", name, ErrMalformed)
+	}
+
+	fc := types.NewFeatureCollection()
+	for i, rf := range features {
+		props := types.NewProperties()
+		if len(rf.tags)%2 != 0 {
+			return Layer{}, fmt.Errorf("layer %q feature %d: odd number of tags: %w", name, i, ErrMalformed)
+		}
+		for t := 0; t < len(rf.tags); t += 2 {
+			k, v := int(rf.tags[t]), int(rf.tags[t+1])
+			if k >= len(keys) || v >= len(values) {
+				return Layer{}, fmt.Errorf("layer %q feature %d: tag index out of range: %w", name, i, ErrMalformed)
+			}
+			props[keys[k]] = values[v]
+		}
+		if rf.hasID && opts.IDProperty != "" {
+			props[opts.IDProperty] = rf.id
+		}
+
+		geometries, err := decodeGeometry(rf.geomType, rf.geometry, tile, int(extent))
+		if err != nil {
+			return Layer{}, fmt.Errorf("layer %q feature %d: %w", name, i, err)
+		}
+		for g, geometry := range geometries {
+			p := props
+			if g > 0 {
+				p = make(types.Properties, len(props))
+				for k, v := range props {
+					p[k] = v
+				}
+			}
+			fc.Features = append(fc.Features, types.NewFeature(geometry, p))
+		}
+	}
+	return Layer{Name: name, Features: fc}, nil
+}
+
+// decodeRawFeature разбирает сообщение объекта
+func decodeRawFeature(data []byte) (rawFeature, error) {
+	var rf rawFeature
+	r := protoReader{buf: data}
+	for {
+		f, ok, err := r.next()
+		if err != nil {
+			return rf, err
+		}
+		if !ok {
+			return rf, nil
+		}
+
+		switch f.number {
+		case featureID:
+			rf.id, rf.hasID = f.value, true
+		case featureType:
+			rf.geomType = int(f.value)
+		case featureTags:
+			v, err := f.uint32s()
+			if err != nil {
+				return rf, err
+			}
+			rf.tags = append(rf.tags, v...)
+		case featureGeometry:
+			v, err := f.uint32s()
+			if err != nil {
+				return rf, err
+			}
+			rf.geometry = append(rf.geometry, v...)
+		}
+	}
+}
+
+// decodeValue разбирает значение свойства. Целые числа возвращаются как int64,
+// кроме беззнаковых значений, не помещающихся в int64
+func decodeValue(data []byte) (interface{}, error) {
+	r := protoReader{buf: data}
+	var result interface{}
+	for {
+		f, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return result, nil
+		}
+
+		switch f.number {
+		case valueString:
+			result = string(f.data)
+		case valueFloat:
+			result = float32FromBits(f.value)
+		case valueDouble:
+			result = float64FromBits(f.value)
+		case valueInt:
+			result = int64(f.value)
+		case valueUint:
+			if f.value > math.MaxInt64 {
+				result = f.value
+			} else {
+				result = int64(f.value)
+			}
+		case valueSint:
+			result = unzigzag(f.value)
+		case valueBool:
+			result = f.value != 0
+		}
+	}
+}
+
+// decodeGeometry выполняет команды геометрии и возвращает геометрии в географических координатах
+func decodeGeometry(geomType int, commands []uint32, tile grid.Tile, extent int) ([]types.Geometry, error) {
+	parts, err := decodeCommands(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	toPoints := func(part [][2]int64) []types.Point {
+		points := make([]types.Point, len(part))
+		for i, p := range part {
+			points[i] = tile.PixelToPoint(float64(p[0]), float64(p[1]), extent)
+		}
+		return points
+	}
+
+	var result []types.Geometry
+	switch geomType {
+	case geomUnknown:
+		// Спецификация разрешает пропускать объекты неизвестного типа
+
+	case geomPoint:
+		for _, part := range parts {
+			for _, p := range toPoints(part) {
+				result = append(result, types.NewPointGeometry(p))
+			}
+		}
+
+	case geomLineString:
+		for _, part := range parts {
+			if len(part) >= 2 {
+				result = append(result, types.NewLineStringGeometry(types.NewLineString(toPoints(part)...)))
+			}
+		}
+
+	case geomPolygon:
+		var polygons types.MultiPolygon
+		exteriorSign := int64(0)
+		for _, part := range parts {
+			area := ringArea(part)
+			if len(part) < 3 || area == 0 {
+				continue
+			}
+
+			// Знак площади первого контура определяет внешние контуры
+			if exteriorSign == 0 {
+				exteriorSign = area
+			}
+
+			// В MVT внешние контуры обходятся по часовой стрелке, в GeoJSON - против
+			ring := toPoints(append(part, part[0]))
+			for a, b := 0, len(ring)-1; a < b; a, b = a+1, b-1 {
+				ring[a], ring[b] = ring[b], ring[a]
+			}
+			if (area > 0) == (exteriorSign > 0) {
+				polygons = append(polygons, types.NewPolygon(ring))
+			} else {
+				last := len(polygons) - 1
+				polygons[last] = append(polygons[last], ring)
+			}
+		}
+
+		switch len(polygons) {
+		case 0:
+		case 1:
+			result = append(result, types.NewPolygonGeometry(polygons[0]))
+		default:
+			result = append(result, types.NewMultiPolygonGeometry(polygons))
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %d", geomType)
+	}
+	return result, nil
+}
+
+// decodeCommands выполняет команды MoveTo/LineTo/ClosePath и возвращает части геометрии
+// в координатах сетки тайла
+func decodeCommands(commands []uint32) ([][][2]int64, error) {
+	var (
+		parts [][][2]int64
+		x, y  int64
+	)
+
+	for i := 0; i < len(commands); {
+		id := int(commands[i] & 7)
+		count := int(commands[i] >> 3)
+		i++
+
+		switch id {
+		case cmdMoveTo, cmdLineTo:
+			if len(commands)-i < 2*count {
+				return nil, fmt.Errorf("command %d needs %d parameters: %w", id, 2*count, ErrMalformed)
+			}
+			if id == cmdLineTo && len(parts) == 0 {
+				return nil, fmt.Errorf("LineTo before MoveTo: %w", ErrMalformed)
+			}
+			for n := 0; n < count; n++ {
+				x += unzigzag(uint64(commands[i]))
+				y += unzigzag(uint64(commands[i+1]))
+				i += 2
+				if id == cmdMoveTo {
+					parts = append(parts, nil)
+				}
+				parts[len(parts)-1] = append(parts[len(parts)-1], [2]int64{x, y})
+			}
+		case cmdClosePath:
+			if len(parts) == 0 {
+				return nil, fmt.Errorf("ClosePath before MoveTo: %w", ErrMalformed)
+			}
+		default:
+			return nil, fmt.Errorf("unknown command %d: %w", id, ErrMalformed)
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,378 @@
+package mvt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Fliiiiii/go-geo/grid"
+	"github.com/Fliiiiii/go-geo/types"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var json = jsoniter.ConfigFastest
+
+// Encode кодирует слои в векторный тайл. Объекты без геометрии внутри тайла с буфером пропускаются
+func Encode(tile grid.Tile, layers []Layer, opts Options) ([]byte, error) {
+	if !tile.IsValid() {
+		return nil, fmt.Errorf("encode tile %s: %w", tile, grid.ErrInvalidTile)
+	}
+	if opts.Extent == 0 {
+		opts.Extent = DefaultExtent
+	}
+
+	var w protoWriter
+	names := make(map[string]struct{}, len(layers))
+	for _, layer := range layers {
+		if layer.Name == "" {
+			return nil, fmt.Errorf("encode tile %s: layer without name", tile)
+		}
+		if _, ok := names[layer.Name]; ok {
+			return nil, fmt.Errorf("encode tile %s: duplicate layer %q", tile, layer.Name)
+		}
+		names[layer.Name] = struct{}{}
+
+		data, err := encodeLayer(tile, layer, opts)
+		if err != nil {
+			return nil, fmt.Errorf("encode tile %s: %w", tile, err)
+		}
+		w.bytesField(tileLayers, data)
+	}
+	return w.buf, nil
+}
+
+// EncodeFeatureCollection кодирует набор объектов в векторный тайл с одним слоем
+func EncodeFeatureCollection(tile grid.Tile, name string, fc *types.FeatureCollection, opts Options) ([]byte, error) {
+	return Encode(tile, []Layer{{Name: name, Features: fc}}, opts)
+}
+
+// valueKey - значение свойства в словаре слоя
+type valueKey struct {
+	kind int
+	s    string
+	n    uint64
+}
+
+// layerEncoder накапливает словари ключей и значений слоя
+type layerEncoder struct {
+	tile   grid.Tile
+	opts   Options
+	keys   map[string]uint32
+	values map[valueKey]uint32
+	w      protoWriter
+	kw     protoWriter
+	vw     protoWriter
+}
+
+// encodeLayer кодирует один слой
+func encodeLayer(tile grid.Tile, layer Layer, opts Options) ([]byte, error) {
+	e := &layerEncoder{
+		tile:   tile,
+		opts:   opts,
+		keys:   make(map[string]uint32),
+		values: make(map[valueKey]uint32),
+	}
+
+	e.w.uintField(layerVersion, 2)
+	e.w.stringField(layerName, layer.Name)
+	if layer.Features != nil {
+		for i, f := range layer.Features.Features {
+			data, err := e.encodeFeature(f)
+			if err != nil {
+				return nil, fmt.Errorf("layer %q feature %d: %w", layer.Name, i, err)
+			}
+			if data != nil {
+				e.w.bytesField(layerFeatures, data)
+			}
+		}
+	}
+	e.w.buf = append(e.w.buf, e.kw.buf...)
+	e.w.buf = append(e.w.buf, e.vw.buf...)
+	e.w.uintField(layerExtent, uint64(opts.Extent))
+	return e.w.buf, nil
+}
+
+// encodeFeature кодирует объект. Возвращает nil, если геометрия целиком вне тайла
+func (e *layerEncoder) encodeFeature(f types.Feature) ([]byte, error) {
+	geomType, commands, err := e.encodeGeometry(f.Geometry)
+	if err != nil {
+		return nil, err
+	}
+	if len(commands) == 0 {
+		return nil, nil
+	}
+
+	var w protoWriter
+	if id, ok := e.featureID(f.Properties); ok {
+		w.uintField(featureID, id)
+	}
+
+	keys := make([]string, 0, len(f.Properties))
+	for k := range f.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]uint32, 0, 2*len(keys))
+	for _, k := range keys {
+		if k == e.opts.IDProperty && e.opts.IDProperty != "" {
+			continue
+		}
+		v, ok := toValue(f.Properties[k])
+		if !ok {
+			continue
+		}
+		tags = append(tags, e.key(k), e.value(v))
+	}
+
+	w.packedField(featureTags, tags)
+	w.uintField(featureType, uint64(geomType))
+	w.packedField(featureGeometry, commands)
+	return w.buf, nil
+}
+
+// featureID извлекает идентификатор объекта из свойства IDProperty
+func (e *layerEncoder) featureID(props types.Properties) (uint64, bool) {
+	if e.opts.IDProperty == "" {
+		return 0, false
+	}
+	v, ok := toValue(props[e.opts.IDProperty])
+	if !ok || v.kind != valueUint {
+		return 0, false
+	}
+	return v.n, true
+}
+
+// key возвращает индекс ключа в словаре слоя
+func (e *layerEncoder) key(k string) uint32 {
+	if idx, ok := e.keys[k]; ok {
+		return idx
+	}
+	idx := uint32(len(e.keys))
+	e.keys[k] = idx
+	e.kw.stringField(layerKeys, k)
+	return idx
+}
+
+// value возвращает индекс значения в словаре слоя
+func (e *layerEncoder) value(v valueKey) uint32 {
+	if idx, ok := e.values[v]; ok {
+		return idx
+	}
+	idx := uint32(len(e.values))
+	e.values[v] = idx
+
+	var w protoWriter
+	switch v.kind {
+	case valueString:
+		w.stringField(valueString, v.s)
+	case valueFloat:
+		w.fixed32Field(valueFloat, uint32(v.n))
+	case valueDouble:
+		w.fixed64Field(valueDouble, v.n)
+	case valueUint, valueSint, valueBool:
+		w.uintField(v.kind, v.n)
+	}
+	e.vw.bytesField(layerValues, w.buf)
+	return idx
+}
+
+// toValue переводит значение свойства в значение MVT. Целые числа (в том числе float64
+// без дробной части) записываются как uint или sint, составные значения - строкой JSON
+func toValue(v interface{}) (valueKey, bool) {
+	switch x := v.(type) {
+	case nil:
+		return valueKey{}, false
+	case string:
+		return valueKey{kind: valueString, s: x}, true
+	case bool:
+		if x {
+			return valueKey{kind: valueBool, n: 1}, true
+		}
+		return valueKey{kind: valueBool}, true
+	case int:
+		return intValue(int64(x)), true
+	case int8:
+		return intValue(int64(x)), true
+	case int16:
+		return intValue(int64(x)), true
+	case int32:
+		return intValue(int64(x)), true
+	case int64:
+		return intValue(x), true
+	case uint:
+		return valueKey{kind: valueUint, n: uint64(x)}, true
+	case uint8:
+		return valueKey{kind: valueUint, n: uint64(x)}, true
+	case uint16:
+		return valueKey{kind: valueUint, n: uint64(x)}, true
+	case uint32:
+		return valueKey{kind: valueUint, n: uint64(x)}, true
+	case uint64:
+		return valueKey{kind: valueUint, n: x}, true
+	case float32:
+		return valueKey{kind: valueFloat, n: uint64(math.Float32bits(x))}, true
+	case float64:
+		if x == math.Trunc(x) && math.Abs(x) < 1<<53 {
+			return intValue(int64(x)), true
+		}
+		return valueKey{kind: valueDouble, n: math.Float64bits(x)}, true
+	default:
+		data, err := json.Marshal(x)
+		if err != nil {
+			return valueKey{}, false
+		}
+		return valueKey{kind: valueString, s: string(data)}, true
+	}
+}
+
+// intValue выбирает представление целого числа
+func intValue(v int64) valueKey {
+	if v < 0 {
+		return valueKey{kind: valueSint, n: zigzag(v)}
+	}
+	return valueKey{kind: valueUint, n: uint64(v)}
+}
+
+// encodeGeometry переводит геометрию в команды MVT
+func (e *layerEncoder) encodeGeometry(g types.Geometry) (int, []uint32, error) {
+	var gw geometryWriter
+	switch g.Type {
+	case types.GeometryPoint:
+		p, ok := g.Coordinates.(types.Point)
+		if !ok {
+			return 0, nil, fmt.Errorf("point geometry has coordinates of type %T", g.Coordinates)
+		}
+		e.writePoint(&gw, p)
+		return geomPoint, gw.commands, nil
+
+	case types.GeometryLineString:
+		ls, ok := g.Coordinates.(types.LineString)
+		if !ok {
+			return 0, nil, fmt.Errorf("linestring geometry has coordinates of type %T", g.Coordinates)
+		}
+		e.writeLineString(&gw, ls)
+		return geomLineString, gw.commands, nil
+
+	case types.GeometryPolygon:
+		polygon, ok := g.Coordinates.(types.Polygon)
+		if !ok {
+			return 0, nil, fmt.Errorf("polygon geometry has coordinates of type %T", g.Coordinates)
+		}
+		e.writePolygon(&gw, polygon)
+		return geomPolygon, gw.commands, nil
+
+	case types.GeometryMultiPolygon:
+		mp, ok := g.Coordinates.(types.MultiPolygon)
+		if !ok {
+			return 0, nil, fmt.Errorf("multipolygon geometry has coordinates of type %T", g.Coordinates)
+		}
+		for _, polygon := range mp {
+			e.writePolygon(&gw, polygon)
+		}
+		return geomPolygon, gw.commands, nil
+
+	default:
+		return 0, nil, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+}
+
+// project переводит точки в координаты сетки тайла
+func (e *layerEncoder) project(points []types.Point) [][2]float64 {
+	result := make([][2]float64, len(points))
+	for i, p := range points {
+		x, y := e.tile.PointToPixel(p, int(e.opts.Extent))
+		result[i] = [2]float64{x, y}
+	}
+	return result
+}
+
+// clipBounds возвращает границы области обрезки с учетом буфера
+func (e *layerEncoder) clipBounds() (float64, float64) {
+	return -float64(e.opts.Buffer), float64(e.opts.Extent + e.opts.Buffer)
+}
+
+func (e *layerEncoder) writePoint(gw *geometryWriter, p types.Point) {
+	lo, hi := e.clipBounds()
+	pt := e.project([]types.Point{p})[0]
+	if pt[0] < lo || pt[0] > hi || pt[1] < lo || pt[1] > hi {
+		return
+	}
+	gw.moveTo(quantize([][2]float64{pt})[0])
+}
+
+func (e *layerEncoder) writeLineString(gw *geometryWriter, ls types.LineString) {
+	lo, hi := e.clipBounds()
+	for _, part := range clipLine(e.project(ls), lo, hi) {
+		q := quantize(part)
+		if len(q) < 2 {
+			continue
+		}
+		gw.moveTo(q[0])
+		gw.lineTo(q[1:])
+	}
+}
+
+func (e *layerEncoder) writePolygon(gw *geometryWriter, polygon types.Polygon) {
+	lo, hi := e.clipBounds()
+	for i, ring := range polygon {
+		if len(ring) > 1 && ring[0].GetLongitude() == ring[len(ring)-1].GetLongitude() &&
+			ring[0].GetLatitude() == ring[len(ring)-1].GetLatitude() {
+			ring = ring[:len(ring)-1]
+		}
+
+		q := quantize(clipRing(e.project(ring), lo, hi))
+		if len(q) > 1 && q[0] == q[len(q)-1] {
+			q = q[:len(q)-1]
+		}
+		area := ringArea(q)
+		if len(q) < 3 || area == 0 {
+			// Без внешнего контура дыры не имеют смысла
+			if i == 0 {
+				return
+			}
+			continue
+		}
+
+		// Внешний контур должен иметь положительную площадь, дыры - отрицательную
+		if (i == 0) != (area > 0) {
+			for a, b := 0, len(q)-1; a < b; a, b = a+1, b-1 {
+				q[a], q[b] = q[b], q[a]
+			}
+		}
+
+		gw.moveTo(q[0])
+		gw.lineTo(q[1:])
+		gw.closePath()
+	}
+}
+
+// geometryWriter формирует команды геометрии с относительными смещениями курсора
+type geometryWriter struct {
+	commands []uint32
+	x, y     int64
+}
+
+func (gw *geometryWriter) moveTo(p [2]int64) {
+	gw.commands = append(gw.commands, command(cmdMoveTo, 1))
+	gw.delta(p)
+}
+
+func (gw *geometryWriter) lineTo(points [][2]int64) {
+	if len(points) == 0 {
+		return
+	}
+	gw.commands = append(gw.commands, command(cmdLineTo, len(points)))
+	for _, p := range points {
+		gw.delta(p)
+	}
+}
+
+func (gw *geometryWriter) closePath() {
+	gw.commands = append(gw.commands, command(cmdClosePath, 1))
+}
+
+func (gw *geometryWriter) delta(p [2]int64) {
+	gw.commands = append(gw.commands, uint32(zigzag(p[0]-gw.x)), uint32(zigzag(p[1]-gw.y)))
+	gw.x, gw.y = p[0], p[1]
+}
@@ -0,0 +1,84 @@
+// Package mvt реализует кодирование и декодирование векторных тайлов Mapbox Vector Tile (версия 2.1).
+//
+// Объекты types.FeatureCollection переводятся в координаты тайла grid.Tile, обрезаются
+// по границе тайла с буфером, квантуются до целочисленной сетки и кодируются командами
+// MoveTo/LineTo/ClosePath. Кодек protobuf реализован в пакете без внешних зависимостей
+package mvt
+
+import (
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+const (
+	// DefaultExtent - размер сетки координат тайла по умолчанию
+	DefaultExtent = 4096
+	// DefaultBuffer - ширина буфера вокруг тайла по умолчанию в единицах сетки тайла
+	DefaultBuffer = 64
+)
+
+// Номера полей сообщений vector_tile.proto
+const (
+	tileLayers = 3
+
+	layerName     = 1
+	layerFeatures = 2
+	layerKeys     = 3
+	layerValues   = 4
+	layerExtent   = 5
+	layerVersion  = 15
+
+	featureID       = 1
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+
+	valueString = 1
+	valueFloat  = 2
+	valueDouble = 3
+	valueInt    = 4
+	valueUint   = 5
+	valueSint   = 6
+	valueBool   = 7
+)
+
+// Типы геометрии MVT
+const (
+	geomUnknown    = 0
+	geomPoint      = 1
+	geomLineString = 2
+	geomPolygon    = 3
+)
+
+// Команды геометрии MVT
+const (
+	cmdMoveTo    = 1
+	cmdLineTo    = 2
+	cmdClosePath = 7
+)
+
+// Layer - слой векторного тайла
+type Layer struct {
+	Name     string
+	Features *types.FeatureCollection
+}
+
+// Options - параметры кодирования и декодирования
+type Options struct {
+	// Extent - размер сетки координат тайла, по умолчанию DefaultExtent
+	Extent uint32
+	// Buffer - ширина буфера вокруг тайла в единицах сетки; геометрия за буфером обрезается
+	Buffer uint32
+	// IDProperty - свойство объекта, из которого берется идентификатор объекта MVT
+	// и в которое он записывается при декодировании. Пустая строка отключает идентификаторы
+	IDProperty string
+}
+
+// DefaultOptions возвращает параметры по умолчанию
+func DefaultOptions() Options {
+	return Options{Extent: DefaultExtent, Buffer: DefaultBuffer}
+}
+
+// command формирует целое число команды
+func command(id, count int) uint32 {
+	return uint32(id&7) | uint32(count)<<3
+}
@@ -0,0 +1,186 @@
+package mvt
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/grid"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// testTile - тайл 12-го масштаба в центре Москвы
+var testTile = grid.PointToTile(types.NewPoint(37.6173, 55.7558), 12)
+
+// tilePoint возвращает точку по координатам сетки тайла
+func tilePoint(x, y float64) types.Point {
+	return testTile.PixelToPoint(x, y, DefaultExtent)
+}
+
+// tileSquare возвращает квадрат в координатах сетки тайла с обходом против часовой стрелки
+func tileSquare(x0, y0, x1, y1 float64) types.LineString {
+	return types.NewLineString(tilePoint(x0, y1), tilePoint(x1, y1), tilePoint(x1, y0), tilePoint(x0, y0), tilePoint(x0, y1))
+}
+
+// signedArea вычисляет ориентированную площадь контура на плоскости долгота/широта
+func signedArea(ring types.LineString) float64 {
+	area := 0.0
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i].GetLongitude()*ring[i+1].GetLatitude() - ring[i+1].GetLongitude()*ring[i].GetLatitude()
+	}
+	return area / 2
+}
+
+// assertNear проверяет, что точки совпадают с точностью до шага сетки тайла
+func assertNear(t *testing.T, got, want types.Point) {
+	t.Helper()
+	gx, gy := testTile.PointToPixel(got, DefaultExtent)
+	wx, wy := testTile.PointToPixel(want, DefaultExtent)
+	if math.Abs(gx-wx) > 0.5+1e-6 || math.Abs(gy-wy) > 0.5+1e-6 {
+		t.Errorf("point %v, want %v", got, want)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	polygon := types.NewPolygon(tileSquare(100, 100, 1000, 1000), tileSquare(400, 400, 600, 600))
+	polygon[1] = polygon[1].Reverse()
+	line := types.NewLineString(tilePoint(10, 20), tilePoint(2000.4, 3000.6), tilePoint(4000, 100))
+
+	fc := types.NewFeatureCollection(
+		types.NewFeature(types.NewPointGeometry(tilePoint(2048, 2048)), types.Properties{
+			"id": uint64(7), "name": "центр", "rank": 3, "delta": -12, "ratio": 0.25, "open": true,
+		}),
+		types.NewFeature(types.NewLineStringGeometry(line), types.Properties{"name": "линия"}),
+		types.NewFeature(types.NewPolygonGeometry(polygon), types.Properties{"tags": []string{"a", "b"}}),
+		types.NewFeature(types.NewMultiPolygonGeometry(types.MultiPolygon{
+			types.NewPolygon(tileSquare(2000, 2000, 2100, 2100)),
+			types.NewPolygon(tileSquare(3000, 3000, 3100, 3100)),
+		}), nil),
+	)
+
+	opts := DefaultOptions()
+	opts.IDProperty = "id"
+	data, err := EncodeFeatureCollection(testTile, "objects", fc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers, err := Decode(data, testTile, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 1 || layers[0].Name != "objects" {
+		t.Fatalf("decoded layers %+v", layers)
+	}
+	features := layers[0].Features.Features
+	if len(features) != 4 {
+		t.Fatalf("decoded %d features, want 4", len(features))
+	}
+
+	wantProps := []types.Properties{
+		{"id": uint64(7), "name": "центр", "rank": int64(3), "delta": int64(-12), "ratio": 0.25, "open": true},
+		{"name": "линия"},
+		{"tags": `["a","b"]`},
+		{},
+	}
+	for i, f := range features {
+		if !reflect.DeepEqual(f.Properties, wantProps[i]) {
+			t.Errorf("feature %d properties %v, want %v", i, f.Properties, wantProps[i])
+		}
+		if f.Geometry.Type != fc.Features[i].Geometry.Type {
+			t.Errorf("feature %d type %q, want %q", i, f.Geometry.Type, fc.Features[i].Geometry.Type)
+		}
+	}
+
+	assertNear(t, features[0].Geometry.Coordinates.(types.Point), tilePoint(2048, 2048))
+
+	gotLine := features[1].Geometry.Coordinates.(types.LineString)
+	if len(gotLine) != len(line) {
+		t.Fatalf("decoded line has %d points, want %d", len(gotLine), len(line))
+	}
+	for i := range line {
+		assertNear(t, gotLine[i], line[i])
+	}
+
+	gotPolygon := features[2].Geometry.Coordinates.(types.Polygon)
+	if len(gotPolygon) != 2 {
+		t.Fatalf("decoded polygon has %d rings, want 2", len(gotPolygon))
+	}
+	// Ориентация контуров GeoJSON восстанавливается: внешний против часовой стрелки, дыра - по
+	if signedArea(gotPolygon[0]) <= 0 || signedArea(gotPolygon[1]) >= 0 {
+		t.Errorf("decoded rings have wrong orientation")
+	}
+	for r := range polygon {
+		if math.Abs(signedArea(gotPolygon[r])-signedArea(polygon[r])) > 1e-3*math.Abs(signedArea(polygon[r])) {
+			t.Errorf("ring %d area %g, want %g", r, signedArea(gotPolygon[r]), signedArea(polygon[r]))
+		}
+	}
+
+	if got := features[3].Geometry.Coordinates.(types.MultiPolygon); len(got) != 2 {
+		t.Errorf("decoded multipolygon has %d polygons, want 2", len(got))
+	}
+}
+
+func TestEncodeClipsToBuffer(t *testing.T) {
+	opts := DefaultOptions()
+	lo, hi := -float64(opts.Buffer), float64(opts.Extent+opts.Buffer)
+
+	fc := types.NewFeatureCollection(
+		types.NewFeature(types.NewPolygonGeometry(types.NewPolygon(tileSquare(-5000, -5000, 9000, 9000))), nil),
+		types.NewFeature(types.NewLineStringGeometry(types.NewLineString(tilePoint(-1000, 2000), tilePoint(5000, 2000))), nil),
+		// Объекты за пределами буфера пропускаются
+		types.NewFeature(types.NewPointGeometry(tilePoint(-500, 100)), nil),
+		types.NewFeature(types.NewPolygonGeometry(types.NewPolygon(tileSquare(5000, 5000, 6000, 6000))), nil),
+	)
+	data, err := EncodeFeatureCollection(testTile, "clip", fc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers, err := Decode(data, testTile, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features := layers[0].Features.Features
+	if len(features) != 2 {
+		t.Fatalf("decoded %d features, want 2", len(features))
+	}
+
+	check := func(points []types.Point) {
+		for _, p := range points {
+			x, y := testTile.PointToPixel(p, DefaultExtent)
+			if x < lo-0.5 || x > hi+0.5 || y < lo-0.5 || y > hi+0.5 {
+				t.Errorf("point (%g, %g) lies outside the buffer", x, y)
+			}
+		}
+	}
+	check(features[0].Geometry.Coordinates.(types.Polygon)[0])
+	check(features[1].Geometry.Coordinates.(types.LineString))
+}
+
+func TestEncodeErrors(t *testing.T) {
+	fc := types.NewFeatureCollection()
+	if _, err := EncodeFeatureCollection(grid.Tile{X: 5, Y: 0, Z: 1}, "a", fc, DefaultOptions()); !errors.Is(err, grid.ErrInvalidTile) {
+		t.Errorf("invalid tile error = %v", err)
+	}
+	if _, err := Encode(testTile, []Layer{{Name: "a"}, {Name: "a"}}, DefaultOptions()); err == nil {
+		t.Error("duplicate layer names accepted")
+	}
+	if _, err := Encode(testTile, []Layer{{}}, DefaultOptions()); err == nil {
+		t.Error("layer without name accepted")
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	fc := types.NewFeatureCollection(types.NewFeature(types.NewLineStringGeometry(
+		types.NewLineString(tilePoint(0, 0), tilePoint(100, 100))), types.Properties{"k": "v"}))
+	data, err := EncodeFeatureCollection(testTile, "a", fc, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range [][]byte{data[:len(data)-1], {0}, {0x1a, 0x7f}} {
+		if _, err := Decode(bad, testTile, DefaultOptions()); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Decode(%x) error = %v, want ErrMalformed", bad, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package mvt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Типы передачи полей protobuf
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ErrMalformed возвращается при разборе некорректного буфера protobuf
+var ErrMalformed = errors.New("malformed protobuf")
+
+// protoWriter - минимальный кодировщик сообщений protobuf
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) varint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *protoWriter) key(field, wire int) {
+	w.varint(uint64(field)<<3 | uint64(wire))
+}
+
+func (w *protoWriter) uintField(field int, v uint64) {
+	w.key(field, wireVarint)
+	w.varint(v)
+}
+
+func (w *protoWriter) bytesField(field int, b []byte) {
+	w.key(field, wireBytes)
+	w.varint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *protoWriter) stringField(field int, s string) {
+	w.key(field, wireBytes)
+	w.varint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *protoWriter) fixed32Field(field int, v uint32) {
+	w.key(field, wireFixed32)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, v)
+}
+
+func (w *protoWriter) fixed64Field(field int, v uint64) {
+	w.key(field, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, v)
+}
+
+// packedField записывает упакованное повторяющееся поле из беззнаковых чисел
+func (w *protoWriter) packedField(field int, values []uint32) {
+	if len(values) == 0 {
+		return
+	}
+	var inner protoWriter
+	for _, v := range values {
+		inner.varint(uint64(v))
+	}
+	w.bytesField(field, inner.buf)
+}
+
+// protoField - поле сообщения, прочитанное protoReader
+type protoField struct {
+	number int
+	wire   int
+	// value - значение полей varint, fixed32 и fixed64
+	value uint64
+	// data - содержимое полей с длиной
+	data []byte
+}
+
+// protoReader - минимальный разборщик сообщений protobuf
+type protoReader struct {
+	buf []byte
+	pos int
+}
+
+// next читает очередное поле. Возвращает false, когда сообщение закончилось
+func (r *protoReader) next() (protoField, bool, error) {
+	if r.pos >= len(r.buf) {
+		return protoField{}, false, nil
+	}
+
+	key, err := r.varint()
+	if err != nil {
+		return protoField{}, false, err
+	}
+	f := protoField{number: int(key >> 3), wire: int(key & 7)}
+	if f.number == 0 {
+		return protoField{}, false, fmt.Errorf("field number 0 at offset %d: %w", r.pos, ErrMalformed)
+	}
+
+	switch f.wire {
+	case wireVarint:
+		f.value, err = r.varint()
+	case wireFixed64:
+		if len(r.buf)-r.pos < 8 {
+			return protoField{}, false, fmt.Errorf("truncated fixed64 field %d: %w", f.number, ErrMalformed)
+		}
+		f.value = binary.LittleEndian.Uint64(r.buf[r.pos:])
+		r.pos += 8
+	case wireFixed32:
+		if len(r.buf)-r.pos < 4 {
+			return protoField{}, false, fmt.Errorf("truncated fixed32 field %d: %w", f.number, ErrMalformed)
+		}
+		f.value = uint64(binary.LittleEndian.Uint32(r.buf[r.pos:]))
+		r.pos += 4
+	case wireBytes:
+		var n uint64
+		n, err = r.varint()
+		if err == nil && n > uint64(len(r.buf)-r.pos) {
+			err = fmt.Errorf("field %d length %d exceeds buffer: %w", f.number, n, ErrMalformed)
+		}
+		if err == nil {
+			f.data = r.buf[r.pos : r.pos+int(n)]
+			r.pos += int(n)
+		}
+	default:
+		err = fmt.Errorf("unsupported wire type %d of field %d: %w", f.wire, f.number, ErrMalformed)
+	}
+	if err != nil {
+		return protoField{}, false, err
+	}
+	return f, true, nil
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("bad varint at offset %d: %w", r.pos, ErrMalformed)
+	}
+	r.pos += n
+	return v, nil
+}
+
+// uint32s возвращает значения повторяющегося поля, записанного упакованным или обычным способом
+func (f protoField) uint32s() ([]uint32, error) {
+	if f.wire == wireVarint {
+		return []uint32{uint32(f.value)}, nil
+	}
+	if f.wire != wireBytes {
+		return nil, fmt.Errorf("field %d has wire type %d, expected packed varints: %w", f.number, f.wire, ErrMalformed)
+	}
+
+	r := protoReader{buf: f.data}
+	values := make([]uint32, 0, len(f.data))
+	for r.pos < len(r.buf) {
+		v, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, uint32(v))
+	}
+	return values, nil
+}
+
+// zigzag кодирует знаковое число в беззнаковое
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// unzigzag декодирует число, закодированное zigzag
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// float32FromBits и float64FromBits восстанавливают числа из полей fixed32 и fixed64
+func float32FromBits(v uint64) float64 {
+	return float64(math.Float32frombits(uint32(v)))
+}
+
+func float64FromBits(v uint64) float64 {
+	return math.Float64frombits(v)
+}
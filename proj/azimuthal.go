@@ -0,0 +1,222 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// AzimuthalParams содержит параметры азимутальных проекций
+type AzimuthalParams struct {
+	Ellipsoid Ellipsoid
+	// CenterLon, CenterLat - центр проекции (в градусах)
+	CenterLon     float64
+	CenterLat     float64
+	FalseEasting  float64
+	FalseNorthing float64
+}
+
+// LambertAzimuthalEqualArea - равновеликая азимутальная проекция Ламберта на эллипсоиде
+type LambertAzimuthalEqualArea struct {
+	params AzimuthalParams
+	e      float64
+	lon0   float64
+	qp     float64
+	rq     float64
+	d      float64
+	sinB1  float64
+	cosB1  float64
+	// polar - знак полюса для полярного аспекта, ноль для косого и экваториального
+	polar float64
+}
+
+// NewLambertAzimuthalEqualArea создает равновеликую азимутальную проекцию Ламберта
+func NewLambertAzimuthalEqualArea(params AzimuthalParams) (*LambertAzimuthalEqualArea, error) {
+	if params.CenterLat < -90 || params.CenterLat > 90 {
+		return nil, fmt.Errorf("invalid center latitude %v", params.CenterLat)
+	}
+
+	e := params.Ellipsoid.E()
+	a := params.Ellipsoid.A
+	phi1 := toRadians(params.CenterLat)
+
+	laea := &LambertAzimuthalEqualArea{
+		params: params,
+		e:      e,
+		lon0:   toRadians(params.CenterLon),
+		qp:     qsfn(e, math.Pi/2),
+	}
+	laea.rq = a * math.Sqrt(laea.qp/2)
+
+	if math.Abs(math.Abs(params.CenterLat)-90) < 1e-10 {
+		laea.polar = math.Copysign(1, params.CenterLat)
+		return laea, nil
+	}
+
+	beta1 := math.Asin(qsfn(e, phi1) / laea.qp)
+	laea.sinB1 = math.Sin(beta1)
+	laea.cosB1 = math.Cos(beta1)
+	laea.d = a * msfn(params.Ellipsoid.E2(), phi1) / (laea.rq * laea.cosB1)
+	return laea, nil
+}
+
+// Params возвращает параметры проекции
+func (laea *LambertAzimuthalEqualArea) Params() AzimuthalParams {
+	return laea.params
+}
+
+// Forward преобразует географические координаты в проекционные
+func (laea *LambertAzimuthalEqualArea) Forward(p types.Point) (types.Point, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+
+	q := qsfn(laea.e, toRadians(p.GetLatitude()))
+	dLon := normalizeLon(toRadians(p.GetLongitude()) - laea.lon0)
+
+	var x, y float64
+	if laea.polar != 0 {
+		// Полярный аспект
+		rho := laea.params.Ellipsoid.A * math.Sqrt(math.Max(laea.qp-laea.polar*q, 0))
+		x = rho * math.Sin(dLon)
+		y = -laea.polar * rho * math.Cos(dLon)
+	} else {
+		beta := math.Asin(math.Max(-1, math.Min(1, q/laea.qp)))
+		sinB, cosB := math.Sin(beta), math.Cos(beta)
+
+		denom := 1 + laea.sinB1*sinB + laea.cosB1*cosB*math.Cos(dLon)
+		if denom < 1e-12 {
+			return nil, fmt.Errorf("antipode of the projection center: %w", ErrOutOfDomain)
+		}
+
+		b := laea.rq * math.Sqrt(2/denom)
+		x = b * laea.d * cosB * math.Sin(dLon)
+		y = b / laea.d * (laea.cosB1*sinB - laea.sinB1*cosB*math.Cos(dLon))
+	}
+
+	return types.NewPoint(x+laea.params.FalseEasting, y+laea.params.FalseNorthing), nil
+}
+
+// Inverse преобразует проекционные координаты в географические
+func (laea *LambertAzimuthalEqualArea) Inverse(p types.Point) (types.Point, error) {
+	x := p.GetLongitude() - laea.params.FalseEasting
+	y := p.GetLatitude() - laea.params.FalseNorthing
+	a := laea.params.Ellipsoid.A
+
+	var q, lon float64
+	if laea.polar != 0 {
+		rho := math.Hypot(x, y)
+		q = laea.polar * (laea.qp - rho*rho/(a*a))
+		lon = laea.lon0 + math.Atan2(x, -laea.polar*y)
+	} else {
+		rho := math.Hypot(x/laea.d, laea.d*y)
+		if rho < 1e-12 {
+			return types.NewPoint(laea.params.CenterLon, laea.params.CenterLat), nil
+		}
+		if rho > 2*laea.rq*(1+1e-12) {
+			return nil, fmt.Errorf("point is beyond the projection boundary: %w", ErrOutOfDomain)
+		}
+
+		ce := 2 * math.Asin(math.Min(1, rho/(2*laea.rq)))
+		sinCe, cosCe := math.Sin(ce), math.Cos(ce)
+
+		sinBeta := cosCe*laea.sinB1 + laea.d*y*sinCe*laea.cosB1/rho
+		q = laea.qp * math.Max(-1, math.Min(1, sinBeta))
+		lon = laea.lon0 + math.Atan2(x*sinCe, laea.d*rho*laea.cosB1*cosCe-laea.d*laea.d*y*laea.sinB1*sinCe)
+	}
+
+	if math.Abs(q) > laea.qp+1e-9 {
+		return nil, fmt.Errorf("point is beyond the pole: %w", ErrOutOfDomain)
+	}
+
+	var phi float64
+	if math.Abs(q) >= laea.qp-1e-12 {
+		phi = math.Copysign(math.Pi/2, q)
+	} else {
+		phi = phiFromQ(laea.e, q)
+	}
+
+	return types.NewPoint(toDegrees(normalizeLon(lon)), toDegrees(phi)), nil
+}
+
+// AzimuthalEquidistant - азимутальная равнопромежуточная проекция.
+// Вычисления выполняются на сфере со средним радиусом эллипсоида, поэтому расстояния от центра
+// сохраняются с точностью порядка 0,5%
+type AzimuthalEquidistant struct {
+	params AzimuthalParams
+	r      float64
+	lon0   float64
+	sinLat float64
+	cosLat float64
+}
+
+// NewAzimuthalEquidistant создает азимутальную равнопромежуточную проекцию
+func NewAzimuthalEquidistant(params AzimuthalParams) (*AzimuthalEquidistant, error) {
+	if params.CenterLat < -90 || params.CenterLat > 90 {
+		return nil, fmt.Errorf("invalid center latitude %v", params.CenterLat)
+	}
+
+	lat0 := toRadians(params.CenterLat)
+	return &AzimuthalEquidistant{
+		params: params,
+		r:      params.Ellipsoid.MeanRadius(),
+		lon0:   toRadians(params.CenterLon),
+		sinLat: math.Sin(lat0),
+		cosLat: math.Cos(lat0),
+	}, nil
+}
+
+// Params возвращает параметры проекции
+func (aeqd *AzimuthalEquidistant) Params() AzimuthalParams {
+	return aeqd.params
+}
+
+// Forward преобразует географические координаты в проекционные
+func (aeqd *AzimuthalEquidistant) Forward(p types.Point) (types.Point, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+
+	lat := toRadians(p.GetLatitude())
+	dLon := normalizeLon(toRadians(p.GetLongitude()) - aeqd.lon0)
+	sinLat, cosLat := math.Sin(lat), math.Cos(lat)
+
+	cosC := aeqd.sinLat*sinLat + aeqd.cosLat*cosLat*math.Cos(dLon)
+	cosC = math.Max(-1, math.Min(1, cosC))
+	c := math.Acos(cosC)
+
+	if math.Pi-c < 1e-12 {
+		return nil, fmt.Errorf("antipode of the projection center: %w", ErrOutOfDomain)
+	}
+
+	k := 1.0
+	if c > 1e-12 {
+		k = c / math.Sin(c)
+	}
+
+	x := aeqd.r * k * cosLat * math.Sin(dLon)
+	y := aeqd.r * k * (aeqd.cosLat*sinLat - aeqd.sinLat*cosLat*math.Cos(dLon))
+	return types.NewPoint(x+aeqd.params.FalseEasting, y+aeqd.params.FalseNorthing), nil
+}
+
+// Inverse преобразует проекционные координаты в географические
+func (aeqd *AzimuthalEquidistant) Inverse(p types.Point) (types.Point, error) {
+	x := p.GetLongitude() - aeqd.params.FalseEasting
+	y := p.GetLatitude() - aeqd.params.FalseNorthing
+
+	rho := math.Hypot(x, y)
+	if rho < 1e-9 {
+		return types.NewPoint(aeqd.params.CenterLon, aeqd.params.CenterLat), nil
+	}
+
+	c := rho / aeqd.r
+	if c > math.Pi {
+		return nil, fmt.Errorf("point is beyond the projection boundary: %w", ErrOutOfDomain)
+	}
+	sinC, cosC := math.Sin(c), math.Cos(c)
+
+	lat := math.Asin(math.Max(-1, math.Min(1, cosC*aeqd.sinLat+y*sinC*aeqd.cosLat/rho)))
+	lon := aeqd.lon0 + math.Atan2(x*sinC, rho*aeqd.cosLat*cosC-y*aeqd.sinLat*sinC)
+	return types.NewPoint(toDegrees(normalizeLon(lon)), toDegrees(lat)), nil
+}
@@ -0,0 +1,238 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// ConicParams содержит параметры конических проекций
+type ConicParams struct {
+	Ellipsoid Ellipsoid
+	// CentralMeridian - осевой меридиан (в градусах)
+	CentralMeridian float64
+	// LatitudeOfOrigin - широта начала координат (в градусах)
+	LatitudeOfOrigin float64
+	// StandardParallel1, StandardParallel2 - стандартные параллели (в градусах)
+	StandardParallel1 float64
+	StandardParallel2 float64
+	FalseEasting      float64
+	FalseNorthing     float64
+}
+
+// validate проверяет параметры конической проекции
+func (c ConicParams) validate() error {
+	if math.Abs(c.StandardParallel1+c.StandardParallel2) < 1e-10 {
+		return fmt.Errorf("standard parallels %v and %v are symmetric about the equator",
+			c.StandardParallel1, c.StandardParallel2)
+	}
+	for _, lat := range []float64{c.StandardParallel1, c.StandardParallel2, c.LatitudeOfOrigin} {
+		if lat < -90 || lat > 90 {
+			return fmt.Errorf("invalid latitude %v in conic parameters", lat)
+		}
+	}
+	return nil
+}
+
+// LambertConformalConic - равноугольная коническая проекция Ламберта с двумя стандартными параллелями
+type LambertConformalConic struct {
+	params ConicParams
+	e      float64
+	lon0   float64
+	n      float64
+	aF     float64
+	rho0   float64
+}
+
+// NewLambertConformalConic создает равноугольную коническую проекцию Ламберта
+func NewLambertConformalConic(params ConicParams) (*LambertConformalConic, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	e := params.Ellipsoid.E()
+	e2 := params.Ellipsoid.E2()
+	phi1 := toRadians(params.StandardParallel1)
+	phi2 := toRadians(params.StandardParallel2)
+	phi0 := toRadians(params.LatitudeOfOrigin)
+
+	m1 := msfn(e2, phi1)
+	t1 := tsfn(e, phi1)
+
+	var n float64
+	if math.Abs(phi1-phi2) < 1e-10 {
+		n = math.Sin(phi1)
+	} else {
+		n = (math.Log(m1) - math.Log(msfn(e2, phi2))) / (math.Log(t1) - math.Log(tsfn(e, phi2)))
+	}
+
+	lcc := &LambertConformalConic{
+		params: params,
+		e:      e,
+		lon0:   toRadians(params.CentralMeridian),
+		n:      n,
+		aF:     params.Ellipsoid.A * m1 / (n * math.Pow(t1, n)),
+	}
+	lcc.rho0 = lcc.rho(phi0)
+	return lcc, nil
+}
+
+// Params возвращает параметры проекции
+func (lcc *LambertConformalConic) Params() ConicParams {
+	return lcc.params
+}
+
+// rho вычисляет радиус параллели в проекции
+func (lcc *LambertConformalConic) rho(phi float64) float64 {
+	if math.Abs(math.Abs(phi)-math.Pi/2) < 1e-12 {
+		if phi*lcc.n > 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return lcc.aF * math.Pow(tsfn(lcc.e, phi), lcc.n)
+}
+
+// Forward преобразует географические координаты в проекционные
+func (lcc *LambertConformalConic) Forward(p types.Point) (types.Point, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+
+	rho := lcc.rho(toRadians(p.GetLatitude()))
+	if math.IsInf(rho, 0) {
+		return nil, fmt.Errorf("pole opposite to the cone apex: %w", ErrOutOfDomain)
+	}
+
+	theta := lcc.n * normalizeLon(toRadians(p.GetLongitude())-lcc.lon0)
+	return types.NewPoint(
+		rho*math.Sin(theta)+lcc.params.FalseEasting,
+		lcc.rho0-rho*math.Cos(theta)+lcc.params.FalseNorthing,
+	), nil
+}
+
+// Inverse преобразует проекционные координаты в географические
+func (lcc *LambertConformalConic) Inverse(p types.Point) (types.Point, error) {
+	x := p.GetLongitude() - lcc.params.FalseEasting
+	y := lcc.rho0 - (p.GetLatitude() - lcc.params.FalseNorthing)
+
+	sign := 1.0
+	if lcc.n < 0 {
+		sign = -1
+	}
+
+	rho := sign * math.Hypot(x, y)
+	theta := math.Atan2(sign*x, sign*y)
+
+	var phi float64
+	if rho == 0 {
+		phi = sign * math.Pi / 2
+	} else {
+		phi = phi2(lcc.e, math.Pow(rho/lcc.aF, 1/lcc.n))
+	}
+
+	lon := normalizeLon(theta/lcc.n + lcc.lon0)
+	return types.NewPoint(toDegrees(lon), toDegrees(phi)), nil
+}
+
+// AlbersEqualArea - равновеликая коническая проекция Альберса
+type AlbersEqualArea struct {
+	params ConicParams
+	e      float64
+	lon0   float64
+	n      float64
+	c      float64
+	rho0   float64
+}
+
+// NewAlbersEqualArea создает равновеликую коническую проекцию Альберса
+func NewAlbersEqualArea(params ConicParams) (*AlbersEqualArea, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	e := params.Ellipsoid.E()
+	e2 := params.Ellipsoid.E2()
+	phi1 := toRadians(params.StandardParallel1)
+	phi2 := toRadians(params.StandardParallel2)
+
+	m1 := msfn(e2, phi1)
+	q1 := qsfn(e, phi1)
+
+	var n float64
+	if math.Abs(phi1-phi2) < 1e-10 {
+		n = math.Sin(phi1)
+	} else {
+		m2 := msfn(e2, phi2)
+		n = (m1*m1 - m2*m2) / (qsfn(e, phi2) - q1)
+	}
+
+	aea := &AlbersEqualArea{
+		params: params,
+		e:      e,
+		lon0:   toRadians(params.CentralMeridian),
+		n:      n,
+		c:      m1*m1 + n*q1,
+	}
+	aea.rho0 = aea.rho(toRadians(params.LatitudeOfOrigin))
+	return aea, nil
+}
+
+// Params возвращает параметры проекции
+func (aea *AlbersEqualArea) Params() ConicParams {
+	return aea.params
+}
+
+// rho вычисляет радиус параллели в проекции
+func (aea *AlbersEqualArea) rho(phi float64) float64 {
+	v := aea.c - aea.n*qsfn(aea.e, phi)
+	return aea.params.Ellipsoid.A * math.Sqrt(math.Max(v, 0)) / aea.n
+}
+
+// Forward преобразует географические координаты в проекционные
+func (aea *AlbersEqualArea) Forward(p types.Point) (types.Point, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+
+	rho := aea.rho(toRadians(p.GetLatitude()))
+	theta := aea.n * normalizeLon(toRadians(p.GetLongitude())-aea.lon0)
+	return types.NewPoint(
+		rho*math.Sin(theta)+aea.params.FalseEasting,
+		aea.rho0-rho*math.Cos(theta)+aea.params.FalseNorthing,
+	), nil
+}
+
+// Inverse преобразует проекционные координаты в географические
+func (aea *AlbersEqualArea) Inverse(p types.Point) (types.Point, error) {
+	x := p.GetLongitude() - aea.params.FalseEasting
+	y := aea.rho0 - (p.GetLatitude() - aea.params.FalseNorthing)
+
+	sign := 1.0
+	if aea.n < 0 {
+		sign = -1
+	}
+
+	rho := math.Hypot(x, y)
+	theta := math.Atan2(sign*x, sign*y)
+
+	a := aea.params.Ellipsoid.A
+	q := (aea.c - rho*rho*aea.n*aea.n/(a*a)) / aea.n
+
+	// Предельное значение q на полюсах
+	qp := qsfn(aea.e, math.Pi/2)
+	if math.Abs(q) > qp+1e-9 {
+		return nil, fmt.Errorf("point is beyond the pole: %w", ErrOutOfDomain)
+	}
+
+	var phi float64
+	if math.Abs(q) >= qp-1e-12 {
+		phi = math.Copysign(math.Pi/2, q)
+	} else {
+		phi = phiFromQ(aea.e, q)
+	}
+
+	lon := normalizeLon(theta/aea.n + aea.lon0)
+	return types.NewPoint(toDegrees(lon), toDegrees(phi)), nil
+}
@@ -0,0 +1,119 @@
+package proj
+
+import "math"
+
+// Ellipsoid описывает эллипсоид вращения
+type Ellipsoid struct {
+	// Name - название эллипсоида
+	Name string
+	// A - большая полуось (в метрах)
+	A float64
+	// InvFlattening - обратное сжатие (1/f). Ноль означает сферу
+	InvFlattening float64
+}
+
+// Распространенные эллипсоиды
+var (
+	WGS84 = Ellipsoid{Name: "WGS 84", A: 6378137.0, InvFlattening: 298.257223563}
+	GRS80 = Ellipsoid{Name: "GRS 1980", A: 6378137.0, InvFlattening: 298.257222101}
+	// Krassovsky - эллипсоид Красовского, используемый в СК-42 и СК-95
+	Krassovsky = Ellipsoid{Name: "Krassovsky 1940", A: 6378245.0, InvFlattening: 298.3}
+	// PZ90 - общеземной эллипсоид системы ПЗ-90
+	PZ90 = Ellipsoid{Name: "PZ-90", A: 6378136.0, InvFlattening: 298.25784}
+	// GSK2011 - эллипсоид геодезической системы координат 2011 года
+	GSK2011 = Ellipsoid{Name: "GSK-2011", A: 6378136.5, InvFlattening: 298.2564151}
+	// WebMercatorSphere - сфера, используемая проекцией Web Mercator
+	WebMercatorSphere = Ellipsoid{Name: "Popular Visualisation Sphere", A: 6378137.0}
+)
+
+// F возвращает сжатие эллипсоида
+func (e Ellipsoid) F() float64 {
+	if e.InvFlattening == 0 {
+		return 0
+	}
+	return 1 / e.InvFlattening
+}
+
+// B возвращает малую полуось (в метрах)
+func (e Ellipsoid) B() float64 {
+	return e.A * (1 - e.F())
+}
+
+// E2 возвращает квадрат первого эксцентриситета
+func (e Ellipsoid) E2() float64 {
+	f := e.F()
+	return f * (2 - f)
+}
+
+// E возвращает первый эксцентриситет
+func (e Ellipsoid) E() float64 {
+	return math.Sqrt(e.E2())
+}
+
+// MeanRadius возвращает средний радиус (2a + b) / 3 (в метрах)
+func (e Ellipsoid) MeanRadius() float64 {
+	return (2*e.A + e.B()) / 3
+}
+
+// msfn вычисляет m = cos(φ) / sqrt(1 - e² sin²(φ))
+func msfn(e2, phi float64) float64 {
+	sinPhi := math.Sin(phi)
+	return math.Cos(phi) / math.Sqrt(1-e2*sinPhi*sinPhi)
+}
+
+// tsfn вычисляет t = tan(π/4 - φ/2) / ((1 - e sin(φ)) / (1 + e sin(φ)))^(e/2)
+func tsfn(e, phi float64) float64 {
+	sinPhi := math.Sin(phi)
+	return math.Tan(math.Pi/4-phi/2) / math.Pow((1-e*sinPhi)/(1+e*sinPhi), e/2)
+}
+
+// phi2 восстанавливает широту по величине t итерациями (обратная функция к tsfn)
+func phi2(e, t float64) float64 {
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 15; i++ {
+		sinPhi := math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-e*sinPhi)/(1+e*sinPhi), e/2))
+		if math.Abs(next-phi) < 1e-12 {
+			return next
+		}
+		phi = next
+	}
+	return phi
+}
+
+// qsfn вычисляет величину q, используемую в равновеликих проекциях
+func qsfn(e, phi float64) float64 {
+	sinPhi := math.Sin(phi)
+	if e < 1e-10 {
+		return 2 * sinPhi
+	}
+	e2 := e * e
+	con := e * sinPhi
+	return (1 - e2) * (sinPhi/(1-con*con) - math.Log((1-con)/(1+con))/(2*e))
+}
+
+// phiFromQ восстанавливает широту по величине q итерациями (обратная функция к qsfn)
+func phiFromQ(e, q float64) float64 {
+	phi := math.Asin(math.Max(-1, math.Min(1, q/2)))
+	if e < 1e-10 {
+		return phi
+	}
+
+	e2 := e * e
+	for i := 0; i < 25; i++ {
+		sinPhi := math.Sin(phi)
+		cosPhi := math.Cos(phi)
+		if math.Abs(cosPhi) < 1e-12 {
+			return phi
+		}
+		con := e * sinPhi
+		com := 1 - con*con
+		dphi := com * com / (2 * cosPhi) *
+			(q/(1-e2) - sinPhi/com + math.Log((1-con)/(1+con))/(2*e))
+		phi += dphi
+		if math.Abs(dphi) < 1e-12 {
+			break
+		}
+	}
+	return phi
+}
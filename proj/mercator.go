@@ -0,0 +1,53 @@
+package proj
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// MaxWebMercatorLatitude - предельная широта проекции Web Mercator (в градусах)
+const MaxWebMercatorLatitude = 85.05112877980659
+
+// WebMercator - сферическая проекция Меркатора, используемая веб-картами (EPSG:3857)
+type WebMercator struct {
+	// Radius - радиус сферы (в метрах). Ноль означает радиус по умолчанию 6378137 м
+	Radius float64
+}
+
+// NewWebMercator создает проекцию Web Mercator с радиусом сферы по умолчанию
+func NewWebMercator() WebMercator {
+	return WebMercator{Radius: WebMercatorSphere.A}
+}
+
+// radius возвращает радиус сферы проекции
+func (m WebMercator) radius() float64 {
+	if m.Radius == 0 {
+		return WebMercatorSphere.A
+	}
+	return m.Radius
+}
+
+// Forward преобразует географические координаты в метры Web Mercator.
+// Широта ограничивается диапазоном ±MaxWebMercatorLatitude
+func (m WebMercator) Forward(p types.Point) (types.Point, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+
+	lat := math.Max(-MaxWebMercatorLatitude, math.Min(p.GetLatitude(), MaxWebMercatorLatitude))
+	r := m.radius()
+
+	x := r * toRadians(p.GetLongitude())
+	y := r * math.Log(math.Tan(math.Pi/4+toRadians(lat)/2))
+	return types.NewPoint(x, y), nil
+}
+
+// Inverse преобразует метры Web Mercator в географические координаты
+func (m WebMercator) Inverse(p types.Point) (types.Point, error) {
+	r := m.radius()
+
+	lon := normalizeLon(p.GetLongitude() / r)
+	lat := 2*math.Atan(math.Exp(p.GetLatitude()/r)) - math.Pi/2
+	return types.NewPoint(toDegrees(lon), toDegrees(lat)), nil
+}
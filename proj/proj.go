@@ -0,0 +1,145 @@
+// Package proj содержит картографические проекции с прямым и обратным преобразованием координат.
+// Географические координаты задаются точками [долгота, широта] в градусах,
+// проекционные - точками [x, y] в метрах
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// ErrOutOfDomain возвращается, если точку невозможно преобразовать в данной проекции
+var ErrOutOfDomain = errors.New("point is outside of the projection domain")
+
+// Projection определяет интерфейс картографической проекции
+type Projection interface {
+	// Forward преобразует географические координаты в проекционные
+	Forward(p types.Point) (types.Point, error)
+	// Inverse преобразует проекционные координаты в географические
+	Inverse(p types.Point) (types.Point, error)
+}
+
+// PointFunc преобразует одну точку
+type PointFunc func(p types.Point) (types.Point, error)
+
+// ForwardGeometry проецирует все точки геометрии
+func ForwardGeometry(proj Projection, g types.Geometry) (types.Geometry, error) {
+	return TransformGeometry(g, proj.Forward)
+}
+
+// InverseGeometry переводит все точки геометрии из проекционных координат в географические
+func InverseGeometry(proj Projection, g types.Geometry) (types.Geometry, error) {
+	return TransformGeometry(g, proj.Inverse)
+}
+
+// TransformGeometry применяет преобразование fn к каждой точке геометрии и возвращает новую геометрию.
+// Дополнительные измерения точек (например, высота) сохраняются, если fn их не возвращает
+func TransformGeometry(g types.Geometry, fn PointFunc) (types.Geometry, error) {
+	result := types.Geometry{Type: g.Type}
+
+	switch coords := g.Coordinates.(type) {
+	case nil:
+		return result, nil
+	case types.Point:
+		p, err := transformPoint(coords, fn)
+		if err != nil {
+			return types.Geometry{}, err
+		}
+		result.Coordinates = p
+	case types.LineString:
+		ls, err := transformLine(coords, fn)
+		if err != nil {
+			return types.Geometry{}, err
+		}
+		result.Coordinates = ls
+	case types.Polygon:
+		polygon, err := transformPolygon(coords, fn)
+		if err != nil {
+			return types.Geometry{}, err
+		}
+		result.Coordinates = polygon
+	case types.MultiPolygon:
+		multiPolygon := make(types.MultiPolygon, len(coords))
+		for i, polygon := range coords {
+			p, err := transformPolygon(polygon, fn)
+			if err != nil {
+				return types.Geometry{}, fmt.Errorf("error in polygon %d: %w", i, err)
+			}
+			multiPolygon[i] = p
+		}
+		result.Coordinates = multiPolygon
+	default:
+		return types.Geometry{}, fmt.Errorf("unsupported coordinates type %T", coords)
+	}
+
+	return result, nil
+}
+
+// transformPoint применяет преобразование к точке, сохраняя дополнительные измерения
+func transformPoint(p types.Point, fn PointFunc) (types.Point, error) {
+	out, err := fn(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) > len(out) {
+		out = append(out, p[len(out):]...)
+	}
+	return out, nil
+}
+
+// transformLine применяет преобразование ко всем точкам линии
+func transformLine(ls types.LineString, fn PointFunc) (types.LineString, error) {
+	result := make(types.LineString, len(ls))
+	for i, p := range ls {
+		out, err := transformPoint(p, fn)
+		if err != nil {
+			return nil, fmt.Errorf("error at position %d: %w", i, err)
+		}
+		result[i] = out
+	}
+	return result, nil
+}
+
+// transformPolygon применяет преобразование ко всем кольцам полигона
+func transformPolygon(polygon types.Polygon, fn PointFunc) (types.Polygon, error) {
+	result := make(types.Polygon, len(polygon))
+	for i, ring := range polygon {
+		ls, err := transformLine(ring, fn)
+		if err != nil {
+			return nil, fmt.Errorf("error in ring %d: %w", i, err)
+		}
+		result[i] = ls
+	}
+	return result, nil
+}
+
+// toRadians переводит градусы в радианы
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180.0
+}
+
+// toDegrees переводит радианы в градусы
+func toDegrees(rad float64) float64 {
+	return rad * 180.0 / math.Pi
+}
+
+// normalizeLon приводит долготу в радианах к диапазону [-π, π]
+func normalizeLon(lon float64) float64 {
+	if lon >= -math.Pi && lon <= math.Pi {
+		return lon
+	}
+	return math.Mod(lon+3*math.Pi, 2*math.Pi) - math.Pi
+}
+
+// checkGeographic проверяет корректность географических координат
+func checkGeographic(p types.Point) error {
+	lat := p.GetLatitude()
+	lon := p.GetLongitude()
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.IsInf(lon, 0) || lat < -90 || lat > 90 {
+		return fmt.Errorf("invalid geographic coordinates [%v, %v]: %w", lon, lat, ErrOutOfDomain)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package proj
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// clarke1866 - эллипсоид примеров Снайдера (Map Projections - A Working Manual)
+var clarke1866 = Ellipsoid{Name: "Clarke 1866", A: 6378206.4, InvFlattening: 294.9786982}
+
+// checkForward проверяет проекционные координаты точки с допуском tol метров
+func checkForward(t *testing.T, proj Projection, lon, lat, x, y, tol float64) {
+	t.Helper()
+	got, err := proj.Forward(types.NewPoint(lon, lat))
+	if err != nil {
+		t.Fatalf("Forward(%g, %g): %v", lon, lat, err)
+	}
+	if math.Abs(got.GetLongitude()-x) > tol || math.Abs(got.GetLatitude()-y) > tol {
+		t.Errorf("Forward(%g, %g) = %.3f, %.3f, want %.3f, %.3f", lon, lat, got.GetLongitude(), got.GetLatitude(), x, y)
+	}
+}
+
+// checkRoundTrip проверяет, что обратное преобразование случайных точек прямоугольника
+// возвращает исходные координаты с допуском tol градусов
+func checkRoundTrip(t *testing.T, proj Projection, minLon, minLat, maxLon, maxLat, tol float64) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := types.NewPoint(minLon+r.Float64()*(maxLon-minLon), minLat+r.Float64()*(maxLat-minLat))
+		xy, err := proj.Forward(p)
+		if err != nil {
+			t.Fatalf("Forward(%v): %v", p, err)
+		}
+		got, err := proj.Inverse(xy)
+		if err != nil {
+			t.Fatalf("Inverse(%v): %v", xy, err)
+		}
+		if math.Abs(got.GetLongitude()-p.GetLongitude()) > tol || math.Abs(got.GetLatitude()-p.GetLatitude()) > tol {
+			t.Fatalf("round trip of %v gives %v", p, got)
+		}
+	}
+}
+
+func TestUTM(t *testing.T) {
+	utm, err := NewUTM(37, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Эталонные значения вычислены рядами Крюгера шестого порядка
+	checkForward(t, utm, 39, 0, 500000, 0, 1e-6)
+	checkForward(t, utm, 39, 45, 500000, 4982950.400, 0.001)
+	checkForward(t, utm, 37.6173, 55.7558, 413224.138, 6179766.954, 0.005)
+	checkForward(t, utm, 36.5, 60, 360577.912, 6654046.024, 0.005)
+	// Ряды третьего порядка дают расхождение порядка миллиметра, около 1e-8°
+	checkRoundTrip(t, utm, 36, 0, 42, 84, 1e-8)
+
+	south, err := NewUTM(56, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForward(t, south, 153, 0, 500000, UTMFalseNorthingSouth, 1e-6)
+	checkRoundTrip(t, south, 150, -80, 156, 0, 1e-8)
+
+	for _, zone := range []int{0, 61} {
+		if _, err := NewUTM(zone, false); err == nil {
+			t.Errorf("NewUTM(%d) is accepted", zone)
+		}
+	}
+	if _, err := utm.Forward(types.NewPoint(129, 10)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("point 90° from the central meridian: error = %v, want ErrOutOfDomain", err)
+	}
+}
+
+func TestTransverseMercatorSnyder(t *testing.T) {
+	// Пример Снайдера: UTM на эллипсоиде Кларка 1866 без ложного смещения на восток
+	tm := NewTransverseMercator(TransverseMercatorParams{
+		Ellipsoid:       clarke1866,
+		CentralMeridian: -75,
+		ScaleFactor:     UTMScaleFactor,
+	})
+	checkForward(t, tm, -73.5, 40.5, 127106.5, 4484124.4, 0.1)
+}
+
+func TestLambertConformalConic(t *testing.T) {
+	// Пример Снайдера: φ1 = 33°, φ2 = 45°, φ0 = 23°, λ0 = 96° з.д., точка 35° с.ш. 75° з.д.
+	lcc, err := NewLambertConformalConic(ConicParams{
+		Ellipsoid:         clarke1866,
+		CentralMeridian:   -96,
+		LatitudeOfOrigin:  23,
+		StandardParallel1: 33,
+		StandardParallel2: 45,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForward(t, lcc, -75, 35, 1894410.9, 1564649.5, 0.1)
+	checkRoundTrip(t, lcc, -130, 10, -60, 80, 1e-9)
+
+	if _, err := lcc.Forward(types.NewPoint(0, -90)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("pole opposite to the apex: error = %v, want ErrOutOfDomain", err)
+	}
+	if _, err := NewLambertConformalConic(ConicParams{Ellipsoid: WGS84, StandardParallel1: 30, StandardParallel2: -30}); err == nil {
+		t.Error("parallels symmetric about the equator are accepted")
+	}
+}
+
+func TestAlbersEqualArea(t *testing.T) {
+	// Пример Снайдера: φ1 = 29,5°, φ2 = 45,5°, φ0 = 23°, λ0 = 96° з.д., точка 35° с.ш. 75° з.д.
+	aea, err := NewAlbersEqualArea(ConicParams{
+		Ellipsoid:         clarke1866,
+		CentralMeridian:   -96,
+		LatitudeOfOrigin:  23,
+		StandardParallel1: 29.5,
+		StandardParallel2: 45.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForward(t, aea, -75, 35, 1885472.7, 1535925.0, 0.1)
+	checkRoundTrip(t, aea, -130, -60, -60, 89, 1e-9)
+
+	// Вершина конуса лежит за полюсом
+	if _, err := aea.Inverse(types.NewPoint(0, aea.rho0)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("cone apex: error = %v, want ErrOutOfDomain", err)
+	}
+}
+
+func TestLambertAzimuthalEqualArea(t *testing.T) {
+	// Пример EPSG для ETRS89-extended / LAEA Europe (EPSG:3035)
+	laea, err := NewLambertAzimuthalEqualArea(AzimuthalParams{
+		Ellipsoid:     GRS80,
+		CenterLon:     10,
+		CenterLat:     52,
+		FalseEasting:  4321000,
+		FalseNorthing: 3210000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForward(t, laea, 5, 50, 3962799.45, 2999718.85, 0.01)
+	checkForward(t, laea, 10, 52, 4321000, 3210000, 1e-6)
+	checkRoundTrip(t, laea, -30, 25, 60, 85, 1e-9)
+
+	if _, err := laea.Forward(types.NewPoint(-170, -52)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("antipode: error = %v, want ErrOutOfDomain", err)
+	}
+	if _, err := laea.Inverse(types.NewPoint(4321000+3*laea.rq, 3210000)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("beyond the boundary: error = %v, want ErrOutOfDomain", err)
+	}
+
+	// В полярном аспекте противоположный полюс отображается в окружность радиуса a·sqrt(2·qp),
+	// точки дальше нее лежат за полюсом
+	polar, err := NewLambertAzimuthalEqualArea(AzimuthalParams{Ellipsoid: WGS84, CenterLat: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForward(t, polar, 0, 90, 0, 0, 1e-6)
+	checkRoundTrip(t, polar, -180, -80, 180, 90, 1e-9)
+	if _, err := polar.Inverse(types.NewPoint(0, 3*WGS84.A)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("beyond the pole: error = %v, want ErrOutOfDomain", err)
+	}
+}
+
+func TestAzimuthalEquidistant(t *testing.T) {
+	aeqd, err := NewAzimuthalEquidistant(AzimuthalParams{Ellipsoid: WGS84, CenterLon: 37.6173, CenterLat: 55.7558})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Расстояние по меридиану от центра равно длине дуги сферы среднего радиуса
+	checkForward(t, aeqd, 37.6173, 65.7558, 0, WGS84.MeanRadius()*10*math.Pi/180, 1e-6)
+	checkRoundTrip(t, aeqd, -180, -80, 180, 90, 1e-9)
+
+	if _, err := aeqd.Forward(types.NewPoint(37.6173-180, -55.7558)); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("antipode: error = %v, want ErrOutOfDomain", err)
+	}
+	if _, err := aeqd.Inverse(types.NewPoint(0, 4*WGS84.MeanRadius())); !errors.Is(err, ErrOutOfDomain) {
+		t.Errorf("beyond the boundary: error = %v, want ErrOutOfDomain", err)
+	}
+}
+
+func TestWebMercator(t *testing.T) {
+	m := NewWebMercator()
+	const half = 20037508.342789244
+	checkForward(t, m, 180, 0, half, 0, 1e-6)
+	checkForward(t, m, -180, MaxWebMercatorLatitude, -half, half, 1e-3)
+	// Широта за пределами проекции ограничивается
+	checkForward(t, m, 0, 89, 0, half, 1e-3)
+	checkRoundTrip(t, m, -180, -85, 180, 85, 1e-9)
+}
+
+func TestInvalidGeographic(t *testing.T) {
+	utm, _ := NewUTM(37, false)
+	projections := map[string]Projection{"utm": utm, "web mercator": NewWebMercator()}
+	for name, proj := range projections {
+		for _, p := range []types.Point{
+			types.NewPoint(37, 91),
+			types.NewPoint(37, math.NaN()),
+			types.NewPoint(math.Inf(1), 55),
+		} {
+			if _, err := proj.Forward(p); !errors.Is(err, ErrOutOfDomain) {
+				t.Errorf("%s: Forward(%v) error = %v, want ErrOutOfDomain", name, p, err)
+			}
+		}
+	}
+}
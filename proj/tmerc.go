@@ -0,0 +1,251 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Параметры зон UTM
+const (
+	UTMScaleFactor        = 0.9996
+	UTMFalseEasting       = 500000.0
+	UTMFalseNorthingSouth = 10000000.0
+)
+
+// TransverseMercatorParams содержит параметры поперечной проекции Меркатора
+type TransverseMercatorParams struct {
+	Ellipsoid Ellipsoid
+	// CentralMeridian - осевой меридиан (в градусах)
+	CentralMeridian float64
+	// LatitudeOfOrigin - широта начала координат (в градусах)
+	LatitudeOfOrigin float64
+	// ScaleFactor - масштабный коэффициент на осевом меридиане. Ноль означает 1
+	ScaleFactor   float64
+	FalseEasting  float64
+	FalseNorthing float64
+}
+
+// TransverseMercator - поперечная проекция Меркатора на эллипсоиде (рядами Крюгера).
+// Погрешность не превышает нескольких миллиметров в пределах ±4° от осевого меридиана
+type TransverseMercator struct {
+	params TransverseMercatorParams
+	lon0   float64
+	k0     float64
+	// k0A - радиус спрямляющей окружности, умноженный на масштабный коэффициент
+	k0A   float64
+	n     float64
+	alpha [3]float64
+	beta  [3]float64
+	delta [3]float64
+	// northingOrigin - ордината широты начала координат
+	northingOrigin float64
+}
+
+// NewTransverseMercator создает поперечную проекцию Меркатора с указанными параметрами
+func NewTransverseMercator(params TransverseMercatorParams) *TransverseMercator {
+	if params.ScaleFactor == 0 {
+		params.ScaleFactor = 1
+	}
+
+	f := params.Ellipsoid.F()
+	n := f / (2 - f)
+	n2 := n * n
+	n3 := n2 * n
+
+	tm := &TransverseMercator{
+		params: params,
+		lon0:   toRadians(params.CentralMeridian),
+		k0:     params.ScaleFactor,
+		n:      n,
+		alpha: [3]float64{
+			n/2 - 2*n2/3 + 5*n3/16,
+			13*n2/48 - 3*n3/5,
+			61 * n3 / 240,
+		},
+		beta: [3]float64{
+			n/2 - 2*n2/3 + 37*n3/96,
+			n2/48 + n3/15,
+			17 * n3 / 480,
+		},
+		delta: [3]float64{
+			2*n - 2*n2/3 - 2*n3,
+			7*n2/3 - 8*n3/5,
+			56 * n3 / 15,
+		},
+	}
+	tm.k0A = tm.k0 * params.Ellipsoid.A / (1 + n) * (1 + n2/4 + n2*n2/64)
+
+	if params.LatitudeOfOrigin != 0 {
+		_, y := tm.project(toRadians(params.LatitudeOfOrigin), 0)
+		tm.northingOrigin = y
+	}
+
+	return tm
+}
+
+// Params возвращает параметры проекции
+func (tm *TransverseMercator) Params() TransverseMercatorParams {
+	return tm.params
+}
+
+// Forward преобразует географические координаты в проекционные
+func (tm *TransverseMercator) Forward(p types.Point) (types.Point, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+
+	dLon := normalizeLon(toRadians(p.GetLongitude()) - tm.lon0)
+	if math.Abs(dLon) >= math.Pi/2 {
+		return nil, fmt.Errorf("longitude %v is too far from central meridian: %w", p.GetLongitude(), ErrOutOfDomain)
+	}
+
+	x, y := tm.project(toRadians(p.GetLatitude()), dLon)
+	return types.NewPoint(
+		x+tm.params.FalseEasting,
+		y-tm.northingOrigin+tm.params.FalseNorthing,
+	), nil
+}
+
+// project вычисляет координаты без учета ложных смещений
+func (tm *TransverseMercator) project(lat, dLon float64) (x, y float64) {
+	c := 2 * math.Sqrt(tm.n) / (1 + tm.n)
+
+	var t float64
+	if math.Abs(lat) >= math.Pi/2 {
+		t = math.Copysign(math.Inf(1), lat)
+	} else {
+		sinLat := math.Sin(lat)
+		t = math.Sinh(math.Atanh(sinLat) - c*math.Atanh(c*sinLat))
+	}
+
+	xiP := math.Atan2(t, math.Cos(dLon))
+	etaP := math.Atanh(math.Sin(dLon) / math.Sqrt(1+t*t))
+	if math.IsInf(t, 0) {
+		etaP = 0
+	}
+
+	xi := xiP
+	eta := etaP
+	for j := 1; j <= 3; j++ {
+		a := tm.alpha[j-1]
+		xi += a * math.Sin(2*float64(j)*xiP) * math.Cosh(2*float64(j)*etaP)
+		eta += a * math.Cos(2*float64(j)*xiP) * math.Sinh(2*float64(j)*etaP)
+	}
+
+	return tm.k0A * eta, tm.k0A * xi
+}
+
+// Inverse преобразует проекционные координаты в географические
+func (tm *TransverseMercator) Inverse(p types.Point) (types.Point, error) {
+	xi := (p.GetLatitude() - tm.params.FalseNorthing + tm.northingOrigin) / tm.k0A
+	eta := (p.GetLongitude() - tm.params.FalseEasting) / tm.k0A
+
+	xiP := xi
+	etaP := eta
+	for j := 1; j <= 3; j++ {
+		b := tm.beta[j-1]
+		xiP -= b * math.Sin(2*float64(j)*xi) * math.Cosh(2*float64(j)*eta)
+		etaP -= b * math.Cos(2*float64(j)*xi) * math.Sinh(2*float64(j)*eta)
+	}
+
+	chi := math.Asin(math.Max(-1, math.Min(1, math.Sin(xiP)/math.Cosh(etaP))))
+	lat := chi
+	for j := 1; j <= 3; j++ {
+		lat += tm.delta[j-1] * math.Sin(2*float64(j)*chi)
+	}
+
+	lon := normalizeLon(tm.lon0 + math.Atan2(math.Sinh(etaP), math.Cos(xiP)))
+	return types.NewPoint(toDegrees(lon), toDegrees(lat)), nil
+}
+
+// UTMZone возвращает номер зоны UTM для точки с учетом исключений для Норвегии и Шпицбергена
+func UTMZone(p types.Point) int {
+	lon := p.GetLongitude()
+	lat := p.GetLatitude()
+
+	// Приводим долготу к диапазону [-180, 180)
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	lon -= 180
+
+	// Юго-западная Норвегия: зона 32V расширена до 3° в.д.
+	if lat >= 56 && lat < 64 && lon >= 3 && lon < 12 {
+		return 32
+	}
+
+	// Шпицберген: зоны 32X, 34X и 36X не используются
+	if lat >= 72 && lat <= 84 && lon >= 0 && lon < 42 {
+		switch {
+		case lon < 9:
+			return 31
+		case lon < 21:
+			return 33
+		case lon < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+
+	zone := int(math.Floor((lon+180)/6)) + 1
+	return min(max(zone, 1), 60)
+}
+
+// UTMCentralMeridian возвращает осевой меридиан зоны UTM (в градусах)
+func UTMCentralMeridian(zone int) float64 {
+	return float64(zone)*6 - 183
+}
+
+// NewUTM создает проекцию для зоны UTM на эллипсоиде WGS 84.
+// south означает южное полушарие (ложное смещение на север 10 000 км)
+func NewUTM(zone int, south bool) (*TransverseMercator, error) {
+	if zone < 1 || zone > 60 {
+		return nil, fmt.Errorf("invalid UTM zone %d, expected 1..60", zone)
+	}
+
+	params := TransverseMercatorParams{
+		Ellipsoid:       WGS84,
+		CentralMeridian: UTMCentralMeridian(zone),
+		ScaleFactor:     UTMScaleFactor,
+		FalseEasting:    UTMFalseEasting,
+	}
+	if south {
+		params.FalseNorthing = UTMFalseNorthingSouth
+	}
+	return NewTransverseMercator(params), nil
+}
+
+// NewUTMForPoint создает проекцию UTM для зоны и полушария, в которых находится точка
+func NewUTMForPoint(p types.Point) (*TransverseMercator, error) {
+	if err := checkGeographic(p); err != nil {
+		return nil, err
+	}
+	return NewUTM(UTMZone(p), p.GetLatitude() < 0)
+}
+
+// NewUTMForGeometry создает проекцию UTM для зоны, в которой находится центр
+// ограничивающего прямоугольника геометрии
+func NewUTMForGeometry(g types.Geometry) (*TransverseMercator, error) {
+	minLon, minLat := math.Inf(1), math.Inf(1)
+	maxLon, maxLat := math.Inf(-1), math.Inf(-1)
+
+	_, err := TransformGeometry(g, func(p types.Point) (types.Point, error) {
+		minLon = math.Min(minLon, p.GetLongitude())
+		minLat = math.Min(minLat, p.GetLatitude())
+		maxLon = math.Max(maxLon, p.GetLongitude())
+		maxLat = math.Max(maxLat, p.GetLatitude())
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(minLon, 1) {
+		return nil, fmt.Errorf("geometry has no coordinates")
+	}
+
+	return NewUTMForPoint(types.NewPoint((minLon+maxLon)/2, (minLat+maxLat)/2))
+}
@@ -0,0 +1,219 @@
+// Package s2cell реализует иерархическую систему ячеек на гранях куба, аналогичную S2.
+//
+// Сфера проецируется на шесть граней куба, каждая грань делится квадродеревом до уровня MaxLevel.
+// Ячейки нумеруются вдоль кривой Гильберта, поэтому идентификаторы соседних по кривой ячеек
+// близки, а все потомки ячейки занимают непрерывный диапазон [RangeMin, RangeMax].
+// Это позволяет хранить ячейки в индексах и шардировать данные по диапазонам идентификаторов
+package s2cell
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+const (
+	// MaxLevel - уровень листовых ячеек (размер порядка сантиметра)
+	MaxLevel = 30
+	// NumFaces - количество граней куба
+	NumFaces = 6
+
+	// posBits - количество бит позиции на кривой Гильберта, включая завершающий бит
+	posBits = 2*MaxLevel + 1
+	// maxSize - количество листовых ячеек вдоль стороны грани
+	maxSize = 1 << MaxLevel
+
+	swapMask   = 1
+	invertMask = 2
+)
+
+// Таблицы обхода кривой Гильберта: позиция дочерней ячейки по ее координатам (i, j)
+// для каждой из четырех ориентаций и обратное преобразование
+var (
+	ijToPos          = [4][4]int{{0, 1, 3, 2}, {0, 3, 1, 2}, {2, 3, 1, 0}, {2, 1, 3, 0}}
+	posToIJ          = [4][4]int{{0, 1, 3, 2}, {0, 2, 3, 1}, {3, 2, 0, 1}, {3, 1, 0, 2}}
+	posToOrientation = [4]int{swapMask, 0, 0, invertMask | swapMask}
+)
+
+// ErrInvalidCellID возвращается для идентификатора, не соответствующего ячейке
+var ErrInvalidCellID = errors.New("invalid cell id")
+
+// CellID - 64-битный идентификатор ячейки: 3 бита грани, по 2 бита на каждый уровень
+// и завершающий единичный бит, положение которого задает уровень ячейки
+type CellID uint64
+
+// FaceCellID возвращает ячейку нулевого уровня, совпадающую с гранью куба
+func FaceCellID(face int) CellID {
+	return CellID(uint64(face)<<posBits + 1<<(2*MaxLevel))
+}
+
+// PointToCellID возвращает ячейку заданного уровня, содержащую точку
+func PointToCellID(p types.Point, level int) CellID {
+	level = max(0, min(level, MaxLevel))
+	return leafCellID(pointToVector(p)).Parent(level)
+}
+
+// leafCellID возвращает листовую ячейку, содержащую точку сферы
+func leafCellID(p vector) CellID {
+	face, u, v := vectorToFaceUV(p)
+	return fromFaceIJ(face, stToIJ(uvToST(u)), stToIJ(uvToST(v)))
+}
+
+// fromFaceIJ строит идентификатор листовой ячейки по номерам (i, j) на грани
+func fromFaceIJ(face, i, j int) CellID {
+	n := uint64(face) << posBits
+	orientation := face & swapMask
+	for k := MaxLevel - 1; k >= 0; k-- {
+		ij := (i>>k&1)<<1 | j>>k&1
+		pos := ijToPos[orientation][ij]
+		n |= uint64(pos) << (2*k + 1)
+		orientation ^= posToOrientation[pos]
+	}
+	return CellID(n | 1)
+}
+
+// faceIJ возвращает грань и номера (i, j) одной из листовых ячеек внутри ячейки
+func (c CellID) faceIJ() (face, i, j int) {
+	face = c.Face()
+	orientation := face & swapMask
+	for k := MaxLevel - 1; k >= 0; k-- {
+		pos := int(uint64(c)>>(2*k+1)) & 3
+		ij := posToIJ[orientation][pos]
+		i |= (ij >> 1) << k
+		j |= (ij & 1) << k
+		orientation ^= posToOrientation[pos]
+	}
+	return face, i, j
+}
+
+// bounds возвращает грань и диапазон номеров листовых ячеек [i0, i0+size) x [j0, j0+size)
+func (c CellID) bounds() (face, i0, j0, size int) {
+	face, i, j := c.faceIJ()
+	size = 1 << (MaxLevel - c.Level())
+	return face, i &^ (size - 1), j &^ (size - 1), size
+}
+
+// IsValid проверяет корректность идентификатора
+func (c CellID) IsValid() bool {
+	return c.Face() < NumFaces && c.lsb()&0x1555555555555555 != 0
+}
+
+// Face возвращает номер грани куба
+func (c CellID) Face() int {
+	return int(uint64(c) >> posBits)
+}
+
+// Level возвращает уровень ячейки
+func (c CellID) Level() int {
+	return MaxLevel - bits.TrailingZeros64(uint64(c))>>1
+}
+
+// IsLeaf проверяет, что ячейка имеет максимальный уровень
+func (c CellID) IsLeaf() bool {
+	return uint64(c)&1 != 0
+}
+
+// lsb возвращает младший единичный бит идентификатора
+func (c CellID) lsb() uint64 {
+	return uint64(c) & -uint64(c)
+}
+
+// lsbForLevel возвращает младший бит идентификатора ячейки заданного уровня
+func lsbForLevel(level int) uint64 {
+	return 1 << (2 * (MaxLevel - level))
+}
+
+// Parent возвращает родительскую ячейку заданного уровня.
+// Если уровень не меньше уровня ячейки, возвращается сама ячейка
+func (c CellID) Parent(level int) CellID {
+	if level >= c.Level() {
+		return c
+	}
+	lsb := lsbForLevel(max(0, level))
+	return CellID(uint64(c)&-lsb | lsb)
+}
+
+// Children возвращает четыре дочерние ячейки в порядке кривой Гильберта.
+// Для листовой ячейки возвращается нулевой массив
+func (c CellID) Children() [4]CellID {
+	var result [4]CellID
+	if c.IsLeaf() {
+		return result
+	}
+	lsb := c.lsb()
+	child := uint64(c) - lsb + lsb>>2
+	for k := range result {
+		result[k] = CellID(child)
+		child += lsb >> 1
+	}
+	return result
+}
+
+// RangeMin возвращает первую листовую ячейку внутри ячейки
+func (c CellID) RangeMin() CellID {
+	return CellID(uint64(c) - (c.lsb() - 1))
+}
+
+// RangeMax возвращает последнюю листовую ячейку внутри ячейки
+func (c CellID) RangeMax() CellID {
+	return CellID(uint64(c) + (c.lsb() - 1))
+}
+
+// Contains проверяет, что ячейка other совпадает с ячейкой или является ее потомком
+func (c CellID) Contains(other CellID) bool {
+	return other >= c.RangeMin() && other <= c.RangeMax()
+}
+
+// Intersects проверяет, что одна из ячеек содержит другую
+func (c CellID) Intersects(other CellID) bool {
+	return other.RangeMin() <= c.RangeMax() && other.RangeMax() >= c.RangeMin()
+}
+
+// ContainsPoint проверяет, что точка лежит внутри ячейки
+func (c CellID) ContainsPoint(p types.Point) bool {
+	return c.Contains(leafCellID(pointToVector(p)))
+}
+
+// Token возвращает компактное шестнадцатеричное представление идентификатора без завершающих нулей
+func (c CellID) Token() string {
+	if c == 0 {
+		return "X"
+	}
+	s := fmt.Sprintf("%016x", uint64(c))
+	return strings.TrimRight(s, "0")
+}
+
+// ParseToken разбирает представление, полученное методом Token
+func ParseToken(token string) (CellID, error) {
+	if token == "" || len(token) > 16 {
+		return 0, fmt.Errorf("parse token %q: %w", token, ErrInvalidCellID)
+	}
+	v, err := strconv.ParseUint(token+strings.Repeat("0", 16-len(token)), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse token %q: %w", token, ErrInvalidCellID)
+	}
+	c := CellID(v)
+	if !c.IsValid() {
+		return 0, fmt.Errorf("parse token %q: %w", token, ErrInvalidCellID)
+	}
+	return c, nil
+}
+
+// String возвращает запись вида "грань/позиции", например "3/0213"
+func (c CellID) String() string {
+	if !c.IsValid() {
+		return "Invalid: " + strconv.FormatUint(uint64(c), 16)
+	}
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(c.Face()))
+	sb.WriteByte('/')
+	for level := 1; level <= c.Level(); level++ {
+		pos := uint64(c) >> (2*(MaxLevel-level) + 1) & 3
+		sb.WriteByte(byte('0' + pos))
+	}
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+package s2cell
+
+import (
+	"sort"
+)
+
+// RegionCoverer строит покрытие области ячейками разных уровней
+type RegionCoverer struct {
+	// MinLevel - минимальный уровень ячеек покрытия
+	MinLevel int
+	// MaxLevel - максимальный уровень ячеек покрытия
+	MaxLevel int
+	// MaxCells - желаемое максимальное количество ячеек. Может быть превышено,
+	// если область пересекает больше ячеек уровня MinLevel
+	MaxCells int
+}
+
+// NewRegionCoverer создает построитель покрытий с заданными ограничениями
+func NewRegionCoverer(minLevel, maxLevel, maxCells int) *RegionCoverer {
+	return &RegionCoverer{MinLevel: minLevel, MaxLevel: maxLevel, MaxCells: maxCells}
+}
+
+// Covering возвращает отсортированный набор непересекающихся ячеек, покрывающий область.
+// Ячейки дробятся начиная с самых крупных, пока количество ячеек не достигнет MaxCells;
+// ячейки, целиком лежащие внутри области, не дробятся
+func (rc *RegionCoverer) Covering(region Region) []CellID {
+	minLevel := max(0, min(rc.MinLevel, MaxLevel))
+	maxLevel := max(minLevel, min(rc.MaxLevel, MaxLevel))
+	maxCells := max(1, rc.MaxCells)
+
+	// Очередь кандидатов упорядочена по уровню: крупные ячейки обрабатываются первыми
+	var queue []CellID
+	for face := 0; face < NumFaces; face++ {
+		if c := FaceCellID(face); region.IntersectsCell(c) {
+			queue = append(queue, c)
+		}
+	}
+
+	var result []CellID
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+
+		level := c.Level()
+		if level >= minLevel && (level >= maxLevel || region.ContainsCell(c)) {
+			result = append(result, c)
+			continue
+		}
+
+		var children []CellID
+		for _, child := range c.Children() {
+			if region.IntersectsCell(child) {
+				children = append(children, child)
+			}
+		}
+
+		// Дробление превышает лимит ячеек - оставляем ячейку как есть
+		if level >= minLevel && len(result)+len(queue)+len(children) > maxCells {
+			result = append(result, c)
+			continue
+		}
+		queue = append(queue, children...)
+	}
+
+	return normalize(result, minLevel)
+}
+
+// CellIDs возвращает ячейки уровня level, содержащие точки области.
+// Покрытие строится без ограничения количества ячеек
+func CellIDs(region Region, level int) []CellID {
+	rc := &RegionCoverer{MinLevel: level, MaxLevel: level, MaxCells: 1}
+	return rc.Covering(region)
+}
+
+// Normalize сортирует набор ячеек, удаляет повторы и ячейки, содержащиеся в других,
+// и заменяет четверки дочерних ячеек родительской
+func Normalize(cells []CellID) []CellID {
+	return normalize(append([]CellID(nil), cells...), 0)
+}
+
+// normalize выполняет нормализацию, не объединяя ячейки в родителей уровня меньше minLevel
+func normalize(cells []CellID, minLevel int) []CellID {
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+
+	result := make([]CellID, 0, len(cells))
+	for _, c := range cells {
+		// Ячейка уже покрыта предыдущей
+		if n := len(result); n > 0 && result[n-1].Contains(c) {
+			continue
+		}
+		// Удаляем предыдущие ячейки, содержащиеся в текущей
+		for len(result) > 0 && c.Contains(result[len(result)-1]) {
+			result = result[:len(result)-1]
+		}
+
+		// Объединяем четыре последние ячейки, если они являются детьми одного родителя
+		for len(result) >= 3 && c.Level() > minLevel {
+			parent := c.Parent(c.Level() - 1)
+			children := parent.Children()
+			n := len(result)
+			if c != children[3] || result[n-3] != children[0] || result[n-2] != children[1] || result[n-1] != children[2] {
+				break
+			}
+			result = result[:n-3]
+			c = parent
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// Range - непрерывный диапазон листовых ячеек [Min, Max]
+type Range struct {
+	Min CellID
+	Max CellID
+}
+
+// Ranges переводит набор ячеек в отсортированные непересекающиеся диапазоны идентификаторов
+// листовых ячеек. Соседние по кривой Гильберта диапазоны объединяются.
+// Документ, проиндексированный листовой ячейкой, попадает в покрытие, если его
+// идентификатор лежит в одном из диапазонов
+func Ranges(cells []CellID) []Range {
+	sorted := append([]CellID(nil), cells...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RangeMin() < sorted[j].RangeMin() })
+
+	var result []Range
+	for _, c := range sorted {
+		lo, hi := c.RangeMin(), c.RangeMax()
+		if n := len(result); n > 0 && lo <= result[n-1].Max+2 {
+			result[n-1].Max = max(result[n-1].Max, hi)
+			continue
+		}
+		result = append(result, Range{Min: lo, Max: hi})
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package s2cell
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// vector - точка единичной сферы в геоцентрической системе координат
+type vector [3]float64
+
+// pointToVector преобразует географическую точку в единичный вектор
+func pointToVector(p types.Point) vector {
+	lon := p.GetLongitude() * math.Pi / 180
+	lat := p.GetLatitude() * math.Pi / 180
+	cosLat := math.Cos(lat)
+	return vector{cosLat * math.Cos(lon), cosLat * math.Sin(lon), math.Sin(lat)}
+}
+
+// toPoint преобразует вектор в географическую точку
+func (v vector) toPoint() types.Point {
+	lat := math.Atan2(v[2], math.Hypot(v[0], v[1]))
+	lon := math.Atan2(v[1], v[0])
+	return types.NewPoint(lon*180/math.Pi, lat*180/math.Pi)
+}
+
+func (v vector) dot(o vector) float64 {
+	return v[0]*o[0] + v[1]*o[1] + v[2]*o[2]
+}
+
+func (v vector) cross(o vector) vector {
+	return vector{
+		v[1]*o[2] - v[2]*o[1],
+		v[2]*o[0] - v[0]*o[2],
+		v[0]*o[1] - v[1]*o[0],
+	}
+}
+
+func (v vector) normalize() vector {
+	n := math.Sqrt(v.dot(v))
+	if n == 0 {
+		return v
+	}
+	return vector{v[0] / n, v[1] / n, v[2] / n}
+}
+
+// angle возвращает угол между единичными векторами в радианах
+func (v vector) angle(o vector) float64 {
+	return math.Atan2(math.Sqrt(v.cross(o).dot(v.cross(o))), v.dot(o))
+}
+
+// faceUVToVector переводит координаты (u, v) грани куба в вектор (не нормированный)
+func faceUVToVector(face int, u, v float64) vector {
+	switch face {
+	case 0:
+		return vector{1, u, v}
+	case 1:
+		return vector{-u, 1, v}
+	case 2:
+		return vector{-u, -v, 1}
+	case 3:
+		return vector{-1, -v, -u}
+	case 4:
+		return vector{v, -1, -u}
+	default:
+		return vector{v, u, -1}
+	}
+}
+
+// vectorToFaceUV находит грань куба, на которую проецируется вектор, и координаты (u, v) на ней
+func vectorToFaceUV(p vector) (face int, u, v float64) {
+	face = 0
+	if math.Abs(p[1]) > math.Abs(p[face]) {
+		face = 1
+	}
+	if math.Abs(p[2]) > math.Abs(p[face]) {
+		face = 2
+	}
+	if p[face] < 0 {
+		face += 3
+	}
+
+	switch face {
+	case 0:
+		u, v = p[1]/p[0], p[2]/p[0]
+	case 1:
+		u, v = -p[0]/p[1], p[2]/p[1]
+	case 2:
+		u, v = -p[0]/p[2], -p[1]/p[2]
+	case 3:
+		u, v = p[2]/p[0], p[1]/p[0]
+	case 4:
+		u, v = p[2]/p[1], -p[0]/p[1]
+	default:
+		u, v = -p[1]/p[2], -p[0]/p[2]
+	}
+	return face, u, v
+}
+
+// stToUV выполняет квадратичное преобразование, выравнивающее площади ячеек
+func stToUV(s float64) float64 {
+	if s >= 0.5 {
+		return (1.0 / 3.0) * (4*s*s - 1)
+	}
+	return (1.0 / 3.0) * (1 - 4*(1-s)*(1-s))
+}
+
+// uvToST - обратное к stToUV преобразование
+func uvToST(u float64) float64 {
+	if u >= 0 {
+		return 0.5 * math.Sqrt(1+3*u)
+	}
+	return 1 - 0.5*math.Sqrt(1-3*u)
+}
+
+// stToIJ переводит координату s ∈ [0, 1] в номер листовой ячейки
+func stToIJ(s float64) int {
+	return max(0, min(maxSize-1, int(math.Floor(maxSize*s))))
+}
+
+// ijToUV возвращает координату u границы листовой ячейки с номером i
+func ijToUV(i int) float64 {
+	return stToUV(float64(i) / maxSize)
+}
@@ -0,0 +1,184 @@
+package s2cell
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+const (
+	// poleLatitude - широта, начиная с которой вершина границы считается полюсом
+	poleLatitude = 90 - 1e-9
+	// edgeStep - наибольшее угловое расстояние между соседними точками уплотненной границы в градусах
+	edgeStep = 0.25
+)
+
+// Center возвращает центр ячейки
+func (c CellID) Center() types.Point {
+	face, i0, j0, size := c.bounds()
+	u := stToUV((float64(i0) + float64(size)/2) / maxSize)
+	v := stToUV((float64(j0) + float64(size)/2) / maxSize)
+	return faceUVToVector(face, u, v).normalize().toPoint()
+}
+
+// Vertices возвращает четыре вершины ячейки против часовой стрелки
+func (c CellID) Vertices() [4]types.Point {
+	var result [4]types.Point
+	for k, v := range c.vertexVectors() {
+		result[k] = v.toPoint()
+	}
+	return result
+}
+
+// vertexVectors возвращает вершины ячейки в виде единичных векторов
+func (c CellID) vertexVectors() [4]vector {
+	face, i0, j0, size := c.bounds()
+	u0, u1 := ijToUV(i0), ijToUV(i0+size)
+	v0, v1 := ijToUV(j0), ijToUV(j0+size)
+	return [4]vector{
+		faceUVToVector(face, u0, v0).normalize(),
+		faceUVToVector(face, u1, v0).normalize(),
+		faceUVToVector(face, u1, v1).normalize(),
+		faceUVToVector(face, u0, v1).normalize(),
+	}
+}
+
+// edgeVectors возвращает точки границы ячейки, уплотненные так, чтобы соседние точки
+// отстояли не более чем на edgeStep. Стороны ячейки - дуги больших кругов
+func (c CellID) edgeVectors() []vector {
+	face, i0, j0, size := c.bounds()
+	u0, u1 := ijToUV(i0), ijToUV(i0+size)
+	v0, v1 := ijToUV(j0), ijToUV(j0+size)
+	corners := [5][2]float64{{u0, v0}, {u1, v0}, {u1, v1}, {u0, v1}, {u0, v0}}
+
+	var result []vector
+	for k := 0; k < 4; k++ {
+		a := faceUVToVector(face, corners[k][0], corners[k][1]).normalize()
+		b := faceUVToVector(face, corners[k+1][0], corners[k+1][1]).normalize()
+		steps := max(1, int(math.Ceil(a.angle(b)*180/math.Pi/edgeStep)))
+		for s := 0; s < steps; s++ {
+			t := float64(s) / float64(steps)
+			u := corners[k][0] + t*(corners[k+1][0]-corners[k][0])
+			v := corners[k][1] + t*(corners[k+1][1]-corners[k][1])
+			result = append(result, faceUVToVector(face, u, v).normalize())
+		}
+	}
+	return result
+}
+
+// Boundary возвращает границу ячейки в виде полигона на плоскости долгота/широта.
+// Стороны ячейки уплотняются промежуточными точками. Долготы не нормализуются,
+// чтобы контур оставался непрерывным у 180-го меридиана; для ячеек, содержащих полюс
+// или касающихся его вершиной, контур проходит по линии полюса
+func (c CellID) Boundary() types.Polygon {
+	return types.NewPolygon(c.lonLatRing())
+}
+
+// lonLatRing строит замкнутый контур ячейки на плоскости долгота/широта
+func (c CellID) lonLatRing() types.LineString {
+	vectors := c.edgeVectors()
+	points := make([]types.Point, len(vectors))
+	start := -1
+	for k, v := range vectors {
+		points[k] = v.toPoint()
+		if start < 0 && math.Abs(points[k].GetLatitude()) < poleLatitude {
+			start = k
+		}
+	}
+
+	ring := make(types.LineString, 0, len(points)+4)
+	prevLon := points[start].GetLongitude()
+	pendingPole := 0.0
+	for n := 0; n <= len(points); n++ {
+		p := points[(start+n)%len(points)]
+		lat := p.GetLatitude()
+
+		// Полюс в вершине: контур идет по линии полюса от долготы предыдущей точки до следующей
+		if math.Abs(lat) >= poleLatitude {
+			pendingPole = math.Copysign(90, lat)
+			ring = append(ring, types.NewPoint(prevLon, pendingPole))
+			continue
+		}
+
+		lon := unwrapLongitude(p.GetLongitude(), prevLon)
+		if pendingPole != 0 {
+			ring = append(ring, types.NewPoint(lon, pendingPole))
+			pendingPole = 0
+		}
+		ring = append(ring, types.NewPoint(lon, lat))
+		prevLon = lon
+	}
+
+	// Полюс внутри ячейки: контур обходит полный круг долгот и замыкается через полюс
+	first := ring[0].GetLongitude()
+	if math.Abs(prevLon-first) > 180 {
+		pole := math.Copysign(90, c.Center().GetLatitude())
+		ring = append(ring,
+			types.NewPoint(prevLon, pole),
+			types.NewPoint(first, pole),
+			types.NewPoint(first, ring[0].GetLatitude()),
+		)
+	}
+	return ring
+}
+
+// unwrapLongitude сдвигает долготу на кратное 360° так, чтобы она отличалась от reference не более чем на 180°
+func unwrapLongitude(lon, reference float64) float64 {
+	for lon-reference > 180 {
+		lon -= 360
+	}
+	for lon-reference < -180 {
+		lon += 360
+	}
+	return lon
+}
+
+// EdgeNeighbors возвращает четыре ячейки того же уровня, имеющие общую сторону с ячейкой:
+// снизу, справа, сверху и слева в координатах грани
+func (c CellID) EdgeNeighbors() [4]CellID {
+	level := c.Level()
+	face, i, j, size := c.bounds()
+	return [4]CellID{
+		fromFaceIJWrap(face, i, j-size).Parent(level),
+		fromFaceIJWrap(face, i+size, j).Parent(level),
+		fromFaceIJWrap(face, i, j+size).Parent(level),
+		fromFaceIJWrap(face, i-size, j).Parent(level),
+	}
+}
+
+// AllNeighbors возвращает все ячейки того же уровня, имеющие с ячейкой общую сторону или вершину.
+// У вершин куба соседей семь, в остальных случаях восемь
+func (c CellID) AllNeighbors() []CellID {
+	level := c.Level()
+	face, i, j, size := c.bounds()
+
+	seen := map[CellID]struct{}{c: {}}
+	var result []CellID
+	for _, d := range [8][2]int{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}} {
+		n := fromFaceIJWrap(face, i+d[0]*size, j+d[1]*size).Parent(level)
+		if _, ok := seen[n]; !ok {
+			seen[n] = struct{}{}
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// fromFaceIJWrap возвращает листовую ячейку по номерам (i, j), которые могут выходить
+// за пределы грани. Такие ячейки проецируются на соседнюю грань
+func fromFaceIJWrap(face, i, j int) CellID {
+	if i >= 0 && i < maxSize && j >= 0 && j < maxSize {
+		return fromFaceIJ(face, i, j)
+	}
+
+	// Центр листовой ячейки сразу за границей грани; вблизи границы преобразование st → uv
+	// можно считать линейным
+	i = max(-1, min(maxSize, i))
+	j = max(-1, min(maxSize, j))
+	limit := math.Nextafter(1, 2)
+	u := math.Max(-limit, math.Min(limit, float64(2*i+1-maxSize)/maxSize))
+	v := math.Max(-limit, math.Min(limit, float64(2*j+1-maxSize)/maxSize))
+
+	face, u, v = vectorToFaceUV(faceUVToVector(face, u, v))
+	return fromFaceIJ(face, stToIJ(0.5*(u+1)), stToIJ(0.5*(v+1)))
+}
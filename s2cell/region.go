@@ -0,0 +1,146 @@
+package s2cell
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// Region - область на сфере, для которой строится покрытие ячейками
+type Region interface {
+	// ContainsCell проверяет, что ячейка целиком лежит внутри области
+	ContainsCell(c CellID) bool
+	// IntersectsCell проверяет, что ячейка имеет с областью общие точки
+	IntersectsCell(c CellID) bool
+}
+
+// PolygonRegion - область, заданная полигоном или набором полигонов.
+// Проверки выполняются на плоскости долгота/широта, как и в пакете calc
+type PolygonRegion struct {
+	prepared *calc.PreparedPolygon
+}
+
+// NewPolygonRegion создает область по полигону
+func NewPolygonRegion(polygon types.Polygon) *PolygonRegion {
+	return &PolygonRegion{prepared: calc.NewPreparedPolygon(polygon)}
+}
+
+// NewMultiPolygonRegion создает область по набору полигонов
+func NewMultiPolygonRegion(multiPolygon types.MultiPolygon) *PolygonRegion {
+	return &PolygonRegion{prepared: calc.NewPreparedMultiPolygon(multiPolygon)}
+}
+
+// ContainsCell проверяет, что ячейка целиком лежит внутри области
+func (r *PolygonRegion) ContainsCell(c CellID) bool {
+	for _, polygon := range cellPolygons(c) {
+		if r.prepared.ContainsPolygon(polygon) {
+			return true
+		}
+	}
+	return false
+}
+
+// IntersectsCell проверяет, что ячейка имеет с областью общие точки
+func (r *PolygonRegion) IntersectsCell(c CellID) bool {
+	for _, polygon := range cellPolygons(c) {
+		if r.prepared.IntersectsPolygon(polygon) {
+			return true
+		}
+	}
+	return false
+}
+
+// cellPolygons возвращает контур ячейки и его копии, сдвинутые на 360°,
+// если контур выходит за пределы [-180, 180]
+func cellPolygons(c CellID) []types.Polygon {
+	ring := c.lonLatRing()
+	minLon, maxLon := math.Inf(1), math.Inf(-1)
+	for _, p := range ring {
+		minLon = math.Min(minLon, p.GetLongitude())
+		maxLon = math.Max(maxLon, p.GetLongitude())
+	}
+
+	result := []types.Polygon{types.NewPolygon(ring)}
+	if minLon < -180 {
+		result = append(result, types.NewPolygon(shiftRing(ring, 360)))
+	}
+	if maxLon > 180 {
+		result = append(result, types.NewPolygon(shiftRing(ring, -360)))
+	}
+	return result
+}
+
+// shiftRing сдвигает контур по долготе
+func shiftRing(ring types.LineString, dLon float64) types.LineString {
+	result := make(types.LineString, len(ring))
+	for k, p := range ring {
+		result[k] = types.NewPoint(p.GetLongitude()+dLon, p.GetLatitude())
+	}
+	return result
+}
+
+// Cap - сферическая шапка: круг заданного радиуса вокруг точки
+type Cap struct {
+	center vector
+	angle  float64
+}
+
+// NewCap создает шапку с центром в точке и радиусом в километрах
+func NewCap(center types.Point, radiusKm float64) *Cap {
+	return &Cap{
+		center: pointToVector(center),
+		angle:  math.Max(0, radiusKm) / calc.EarthRadiusKm,
+	}
+}
+
+// ContainsPoint проверяет, что точка лежит внутри шапки
+func (cp *Cap) ContainsPoint(p types.Point) bool {
+	return cp.center.angle(pointToVector(p)) <= cp.angle
+}
+
+// ContainsCell проверяет, что ячейка целиком лежит внутри шапки
+func (cp *Cap) ContainsCell(c CellID) bool {
+	// Для шапок больше полусферы проверяются промежуточные точки сторон,
+	// для меньших шапок достаточно вершин
+	vertices := c.vertexVectors()
+	points := vertices[:]
+	if cp.angle > math.Pi/2 {
+		points = c.edgeVectors()
+	}
+	for _, p := range points {
+		if cp.center.angle(p) > cp.angle {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsCell проверяет, что ячейка имеет с шапкой общие точки
+func (cp *Cap) IntersectsCell(c CellID) bool {
+	if c.Contains(leafCellID(cp.center)) {
+		return true
+	}
+
+	vertices := c.vertexVectors()
+	for k := range vertices {
+		a, b := vertices[k], vertices[(k+1)%4]
+		if cp.center.angle(a) <= cp.angle || edgeDistance(cp.center, a, b) <= cp.angle {
+			return true
+		}
+	}
+	return false
+}
+
+// edgeDistance вычисляет угловое расстояние от точки p до дуги большого круга ab
+func edgeDistance(p, a, b vector) float64 {
+	n := a.cross(b).normalize()
+	d := p.dot(n)
+
+	// Проекция точки на большой круг лежит внутри дуги
+	q := vector{p[0] - d*n[0], p[1] - d*n[1], p[2] - d*n[2]}.normalize()
+	if a.cross(q).dot(n) >= 0 && q.cross(b).dot(n) >= 0 {
+		return math.Asin(math.Min(1, math.Abs(d)))
+	}
+	return math.Min(p.angle(a), p.angle(b))
+}
@@ -0,0 +1,179 @@
+package s2cell
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// randomPoint возвращает случайную точку, равномерно распределенную по сфере
+func randomPoint(r *rand.Rand) types.Point {
+	lat := math.Asin(2*r.Float64()-1) * 180 / math.Pi
+	return types.NewPoint(-180+r.Float64()*360, lat)
+}
+
+// covered проверяет, что точка лежит в одной из ячеек
+func covered(cells []CellID, p types.Point) bool {
+	for _, c := range cells {
+		if c.ContainsPoint(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPointToCellID(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(r)
+		leaf := PointToCellID(p, MaxLevel)
+		if !leaf.IsValid() || !leaf.IsLeaf() {
+			t.Fatalf("PointToCellID(%v) = %s, want valid leaf", p, leaf)
+		}
+
+		for _, level := range []int{0, 5, 12, 20} {
+			c := PointToCellID(p, level)
+			if c.Level() != level || !c.ContainsPoint(p) {
+				t.Fatalf("cell %s at level %d does not contain %v", c, level, p)
+			}
+			if c != leaf.Parent(level) || !c.Contains(leaf) {
+				t.Fatalf("cell %s is not the parent of leaf %s", c, leaf)
+			}
+			if got := PointToCellID(c.Center(), level); got != c {
+				t.Fatalf("centre of %s maps to %s", c, got)
+			}
+
+			token := c.Token()
+			parsed, err := ParseToken(token)
+			if err != nil || parsed != c {
+				t.Fatalf("ParseToken(%q) = %s, %v, want %s", token, parsed, err, c)
+			}
+		}
+	}
+}
+
+func TestParseTokenInvalid(t *testing.T) {
+	for _, token := range []string{"", "X", "zz", "0", "12345678901234567"} {
+		if _, err := ParseToken(token); !errors.Is(err, ErrInvalidCellID) {
+			t.Errorf("ParseToken(%q) error = %v, want ErrInvalidCellID", token, err)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	c := PointToCellID(types.NewPoint(37.6173, 55.7558), 10)
+	children := c.Children()
+	for k, child := range children {
+		if child.Level() != 11 || child.Parent(10) != c || !c.Contains(child) {
+			t.Fatalf("child %s is not a child of %s", child, c)
+		}
+		if k > 0 && child.RangeMin() != children[k-1].RangeMax()+2 {
+			t.Errorf("children ranges of %s are not contiguous", c)
+		}
+	}
+	if children[0].RangeMin() != c.RangeMin() || children[3].RangeMax() != c.RangeMax() {
+		t.Errorf("children do not span the range of %s", c)
+	}
+	if leaf := c.RangeMin(); leaf.Children() != [4]CellID{} {
+		t.Errorf("leaf %s has children", leaf)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 300; i++ {
+		c := PointToCellID(randomPoint(r), 1+r.Intn(20))
+		edges := c.EdgeNeighbors()
+		for _, n := range edges {
+			if n.Level() != c.Level() || n == c {
+				t.Fatalf("edge neighbour %s of %s", n, c)
+			}
+			if back := n.EdgeNeighbors(); !slices.Contains(back[:], c) {
+				t.Fatalf("%s is not an edge neighbour of its neighbour %s", c, n)
+			}
+		}
+
+		all := c.AllNeighbors()
+		if len(all) != 7 && len(all) != 8 {
+			t.Fatalf("%s has %d neighbours", c, len(all))
+		}
+		for _, n := range edges {
+			if !slices.Contains(all, n) {
+				t.Fatalf("AllNeighbors of %s misses edge neighbour %s", c, n)
+			}
+		}
+	}
+}
+
+func TestCapCovering(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 20; i++ {
+		center := randomPoint(r)
+		region := NewCap(center, 10+r.Float64()*2000)
+		cells := NewRegionCoverer(0, 16, 8).Covering(region)
+		if len(cells) == 0 || len(cells) > 8 {
+			t.Fatalf("covering of cap around %v has %d cells", center, len(cells))
+		}
+		if !slices.IsSorted(cells) {
+			t.Fatalf("covering is not sorted: %v", cells)
+		}
+
+		for j := 0; j < 200; j++ {
+			p := randomPoint(r)
+			if region.ContainsPoint(p) && !covered(cells, p) {
+				t.Fatalf("point %v of cap around %v is not covered", p, center)
+			}
+		}
+		if !covered(cells, center) {
+			t.Fatalf("cap centre %v is not covered", center)
+		}
+	}
+}
+
+func TestPolygonCovering(t *testing.T) {
+	// Прямоугольник по обе стороны от 180-го меридиана
+	multiPolygon := types.MultiPolygon{
+		types.NewPolygon(types.NewLineString(
+			types.NewPoint(179, -10), types.NewPoint(180, -10), types.NewPoint(180, 10),
+			types.NewPoint(179, 10), types.NewPoint(179, -10),
+		)),
+		types.NewPolygon(types.NewLineString(
+			types.NewPoint(-180, -10), types.NewPoint(-179, -10), types.NewPoint(-179, 10),
+			types.NewPoint(-180, 10), types.NewPoint(-180, -10),
+		)),
+	}
+	region := NewMultiPolygonRegion(multiPolygon)
+
+	for _, cells := range [][]CellID{
+		NewRegionCoverer(2, 12, 20).Covering(region),
+		CellIDs(region, 6),
+	} {
+		for _, c := range cells {
+			if !region.IntersectsCell(c) {
+				t.Fatalf("cell %s does not intersect the polygon", c)
+			}
+		}
+
+		r := rand.New(rand.NewSource(4))
+		for j := 0; j < 500; j++ {
+			lon := 179 + r.Float64()*2
+			if lon > 180 {
+				lon -= 360
+			}
+			p := types.NewPoint(lon, -10+r.Float64()*20)
+			if !covered(cells, p) {
+				t.Fatalf("point %v of the polygon is not covered", p)
+			}
+		}
+	}
+
+	for _, c := range CellIDs(region, 6) {
+		if c.Level() != 6 {
+			t.Fatalf("CellIDs returned cell %s at level %d", c, c.Level())
+		}
+	}
+}
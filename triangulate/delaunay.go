@@ -0,0 +1,392 @@
+// Package triangulate реализует триангуляцию Делоне и диаграммы Вороного для наборов точек
+// на плоскости и на сфере, а также разбиение полигонов на треугольники.
+//
+// Плоские алгоритмы работают в локальной равнопромежуточной системе координат: долгота
+// умножается на косинус средней широты, поэтому ячейки Вороного небольших областей
+// соответствуют ближайшей точке на местности. Для областей масштаба континента
+// используются сферические варианты, строящие триангуляцию как выпуклую оболочку
+// точек единичной сферы
+package triangulate
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// ErrDegenerate возвращается, если по набору точек нельзя построить результат:
+// точек слишком мало или все они лежат на одной прямой (окружности на сфере)
+var ErrDegenerate = errors.New("degenerate point set")
+
+// Triangle - треугольник триангуляции: индексы вершин во входном наборе точек
+// в порядке обхода против часовой стрелки
+type Triangle [3]int
+
+// Triangulation - триангуляция набора точек
+type Triangulation struct {
+	// Points - исходный набор точек
+	Points []types.Point
+	// Triangles - треугольники триангуляции
+	Triangles []Triangle
+}
+
+// MultiPolygon возвращает треугольники в виде полигонов
+func (t *Triangulation) MultiPolygon() types.MultiPolygon {
+	polygons := make(types.MultiPolygon, 0, len(t.Triangles))
+	for _, tri := range t.Triangles {
+		a, b, c := t.Points[tri[0]], t.Points[tri[1]], t.Points[tri[2]]
+		polygons = append(polygons, types.NewPolygon(types.NewLineString(a, b, c, a)))
+	}
+	return polygons
+}
+
+// Edges возвращает ребра триангуляции в виде пар индексов точек, меньший индекс первый.
+// Каждое ребро возвращается один раз
+func (t *Triangulation) Edges() [][2]int {
+	return triangleEdges(t.Triangles)
+}
+
+// Neighbors возвращает для каждой точки индексы точек, соединенных с ней ребром
+func (t *Triangulation) Neighbors() [][]int {
+	return vertexNeighbors(len(t.Points), t.Triangles)
+}
+
+// triangleEdges возвращает ребра треугольников без повторов
+func triangleEdges(triangles []Triangle) [][2]int {
+	seen := make(map[[2]int]bool, len(triangles)*3/2)
+	edges := make([][2]int, 0, len(triangles)*3/2)
+	for _, tri := range triangles {
+		for k := 0; k < 3; k++ {
+			a, b := tri[k], tri[(k+1)%3]
+			e := [2]int{min(a, b), max(a, b)}
+			if !seen[e] {
+				seen[e] = true
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// vertexNeighbors возвращает для каждой из n вершин индексы вершин, соединенных с ней ребром
+func vertexNeighbors(n int, triangles []Triangle) [][]int {
+	neighbors := make([][]int, n)
+	for _, e := range triangleEdges(triangles) {
+		neighbors[e[0]] = append(neighbors[e[0]], e[1])
+		neighbors[e[1]] = append(neighbors[e[1]], e[0])
+	}
+	return neighbors
+}
+
+// localFrame - локальная равнопромежуточная система координат с центром (lon0, lat0)
+type localFrame struct {
+	lon0, lat0 float64
+	cosLat     float64
+}
+
+// newLocalFrame создает систему координат с центром в середине ограничивающего прямоугольника точек
+func newLocalFrame(points []types.Point) localFrame {
+	minLon, minLat := math.Inf(1), math.Inf(1)
+	maxLon, maxLat := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minLon, maxLon = math.Min(minLon, p.GetLongitude()), math.Max(maxLon, p.GetLongitude())
+		minLat, maxLat = math.Min(minLat, p.GetLatitude()), math.Max(maxLat, p.GetLatitude())
+	}
+	if len(points) == 0 {
+		return localFrame{cosLat: 1}
+	}
+	lat0 := (minLat + maxLat) / 2
+	return localFrame{lon0: (minLon + maxLon) / 2, lat0: lat0, cosLat: math.Max(math.Cos(lat0*math.Pi/180), 1e-6)}
+}
+
+// forward переводит точку в локальные координаты
+func (f localFrame) forward(p types.Point) [2]float64 {
+	return [2]float64{(p.GetLongitude() - f.lon0) * f.cosLat, p.GetLatitude() - f.lat0}
+}
+
+// inverse переводит локальные координаты в точку
+func (f localFrame) inverse(xy [2]float64) types.Point {
+	return types.NewPoint(xy[0]/f.cosLat+f.lon0, xy[1]+f.lat0)
+}
+
+// orient возвращает удвоенную ориентированную площадь треугольника abc:
+// положительную, если c лежит слева от направления ab
+func orient(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// inCircle возвращает положительное значение, если d лежит внутри окружности,
+// описанной около треугольника abc, обходимого против часовой стрелки
+func inCircle(a, b, c, d [2]float64) float64 {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+	ad := adx*adx + ady*ady
+	bd := bdx*bdx + bdy*bdy
+	cd := cdx*cdx + cdy*cdy
+	return adx*(bdy*cd-bd*cdy) - ady*(bdx*cd-bd*cdx) + ad*(bdx*cdy-bdy*cdx)
+}
+
+// infinite - индекс бесконечно удаленной вершины. Треугольник (a, b, infinite) прилегает
+// снаружи к ребру выпуклой оболочки ab, внешняя область лежит слева от направления ab
+const infinite = -1
+
+// dtTriangle - треугольник строящейся триангуляции
+type dtTriangle struct {
+	v [3]int
+	// n - индексы соседних треугольников: n[k] лежит напротив вершины v[k]
+	n    [3]int
+	dead bool
+}
+
+// bowyerWatson строит триангуляцию Делоне алгоритмом Боуэра-Уотсона с бесконечной вершиной.
+// Совпадающие точки должны быть исключены заранее
+type bowyerWatson struct {
+	pts  [][2]float64
+	tris []dtTriangle
+	// last - недавно созданный конечный треугольник, с которого начинается поиск
+	last int
+}
+
+// hasInfinite проверяет, что треугольник прилегает к бесконечной вершине
+func (t *dtTriangle) hasInfinite() bool {
+	return t.v[0] == infinite || t.v[1] == infinite || t.v[2] == infinite
+}
+
+// collinearEps - относительный допуск, в пределах которого три точки считаются лежащими на одной прямой
+const collinearEps = 1e-12
+
+// delaunay строит триангуляцию Делоне точек pts без совпадающих точек.
+// Возвращает треугольники с индексами pts или nil, если все точки лежат на одной прямой
+func delaunay(pts [][2]float64) []Triangle {
+	if len(pts) < 3 {
+		return nil
+	}
+
+	// Точки вставляются в порядке возрастания координат, чтобы поиск треугольника
+	// начинался рядом с предыдущей точкой
+	order := make([]int, len(pts))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		if c := cmpFloat(pts[a][0], pts[b][0]); c != 0 {
+			return c
+		}
+		return cmpFloat(pts[a][1], pts[b][1])
+	})
+
+	// Начальный треугольник - первые две точки и первая точка не на их прямой.
+	// Перевод в локальные координаты вносит погрешность округления, поэтому точки,
+	// отклоняющиеся от прямой на уровне этой погрешности, считаются лежащими на ней
+	a, b := order[0], order[1]
+	third := -1
+	for k := 2; k < len(order); k++ {
+		c := pts[order[k]]
+		scale := math.Hypot(pts[b][0]-pts[a][0], pts[b][1]-pts[a][1]) * math.Hypot(c[0]-pts[a][0], c[1]-pts[a][1])
+		if math.Abs(orient(pts[a], pts[b], c)) > collinearEps*scale {
+			third = k
+			break
+		}
+	}
+	if third < 0 {
+		return nil
+	}
+	c := order[third]
+	if orient(pts[a], pts[b], pts[c]) < 0 {
+		a, b = b, a
+	}
+
+	bw := &bowyerWatson{pts: pts}
+	bw.tris = []dtTriangle{
+		{v: [3]int{a, b, c}, n: [3]int{2, 3, 1}},
+		{v: [3]int{b, a, infinite}, n: [3]int{3, 2, 0}},
+		{v: [3]int{c, b, infinite}, n: [3]int{1, 3, 0}},
+		{v: [3]int{a, c, infinite}, n: [3]int{2, 1, 0}},
+	}
+
+	for k, i := range order {
+		if k < 2 || k == third {
+			continue
+		}
+		bw.insert(i)
+	}
+
+	var result []Triangle
+	for _, t := range bw.tris {
+		if !t.dead && !t.hasInfinite() {
+			result = append(result, Triangle(t.v))
+		}
+	}
+	return result
+}
+
+// cmpFloat сравнивает числа для сортировки
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// conflicts проверяет, что точка p лежит внутри окружности, описанной около треугольника.
+// Для треугольника с бесконечной вершиной окружность вырождается в полуплоскость снаружи
+// ребра оболочки вместе с самим ребром
+func (bw *bowyerWatson) conflicts(t *dtTriangle, p [2]float64) bool {
+	if !t.hasInfinite() {
+		return inCircle(bw.pts[t.v[0]], bw.pts[t.v[1]], bw.pts[t.v[2]], p) > 0
+	}
+
+	// Поворачиваем вершины так, чтобы бесконечная была последней
+	v := t.v
+	for v[2] != infinite {
+		v = [3]int{v[2], v[0], v[1]}
+	}
+	a, b := bw.pts[v[0]], bw.pts[v[1]]
+	o := orient(a, b, p)
+	if o != 0 {
+		return o > 0
+	}
+	// Точка на прямой ребра оболочки конфликтует, только если лежит на самом ребре
+	dot := (p[0]-a[0])*(b[0]-a[0]) + (p[1]-a[1])*(b[1]-a[1])
+	return dot > 0 && dot < (b[0]-a[0])*(b[0]-a[0])+(b[1]-a[1])*(b[1]-a[1])
+}
+
+// locate возвращает треугольник, конфликтующий с точкой p: обходом от последнего
+// треугольника к точке, а если обход не удался - перебором
+func (bw *bowyerWatson) locate(p [2]float64) int {
+	t := bw.last
+	for steps := 0; steps < len(bw.tris); steps++ {
+		tri := &bw.tris[t]
+		if tri.hasInfinite() {
+			break
+		}
+		next := -1
+		for k := 0; k < 3; k++ {
+			a, b := bw.pts[tri.v[(k+1)%3]], bw.pts[tri.v[(k+2)%3]]
+			if orient(a, b, p) < 0 {
+				next = tri.n[k]
+				break
+			}
+		}
+		if next < 0 {
+			return t
+		}
+		t = next
+	}
+	if bw.conflicts(&bw.tris[t], p) {
+		return t
+	}
+
+	for i := range bw.tris {
+		if !bw.tris[i].dead && bw.conflicts(&bw.tris[i], p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insert добавляет точку i: удаляет треугольники, конфликтующие с ней, и соединяет
+// границу образовавшейся полости с новой точкой
+func (bw *bowyerWatson) insert(i int) {
+	p := bw.pts[i]
+	start := bw.locate(p)
+	if start < 0 {
+		return
+	}
+
+	// Полость - связная область конфликтующих треугольников
+	cavity := []int{start}
+	inCavity := map[int]bool{start: true}
+	for k := 0; k < len(cavity); k++ {
+		for _, n := range bw.tris[cavity[k]].n {
+			if !inCavity[n] && bw.conflicts(&bw.tris[n], p) {
+				inCavity[n] = true
+				cavity = append(cavity, n)
+			}
+		}
+	}
+
+	// Ребра границы полости в порядке обхода треугольников полости
+	type boundaryEdge struct {
+		u, v  int
+		outer int
+	}
+	var edges []boundaryEdge
+	for _, t := range cavity {
+		tri := &bw.tris[t]
+		tri.dead = true
+		for k := 0; k < 3; k++ {
+			if !inCavity[tri.n[k]] {
+				edges = append(edges, boundaryEdge{u: tri.v[(k+1)%3], v: tri.v[(k+2)%3], outer: tri.n[k]})
+			}
+		}
+	}
+
+	byStart := make(map[int]int, len(edges))
+	byEnd := make(map[int]int, len(edges))
+	first := len(bw.tris)
+	for k, e := range edges {
+		t := first + k
+		byStart[e.u] = t
+		byEnd[e.v] = t
+		bw.tris = append(bw.tris, dtTriangle{v: [3]int{e.u, e.v, i}, n: [3]int{-1, -1, e.outer}})
+
+		// Внешний сосед теперь примыкает к новому треугольнику по ребру (v, u)
+		outer := &bw.tris[e.outer]
+		for j := 0; j < 3; j++ {
+			if outer.v[(j+1)%3] == e.v && outer.v[(j+2)%3] == e.u {
+				outer.n[j] = t
+			}
+		}
+	}
+	for k, e := range edges {
+		t := &bw.tris[first+k]
+		t.n[0] = byStart[e.v]
+		t.n[1] = byEnd[e.u]
+		if e.u != infinite && e.v != infinite {
+			bw.last = first + k
+		}
+	}
+}
+
+// uniquePoints возвращает индексы первых вхождений различных точек
+func uniquePoints(points []types.Point) []int {
+	seen := make(map[[2]float64]bool, len(points))
+	unique := make([]int, 0, len(points))
+	for i, p := range points {
+		key := [2]float64{p.GetLongitude(), p.GetLatitude()}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, i)
+		}
+	}
+	return unique
+}
+
+// Delaunay строит триангуляцию Делоне набора точек на плоскости локальной системы координат.
+// Совпадающие точки учитываются один раз, в треугольники попадает индекс первой из них.
+// Возвращает ErrDegenerate, если различных точек меньше трех или все они лежат на одной прямой
+func Delaunay(points []types.Point) (*Triangulation, error) {
+	unique := uniquePoints(points)
+	frame := newLocalFrame(points)
+	pts := make([][2]float64, len(unique))
+	for k, i := range unique {
+		pts[k] = frame.forward(points[i])
+	}
+
+	triangles := delaunay(pts)
+	if len(triangles) == 0 {
+		return nil, ErrDegenerate
+	}
+	for k, t := range triangles {
+		triangles[k] = Triangle{unique[t[0]], unique[t[1]], unique[t[2]]}
+	}
+	return &Triangulation{Points: points, Triangles: triangles}, nil
+}
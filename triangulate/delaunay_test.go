@@ -0,0 +1,146 @@
+package triangulate
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// ringArea вычисляет ориентированную площадь контура на плоскости долгота/широта
+func ringArea(ring types.LineString) float64 {
+	area := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += a.GetLongitude()*b.GetLatitude() - b.GetLongitude()*a.GetLatitude()
+	}
+	return area / 2
+}
+
+// polygonsArea вычисляет площадь набора полигонов с дырами на плоскости долгота/широта
+func polygonsArea(mp types.MultiPolygon) float64 {
+	area := 0.0
+	for _, polygon := range mp {
+		for r, ring := range polygon {
+			if r == 0 {
+				area += math.Abs(ringArea(ring))
+			} else {
+				area -= math.Abs(ringArea(ring))
+			}
+		}
+	}
+	return area
+}
+
+// randomPoints возвращает n случайных точек в прямоугольнике
+func randomPoints(r *rand.Rand, n int, minLon, minLat, maxLon, maxLat float64) []types.Point {
+	points := make([]types.Point, n)
+	for i := range points {
+		points[i] = types.NewPoint(minLon+r.Float64()*(maxLon-minLon), minLat+r.Float64()*(maxLat-minLat))
+	}
+	return points
+}
+
+func TestDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := randomPoints(r, 300, 37, 55, 38, 56)
+	// Повторы учитываются один раз
+	points = append(points, points[0], points[10])
+
+	tr, err := Delaunay(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := newLocalFrame(points)
+	local := make([][2]float64, len(points))
+	for i, p := range points {
+		local[i] = frame.forward(p)
+	}
+
+	for _, tri := range tr.Triangles {
+		a, b, c := local[tri[0]], local[tri[1]], local[tri[2]]
+		if tri[0] >= 300 || tri[1] >= 300 || tri[2] >= 300 {
+			t.Fatalf("triangle %v refers to a duplicate point", tri)
+		}
+		if orient(a, b, c) <= 0 {
+			t.Fatalf("triangle %v is not counter-clockwise", tri)
+		}
+		// Описанная окружность не содержит других точек
+		scale := orient(a, b, c) * (a[0]*a[0] + a[1]*a[1] + 1)
+		for i, d := range local[:300] {
+			if i != tri[0] && i != tri[1] && i != tri[2] && inCircle(a, b, c, d) > 1e-9*scale {
+				t.Fatalf("point %d lies inside the circumcircle of %v", i, tri)
+			}
+		}
+	}
+
+	// Для триангуляции выпуклой оболочки из n вершин ребер на n - 1 больше, чем треугольников
+	if e, n := len(tr.Edges()), 300; e-len(tr.Triangles) != n-1 {
+		t.Errorf("%d edges and %d triangles for %d points", e, len(tr.Triangles), n)
+	}
+
+	neighbors := tr.Neighbors()
+	for i, list := range neighbors[:300] {
+		if len(list) < 2 {
+			t.Fatalf("point %d has %d neighbours", i, len(list))
+		}
+	}
+	if len(neighbors[300]) != 0 {
+		t.Errorf("duplicate point has neighbours %v", neighbors[300])
+	}
+}
+
+func TestDelaunayDegenerate(t *testing.T) {
+	for _, points := range [][]types.Point{
+		nil,
+		{types.NewPoint(0, 0), types.NewPoint(1, 1)},
+		{types.NewPoint(0, 0), types.NewPoint(1, 1), types.NewPoint(2, 2), types.NewPoint(3, 3)},
+		{types.NewPoint(0, 0), types.NewPoint(0, 0), types.NewPoint(1, 0)},
+	} {
+		if _, err := Delaunay(points); !errors.Is(err, ErrDegenerate) {
+			t.Errorf("Delaunay(%v) error = %v, want ErrDegenerate", points, err)
+		}
+	}
+}
+
+func TestSphericalDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := make([]types.Point, 200)
+	for i := range points {
+		points[i] = types.NewPoint(-180+r.Float64()*360, math.Asin(2*r.Float64()-1)*180/math.Pi)
+	}
+
+	tr, err := SphericalDelaunay(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Триангуляция сферы из n вершин содержит 2n - 4 треугольника
+	if len(tr.Triangles) != 2*len(points)-4 {
+		t.Fatalf("%d triangles for %d points, want %d", len(tr.Triangles), len(points), 2*len(points)-4)
+	}
+
+	vectors := make([]vec3, len(points))
+	for i, p := range points {
+		vectors[i] = toVec3(p)
+	}
+	for _, tri := range tr.Triangles {
+		a, b, c := vectors[tri[0]], vectors[tri[1]], vectors[tri[2]]
+		normal := b.sub(a).cross(c.sub(a))
+		if normal.dot(a) <= 0 {
+			t.Fatalf("triangle %v is not counter-clockwise from outside", tri)
+		}
+		// Все точки лежат по одну сторону от плоскости грани: окружность грани пуста
+		for i, v := range vectors {
+			if normal.dot(v.sub(a)) > 1e-12 {
+				t.Fatalf("point %d lies outside the face %v", i, tri)
+			}
+		}
+	}
+
+	if _, err := SphericalDelaunay(points[:3]); !errors.Is(err, ErrDegenerate) {
+		t.Errorf("three points error = %v, want ErrDegenerate", err)
+	}
+}
@@ -0,0 +1,690 @@
+package triangulate
+
+import (
+	"math"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// earcutHashThreshold - количество вершин, начиная с которого проверка ушей
+// ускоряется сортировкой вершин по Z-кривой
+const earcutHashThreshold = 80
+
+// Mesh - треугольники полигонов в виде буферов для отрисовки (например, в WebGL)
+type Mesh struct {
+	// Coordinates - координаты вершин подряд: долгота и широта первой вершины, затем второй и т.д.
+	// Вершины идут в порядке колец полигона без замыкающих точек
+	Coordinates []float64
+	// Indices - номера вершин в Coordinates, по три на треугольник.
+	// Треугольники обходятся против часовой стрелки
+	Indices []uint32
+}
+
+// Len возвращает количество треугольников
+func (m *Mesh) Len() int {
+	return len(m.Indices) / 3
+}
+
+// Vertex возвращает вершину с номером i
+func (m *Mesh) Vertex(i int) types.Point {
+	return types.NewPoint(m.Coordinates[2*i], m.Coordinates[2*i+1])
+}
+
+// Triangle возвращает номера вершин треугольника i
+func (m *Mesh) Triangle(i int) Triangle {
+	return Triangle{int(m.Indices[3*i]), int(m.Indices[3*i+1]), int(m.Indices[3*i+2])}
+}
+
+// MultiPolygon возвращает треугольники в виде полигонов
+func (m *Mesh) MultiPolygon() types.MultiPolygon {
+	polygons := make(types.MultiPolygon, 0, m.Len())
+	for i := 0; i < m.Len(); i++ {
+		t := m.Triangle(i)
+		a, b, c := m.Vertex(t[0]), m.Vertex(t[1]), m.Vertex(t[2])
+		polygons = append(polygons, types.NewPolygon(types.NewLineString(a, b, c, a)))
+	}
+	return polygons
+}
+
+// Earcut разбивает полигон с дырами на треугольники методом отсечения ушей
+// (по алгоритму библиотеки earcut). Вершины триангулируются на плоскости долгота/широта.
+// Повторяющиеся вершины, вершины на одной прямой с соседними и кольца нулевой площади
+// не порождают треугольников, поэтому вырожденные кольца дают пустой результат,
+// а не ошибку. Самопересекающиеся кольца триангулируются приближенно
+func Earcut(polygon types.Polygon) *Mesh {
+	m := &Mesh{}
+	m.add(polygon)
+	return m
+}
+
+// EarcutMultiPolygon разбивает на треугольники все полигоны набора и объединяет
+// результат в общие буферы. Вершины полигонов идут в порядке набора
+func EarcutMultiPolygon(mp types.MultiPolygon) *Mesh {
+	m := &Mesh{}
+	for _, polygon := range mp {
+		m.add(polygon)
+	}
+	return m
+}
+
+// add добавляет вершины и треугольники полигона
+func (m *Mesh) add(polygon types.Polygon) {
+	if len(polygon) == 0 {
+		return
+	}
+
+	// Вершины колец без замыкающих точек; rings - границы колец в номерах вершин
+	offset := len(m.Coordinates) / 2
+	rings := make([][2]int, 0, len(polygon))
+	for _, ring := range polygon {
+		n := len(ring)
+		if n > 1 && ring[0].GetLongitude() == ring[n-1].GetLongitude() && ring[0].GetLatitude() == ring[n-1].GetLatitude() {
+			n--
+		}
+		start := len(m.Coordinates) / 2
+		for _, p := range ring[:n] {
+			m.Coordinates = append(m.Coordinates, p.GetLongitude(), p.GetLatitude())
+		}
+		rings = append(rings, [2]int{start, start + n})
+	}
+
+	e := &earcut{coords: m.Coordinates}
+	outer := e.linkedList(rings[0][0], rings[0][1], true)
+	if outer == nil || outer.next == outer.prev {
+		return
+	}
+	if len(rings) > 1 {
+		outer = e.eliminateHoles(rings[1:], outer)
+	}
+
+	// Для больших полигонов вершины индексируются по Z-кривой в пределах прямоугольника полигона
+	if end := len(m.Coordinates) / 2; end-offset > earcutHashThreshold {
+		e.minX, e.minY = math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for i := offset; i < end; i++ {
+			x, y := m.Coordinates[2*i], m.Coordinates[2*i+1]
+			e.minX, e.minY = math.Min(e.minX, x), math.Min(e.minY, y)
+			maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+		}
+		if size := math.Max(maxX-e.minX, maxY-e.minY); size > 0 {
+			e.invSize = 32767 / size
+		}
+	}
+
+	e.earcutLinked(outer, 0)
+	m.Indices = append(m.Indices, e.triangles...)
+}
+
+// earNode - вершина кольца в двусвязном списке. Кроме соседей по кольцу, вершина
+// связана с соседями по Z-кривой
+type earNode struct {
+	i            int
+	x, y         float64
+	prev, next   *earNode
+	z            uint32
+	prevZ, nextZ *earNode
+	// steiner - дыра из одной точки, которую нельзя удалять как вырожденную
+	steiner bool
+}
+
+// earcut - состояние триангуляции одного полигона
+type earcut struct {
+	coords     []float64
+	minX, minY float64
+	// invSize - масштаб координат для кода Z-кривой; ноль отключает индексацию
+	invSize   float64
+	triangles []uint32
+}
+
+// linkedList строит кольцевой список вершин start..end. Внешнее кольцо (outer) обходится
+// против часовой стрелки, дыры - по часовой
+func (e *earcut) linkedList(start, end int, outer bool) *earNode {
+	// Удвоенная площадь кольца; положительна при обходе против часовой стрелки
+	area := 0.0
+	for i, j := start, end-1; i < end; j, i = i, i+1 {
+		area += (e.coords[2*j] - e.coords[2*i]) * (e.coords[2*i+1] + e.coords[2*j+1])
+	}
+
+	var last *earNode
+	if outer == (area > 0) {
+		for i := start; i < end; i++ {
+			last = insertEarNode(i, e.coords[2*i], e.coords[2*i+1], last)
+		}
+	} else {
+		for i := end - 1; i >= start; i-- {
+			last = insertEarNode(i, e.coords[2*i], e.coords[2*i+1], last)
+		}
+	}
+	if last != nil && equalNodes(last, last.next) {
+		removeEarNode(last)
+		last = last.next
+	}
+	return last
+}
+
+// filterPoints удаляет повторяющиеся вершины и вершины на одной прямой с соседними
+func filterPoints(start, end *earNode) *earNode {
+	if start == nil {
+		return nil
+	}
+	if end == nil {
+		end = start
+	}
+
+	p := start
+	for {
+		again := false
+		if !p.steiner && (equalNodes(p, p.next) || nodeArea(p.prev, p, p.next) == 0) {
+			removeEarNode(p)
+			p, end = p.prev, p.prev
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// earcutLinked отсекает уши кольца. Если ушей не осталось, следующий проход сначала удаляет
+// вырожденные вершины, затем устраняет локальные самопересечения и в последнюю очередь
+// делит кольцо диагональю на два
+func (e *earcut) earcutLinked(ear *earNode, pass int) {
+	if ear == nil {
+		return
+	}
+	if pass == 0 && e.invSize > 0 {
+		e.indexCurve(ear)
+	}
+
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+
+		isEar := false
+		if e.invSize > 0 {
+			isEar = e.isEarHashed(ear)
+		} else {
+			isEar = isEarNode(ear)
+		}
+		if isEar {
+			e.addTriangle(prev, ear, next)
+			removeEarNode(ear)
+
+			// Пропуск следующей вершины дает меньше вытянутых треугольников
+			ear, stop = next.next, next.next
+			continue
+		}
+
+		ear = next
+		if ear == stop {
+			switch pass {
+			case 0:
+				e.earcutLinked(filterPoints(ear, nil), 1)
+			case 1:
+				ear = e.cureLocalIntersections(filterPoints(ear, nil))
+				e.earcutLinked(ear, 2)
+			case 2:
+				e.splitEarcut(ear)
+			}
+			return
+		}
+	}
+}
+
+// addTriangle добавляет треугольник с вершинами в порядке обхода кольца
+func (e *earcut) addTriangle(a, b, c *earNode) {
+	e.triangles = append(e.triangles, uint32(a.i), uint32(b.i), uint32(c.i))
+}
+
+// isEarNode проверяет, что вершина выпуклая и в ее треугольнике нет других вершин
+func isEarNode(ear *earNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if nodeArea(a, b, c) >= 0 {
+		return false // вогнутая вершина
+	}
+
+	x0, x1 := math.Min(a.x, math.Min(b.x, c.x)), math.Max(a.x, math.Max(b.x, c.x))
+	y0, y1 := math.Min(a.y, math.Min(b.y, c.y)), math.Max(a.y, math.Max(b.y, c.y))
+	for p := c.next; p != a; p = p.next {
+		if p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 &&
+			pointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			nodeArea(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isEarHashed - isEarNode, проверяющая только вершины с кодами Z-кривой
+// в пределах прямоугольника треугольника
+func (e *earcut) isEarHashed(ear *earNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if nodeArea(a, b, c) >= 0 {
+		return false
+	}
+
+	x0, x1 := math.Min(a.x, math.Min(b.x, c.x)), math.Max(a.x, math.Max(b.x, c.x))
+	y0, y1 := math.Min(a.y, math.Min(b.y, c.y)), math.Max(a.y, math.Max(b.y, c.y))
+	minZ, maxZ := e.zOrder(x0, y0), e.zOrder(x1, y1)
+
+	blocks := func(p *earNode) bool {
+		return p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 && p != a && p != c &&
+			pointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			nodeArea(p.prev, p, p.next) >= 0
+	}
+
+	// Просматриваем вершины в обе стороны по Z-кривой
+	p, n := ear.prevZ, ear.nextZ
+	for p != nil && p.z >= minZ && n != nil && n.z <= maxZ {
+		if blocks(p) {
+			return false
+		}
+		p = p.prevZ
+		if blocks(n) {
+			return false
+		}
+		n = n.nextZ
+	}
+	for ; p != nil && p.z >= minZ; p = p.prevZ {
+		if blocks(p) {
+			return false
+		}
+	}
+	for ; n != nil && n.z <= maxZ; n = n.nextZ {
+		if blocks(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// cureLocalIntersections отсекает треугольники на местах самопересечения соседних ребер
+func (e *earcut) cureLocalIntersections(start *earNode) *earNode {
+	if start == nil {
+		return nil
+	}
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if !equalNodes(a, b) && segmentsIntersect(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			e.addTriangle(a, p, b)
+			removeEarNode(p)
+			removeEarNode(p.next)
+			p, start = b, b
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return filterPoints(p, nil)
+}
+
+// splitEarcut ищет допустимую диагональ, делит по ней кольцо на два и триангулирует их
+func (e *earcut) splitEarcut(start *earNode) {
+	if start == nil {
+		return
+	}
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := splitPolygon(a, b)
+				a = filterPoints(a, a.next)
+				c = filterPoints(c, c.next)
+				e.earcutLinked(a, 0)
+				e.earcutLinked(c, 0)
+				return
+			}
+		}
+		a = a.next
+		if a == start {
+			return
+		}
+	}
+}
+
+// eliminateHoles соединяет дыры с внешним кольцом разрезами, начиная с самой западной дыры
+func (e *earcut) eliminateHoles(holes [][2]int, outer *earNode) *earNode {
+	queue := make([]*earNode, 0, len(holes))
+	for _, h := range holes {
+		list := e.linkedList(h[0], h[1], false)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, leftmostNode(list))
+	}
+	sortByX(queue)
+
+	for _, hole := range queue {
+		outer = eliminateHole(hole, outer)
+	}
+	return outer
+}
+
+// sortByX упорядочивает вершины по долготе с сохранением порядка равных
+func sortByX(nodes []*earNode) {
+	for i := 1; i < len(nodes); i++ {
+		for j := i; j > 0 && nodes[j].x < nodes[j-1].x; j-- {
+			nodes[j], nodes[j-1] = nodes[j-1], nodes[j]
+		}
+	}
+}
+
+// eliminateHole соединяет дыру с внешним кольцом двойным ребром
+func eliminateHole(hole, outer *earNode) *earNode {
+	bridge := findHoleBridge(hole, outer)
+	if bridge == nil {
+		return outer
+	}
+	reverse := splitPolygon(bridge, hole)
+	filterPoints(reverse, reverse.next)
+	return filterPoints(bridge, bridge.next)
+}
+
+// findHoleBridge находит вершину внешнего кольца, которую можно соединить с самой
+// западной вершиной дыры, не пересекая ребер (алгоритм Эберли)
+func findHoleBridge(hole, outer *earNode) *earNode {
+	hx, hy := hole.x, hole.y
+	qx := math.Inf(-1)
+	var m *earNode
+
+	// Ближайшее к дыре с запада ребро на широте ее вершины; m - его западный конец
+	p := outer
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.next.x < p.x {
+					m = p.next
+				}
+				if x == hx {
+					return m // дыра касается внешнего кольца
+				}
+			}
+		}
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+
+	// Если в треугольнике между вершиной дыры, точкой пересечения и m есть другие вершины,
+	// выбираем из них вершину с наименьшим углом к широте дыры
+	stop := m
+	mx, my := m.x, m.y
+	tanMin := math.Inf(1)
+	p = m
+	for {
+		ax, cx := qx, hx
+		if hy < my {
+			ax, cx = hx, qx
+		}
+		if hx >= p.x && p.x >= mx && hx != p.x && pointInTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+			tan := math.Abs(hy-p.y) / (hx - p.x)
+			if locallyInside(p, hole) &&
+				(tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && sectorContainsSector(m, p))))) {
+				m, tanMin = p, tan
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+	return m
+}
+
+// sectorContainsSector проверяет, что угол при вершине m содержит угол при вершине p
+func sectorContainsSector(m, p *earNode) bool {
+	return nodeArea(m.prev, m, p.prev) < 0 && nodeArea(p.next, m, m.next) < 0
+}
+
+// indexCurve вычисляет коды Z-кривой вершин и сортирует по ним список prevZ/nextZ
+func (e *earcut) indexCurve(start *earNode) {
+	p := start
+	for {
+		if p.z == 0 {
+			p.z = e.zOrder(p.x, p.y)
+		}
+		p.prevZ, p.nextZ = p.prev, p.next
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	p.prevZ.nextZ = nil
+	p.prevZ = nil
+	sortLinked(p)
+}
+
+// sortLinked сортирует список по кодам Z-кривой слиянием (алгоритм Саймона Тэтэма)
+func sortLinked(list *earNode) *earNode {
+	inSize := 1
+	for {
+		p := list
+		list = nil
+		var tail *earNode
+		merges := 0
+
+		for p != nil {
+			merges++
+			q := p
+			pSize := 0
+			for i := 0; i < inSize && q != nil; i++ {
+				pSize++
+				q = q.nextZ
+			}
+			qSize := inSize
+
+			for pSize > 0 || (qSize > 0 && q != nil) {
+				var n *earNode
+				if pSize != 0 && (qSize == 0 || q == nil || p.z <= q.z) {
+					n = p
+					p = p.nextZ
+					pSize--
+				} else {
+					n = q
+					q = q.nextZ
+					qSize--
+				}
+				if tail != nil {
+					tail.nextZ = n
+				} else {
+					list = n
+				}
+				n.prevZ = tail
+				tail = n
+			}
+			p = q
+		}
+		tail.nextZ = nil
+		inSize *= 2
+		if merges <= 1 {
+			return list
+		}
+	}
+}
+
+// zOrder возвращает код Z-кривой точки: чередование битов 15-битных координат
+func (e *earcut) zOrder(x, y float64) uint32 {
+	ix := uint32(int32((x - e.minX) * e.invSize))
+	iy := uint32(int32((y - e.minY) * e.invSize))
+	return spreadBits(ix) | spreadBits(iy)<<1
+}
+
+// spreadBits разносит младшие 16 бит числа по четным позициям
+func spreadBits(v uint32) uint32 {
+	v &= 0xFFFF
+	v = (v | v<<8) & 0x00FF00FF
+	v = (v | v<<4) & 0x0F0F0F0F
+	v = (v | v<<2) & 0x33333333
+	v = (v | v<<1) & 0x55555555
+	return v
+}
+
+// leftmostNode возвращает самую западную вершину кольца, при равенстве - самую южную
+func leftmostNode(start *earNode) *earNode {
+	leftmost := start
+	for p := start.next; p != start; p = p.next {
+		if p.x < leftmost.x || (p.x == leftmost.x && p.y < leftmost.y) {
+			leftmost = p
+		}
+	}
+	return leftmost
+}
+
+// pointInTriangle проверяет, что точка p лежит в треугольнике abc или на его границе
+func pointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
+		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
+		(bx-px)*(cy-py) >= (cx-px)*(by-py)
+}
+
+// isValidDiagonal проверяет, что отрезок ab можно провести внутри кольца
+func isValidDiagonal(a, b *earNode) bool {
+	if a.next.i == b.i || a.prev.i == b.i || intersectsPolygon(a, b) {
+		return false
+	}
+	if locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) &&
+		(nodeArea(a.prev, a, b.prev) != 0 || nodeArea(a, b.prev, b) != 0) {
+		return true
+	}
+	// Совпадающие вершины с выпуклыми углами
+	return equalNodes(a, b) && nodeArea(a.prev, a, a.next) > 0 && nodeArea(b.prev, b, b.next) > 0
+}
+
+// nodeArea возвращает удвоенную площадь треугольника pqr со знаком:
+// отрицательная при обходе против часовой стрелки
+func nodeArea(p, q, r *earNode) float64 {
+	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
+}
+
+// equalNodes проверяет совпадение координат вершин
+func equalNodes(a, b *earNode) bool {
+	return a.x == b.x && a.y == b.y
+}
+
+// segmentsIntersect проверяет пересечение отрезков p1q1 и p2q2, включая касание
+func segmentsIntersect(p1, q1, p2, q2 *earNode) bool {
+	o1 := sign(nodeArea(p1, q1, p2))
+	o2 := sign(nodeArea(p1, q1, q2))
+	o3 := sign(nodeArea(p2, q2, p1))
+	o4 := sign(nodeArea(p2, q2, q1))
+
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	// Концы на одной прямой с другим отрезком
+	return (o1 == 0 && onSegment(p1, p2, q1)) || (o2 == 0 && onSegment(p1, q2, q1)) ||
+		(o3 == 0 && onSegment(p2, p1, q2)) || (o4 == 0 && onSegment(p2, q1, q2))
+}
+
+// onSegment проверяет, что точка q, лежащая на прямой pr, находится между p и r
+func onSegment(p, q, r *earNode) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) &&
+		q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+// sign возвращает знак числа
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// intersectsPolygon проверяет, что отрезок ab пересекает ребра кольца, не имеющие с ним общих вершин
+func intersectsPolygon(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i && segmentsIntersect(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+// locallyInside проверяет, что отрезок ab начинается внутрь кольца в окрестности вершины a
+func locallyInside(a, b *earNode) bool {
+	if nodeArea(a.prev, a, a.next) < 0 {
+		return nodeArea(a, b, a.next) >= 0 && nodeArea(a, a.prev, b) >= 0
+	}
+	return nodeArea(a, b, a.prev) < 0 || nodeArea(a, a.next, b) < 0
+}
+
+// middleInside проверяет, что середина отрезка ab лежит внутри кольца
+func middleInside(a, b *earNode) bool {
+	inside := false
+	px, py := (a.x+b.x)/2, (a.y+b.y)/2
+	p := a
+	for {
+		if (p.y > py) != (p.next.y > py) && p.next.y != p.y &&
+			px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// splitPolygon соединяет вершины a и b диагональю, разделяя кольцо на два.
+// Вершины a и b дублируются; возвращает копию b во втором кольце
+func splitPolygon(a, b *earNode) *earNode {
+	a2 := &earNode{i: a.i, x: a.x, y: a.y}
+	b2 := &earNode{i: b.i, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+
+	a.next, b.prev = b, a
+	a2.next, an.prev = an, a2
+	b2.next, a2.prev = a2, b2
+	bp.next, b2.prev = b2, bp
+	return b2
+}
+
+// insertEarNode вставляет вершину после last и возвращает ее
+func insertEarNode(i int, x, y float64, last *earNode) *earNode {
+	p := &earNode{i: i, x: x, y: y}
+	if last == nil {
+		p.prev, p.next = p, p
+	} else {
+		p.next, p.prev = last.next, last
+		last.next.prev = p
+		last.next = p
+	}
+	return p
+}
+
+// removeEarNode исключает вершину из кольца и из списка Z-кривой
+func removeEarNode(p *earNode) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+	if p.prevZ != nil {
+		p.prevZ.nextZ = p.nextZ
+	}
+	if p.nextZ != nil {
+		p.nextZ.prevZ = p.prevZ
+	}
+}
@@ -0,0 +1,127 @@
+package triangulate
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// starRing возвращает простой невыпуклый контур из n вершин вокруг центра с обходом против часовой стрелки
+func starRing(r *rand.Rand, lon, lat, radius float64, n int) types.LineString {
+	ring := make(types.LineString, 0, n+1)
+	for i := 0; i < n; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		d := radius * (0.5 + 0.5*r.Float64())
+		ring = append(ring, types.NewPoint(lon+d*math.Cos(angle), lat+d*math.Sin(angle)))
+	}
+	return append(ring, ring[0])
+}
+
+// checkMesh проверяет, что треугольники обходятся против часовой стрелки,
+// а их суммарная площадь равна площади полигонов
+func checkMesh(t *testing.T, m *Mesh, mp types.MultiPolygon) {
+	t.Helper()
+	if len(m.Indices)%3 != 0 {
+		t.Fatalf("%d indices is not a multiple of 3", len(m.Indices))
+	}
+	total := 0.0
+	for _, triangle := range m.MultiPolygon() {
+		area := ringArea(triangle[0][:3])
+		if area <= 0 {
+			t.Fatalf("triangle %v is not counter-clockwise", triangle[0])
+		}
+		total += area
+	}
+	if want := polygonsArea(mp); math.Abs(total-want) > 1e-9*want {
+		t.Errorf("triangles cover %g, polygon area %g", total, want)
+	}
+}
+
+func TestEarcutSquareWithHole(t *testing.T) {
+	polygon := types.NewPolygon(box(0, 0, 10, 10), box(4, 4, 6, 6).Reverse())
+	m := Earcut(polygon)
+	if m.Len() != 8 {
+		t.Errorf("%d triangles, want 8", m.Len())
+	}
+	if len(m.Coordinates) != 16 {
+		t.Errorf("%d coordinates, want 16 without closing points", len(m.Coordinates))
+	}
+	checkMesh(t, m, types.MultiPolygon{polygon})
+}
+
+func TestEarcutLargePolygon(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	// Вершин больше earcutHashThreshold, поэтому проверка ушей идет по Z-кривой
+	polygon := types.NewPolygon(
+		starRing(r, 37.6, 55.7, 1, 300),
+		starRing(r, 37.3, 55.7, 0.1, 40).Reverse(),
+		starRing(r, 37.9, 55.7, 0.1, 40).Reverse(),
+	)
+	m := Earcut(polygon)
+
+	// Триангуляция полигона из n вершин с h дырами содержит n + 2h - 2 треугольника
+	if want := 380 + 2*2 - 2; m.Len() != want {
+		t.Errorf("%d triangles, want %d", m.Len(), want)
+	}
+	checkMesh(t, m, types.MultiPolygon{polygon})
+
+	// Направление обхода входных колец не влияет на результат
+	reversed := types.NewPolygon(polygon[0].Reverse(), polygon[1].Reverse(), polygon[2].Reverse())
+	checkMesh(t, Earcut(reversed), types.MultiPolygon{polygon})
+}
+
+func TestEarcutDegenerate(t *testing.T) {
+	for _, polygon := range []types.Polygon{
+		nil,
+		{},
+		types.NewPolygon(types.NewLineString()),
+		types.NewPolygon(types.NewLineString(types.NewPoint(0, 0), types.NewPoint(1, 1))),
+		types.NewPolygon(types.NewLineString(
+			types.NewPoint(0, 0), types.NewPoint(1, 1), types.NewPoint(2, 2), types.NewPoint(0, 0))),
+		types.NewPolygon(types.NewLineString(
+			types.NewPoint(1, 1), types.NewPoint(1, 1), types.NewPoint(1, 1), types.NewPoint(1, 1))),
+	} {
+		if m := Earcut(polygon); m.Len() != 0 {
+			t.Errorf("Earcut(%v) returned %d triangles", polygon, m.Len())
+		}
+	}
+
+	// Повторяющиеся вершины и вершины на одной прямой с соседними не порождают треугольников
+	polygon := types.NewPolygon(types.NewLineString(
+		types.NewPoint(0, 0), types.NewPoint(1, 0), types.NewPoint(1, 0), types.NewPoint(2, 0),
+		types.NewPoint(2, 2), types.NewPoint(0, 2), types.NewPoint(0, 0),
+	))
+	m := Earcut(polygon)
+	if m.Len() != 2 {
+		t.Errorf("%d triangles, want 2", m.Len())
+	}
+	checkMesh(t, m, types.MultiPolygon{polygon})
+}
+
+func TestEarcutMultiPolygon(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	mp := types.MultiPolygon{
+		types.NewPolygon(box(0, 0, 10, 10), box(4, 4, 6, 6).Reverse()),
+		types.NewPolygon(starRing(r, 20, 0, 3, 100)),
+	}
+	m := EarcutMultiPolygon(mp)
+	checkMesh(t, m, mp)
+
+	first, second := Earcut(mp[0]), Earcut(mp[1])
+	if !slices.Equal(m.Coordinates, append(slices.Clone(first.Coordinates), second.Coordinates...)) {
+		t.Fatal("coordinates are not the concatenation of the polygons")
+	}
+
+	// Номера вершин второго полигона сдвинуты на количество вершин первого
+	offset := uint32(len(first.Coordinates) / 2)
+	want := slices.Clone(first.Indices)
+	for _, i := range second.Indices {
+		want = append(want, i+offset)
+	}
+	if !slices.Equal(m.Indices, want) {
+		t.Errorf("indices of the second polygon are not offset by %d", offset)
+	}
+}
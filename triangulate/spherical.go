@@ -0,0 +1,291 @@
+package triangulate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// sphericalEdgeStep - наибольший угол между соседними вершинами ребра сферической ячейки
+// в радианах. Ребра ячеек - дуги больших кругов, поэтому они аппроксимируются отрезками
+const sphericalEdgeStep = 0.5 * math.Pi / 180
+
+// vec3 - вектор трехмерного пространства
+type vec3 [3]float64
+
+func (a vec3) sub(b vec3) vec3 { return vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+func (a vec3) dot(b vec3) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+func (a vec3) cross(b vec3) vec3 {
+	return vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+func (a vec3) normalize() vec3 {
+	l := math.Sqrt(a.dot(a))
+	return vec3{a[0] / l, a[1] / l, a[2] / l}
+}
+
+// toVec3 переводит точку в единичный вектор
+func toVec3(p types.Point) vec3 {
+	lon, lat := p.GetLongitude()*math.Pi/180, p.GetLatitude()*math.Pi/180
+	return vec3{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+// toLonLat переводит единичный вектор в долготу и широту в градусах
+func toLonLat(v vec3) (float64, float64) {
+	return math.Atan2(v[1], v[0]) * 180 / math.Pi, math.Asin(math.Max(-1, math.Min(1, v[2]))) * 180 / math.Pi
+}
+
+// convexHull строит выпуклую оболочку точек единичной сферы инкрементальным алгоритмом.
+// Грани ориентированы наружу: вершины обходятся против часовой стрелки, если смотреть снаружи.
+// Возвращает nil, если все точки лежат в одной плоскости
+func convexHull(pts []vec3) []Triangle {
+	if len(pts) < 4 {
+		return nil
+	}
+
+	// Допуск расстояния до плоскости грани. Точки лежат на единичной сфере, поэтому
+	// допуск абсолютный: 1e-14 соответствует долям миллиметра на поверхности Земли
+	const eps = 1e-14
+
+	// Начальный тетраэдр: две первые точки, самая удаленная от их прямой и самая удаленная от их плоскости
+	a, b := 0, 1
+	c, best := -1, eps
+	for i := 2; i < len(pts); i++ {
+		axis := pts[b].sub(pts[a]).normalize()
+		if d := axis.cross(pts[i].sub(pts[a])); math.Sqrt(d.dot(d)) > best {
+			c, best = i, math.Sqrt(d.dot(d))
+		}
+	}
+	if c < 0 {
+		return nil
+	}
+	normal := pts[b].sub(pts[a]).cross(pts[c].sub(pts[a])).normalize()
+	d, best := -1, eps
+	for i := 2; i < len(pts); i++ {
+		if v := math.Abs(normal.dot(pts[i].sub(pts[a]))); v > best {
+			d, best = i, v
+		}
+	}
+	if d < 0 {
+		return nil
+	}
+	if normal.dot(pts[d].sub(pts[a])) > 0 {
+		b, c = c, b
+	}
+
+	// hullFace - грань оболочки с единичной внешней нормалью
+	type hullFace struct {
+		t      Triangle
+		normal vec3
+	}
+	newFace := func(t Triangle) hullFace {
+		return hullFace{t: t, normal: pts[t[1]].sub(pts[t[0]]).cross(pts[t[2]].sub(pts[t[0]])).normalize()}
+	}
+
+	faces := []hullFace{newFace(Triangle{a, b, c}), newFace(Triangle{a, d, b}), newFace(Triangle{b, d, c}), newFace(Triangle{c, d, a})}
+	for i := range pts {
+		if i == a || i == b || i == c || i == d {
+			continue
+		}
+
+		// Грани, видимые из точки, удаляются; горизонт - их ребра, не имеющие видимой пары
+		visible := make(map[[2]int]bool)
+		kept := faces[:0:0]
+		for _, f := range faces {
+			if f.normal.dot(pts[i].sub(pts[f.t[0]])) > eps {
+				for k := 0; k < 3; k++ {
+					visible[[2]int{f.t[k], f.t[(k+1)%3]}] = true
+				}
+			} else {
+				kept = append(kept, f)
+			}
+		}
+		if len(visible) == 0 {
+			continue // точка внутри оболочки
+		}
+		for e := range visible {
+			if !visible[[2]int{e[1], e[0]}] {
+				kept = append(kept, newFace(Triangle{e[0], e[1], i}))
+			}
+		}
+		faces = kept
+	}
+
+	triangles := make([]Triangle, len(faces))
+	for k, f := range faces {
+		triangles[k] = f.t
+	}
+	return triangles
+}
+
+// SphericalDelaunay строит триангуляцию Делоне набора точек на сфере как выпуклую оболочку
+// соответствующих единичных векторов. Треугольники покрывают всю сферу и обходятся против
+// часовой стрелки, если смотреть снаружи. Совпадающие точки учитываются один раз.
+// Возвращает ErrDegenerate, если различных точек меньше четырех или все они лежат на одной окружности
+func SphericalDelaunay(points []types.Point) (*Triangulation, error) {
+	unique := uniquePoints(points)
+	pts := make([]vec3, len(unique))
+	for k, i := range unique {
+		pts[k] = toVec3(points[i])
+	}
+
+	faces := convexHull(pts)
+	if len(faces) == 0 {
+		return nil, ErrDegenerate
+	}
+	for k, f := range faces {
+		faces[k] = Triangle{unique[f[0]], unique[f[1]], unique[f[2]]}
+	}
+	return &Triangulation{Points: points, Triangles: faces}, nil
+}
+
+// SphericalVoronoi строит диаграмму Вороного набора точек на сфере и обрезает ячейки
+// по полигону boundary в координатах долгота/широта. Ребра ячеек - дуги больших кругов,
+// аппроксимированные отрезками через 0,5°. Ячейки строятся непрерывными по долготе вокруг
+// порождающей точки и обрезаются со сдвигами долготы на 360°, а ячейки, содержащие полюс,
+// замыкаются по широте ±90°, поэтому граница задается в пределах [-180, 180] x [-90, 90].
+// Для всей Земли boundary - прямоугольник от -180 до 180 по долготе и от -90 до 90 по широте.
+// Остальные соглашения совпадают с Voronoi
+func SphericalVoronoi(points []types.Point, boundary types.Polygon) ([]VoronoiCell, error) {
+	if len(boundary) == 0 || len(boundary[0]) < 4 {
+		return nil, fmt.Errorf("voronoi boundary with %d rings: %w", len(boundary), ErrDegenerate)
+	}
+	unique := uniquePoints(points)
+	pts := make([]vec3, len(unique))
+	for k, i := range unique {
+		pts[k] = toVec3(points[i])
+	}
+
+	faces := convexHull(pts)
+	if len(faces) == 0 {
+		return nil, fmt.Errorf("spherical voronoi of %d points: %w", len(unique), ErrDegenerate)
+	}
+
+	// Вершина Вороного - центр описанной окружности грани, то есть нормаль грани.
+	// next[i][j] = k для грани (i, j, k): обход соседей точки i против часовой стрелки
+	centers := make([]vec3, len(faces))
+	next := make([]map[int]int, len(pts))
+	faceOf := make([]map[int]int, len(pts))
+	for f, t := range faces {
+		centers[f] = pts[t[1]].sub(pts[t[0]]).cross(pts[t[2]].sub(pts[t[0]])).normalize()
+		for k := 0; k < 3; k++ {
+			i, j, l := t[k], t[(k+1)%3], t[(k+2)%3]
+			if next[i] == nil {
+				next[i] = make(map[int]int)
+				faceOf[i] = make(map[int]int)
+			}
+			next[i][j] = l
+			faceOf[i][j] = f
+		}
+	}
+
+	// Ячейки полюсов - ячейки ближайших к полюсам точек
+	north, south := 0, 0
+	for k, p := range pts {
+		if p[2] > pts[north][2] {
+			north = k
+		}
+		if p[2] < pts[south][2] {
+			south = k
+		}
+	}
+	if north == south {
+		return nil, fmt.Errorf("spherical voronoi cell of point %d contains both poles: %w", unique[north], ErrDegenerate)
+	}
+
+	boundaryBox := calc.CalculatePlanarBoundingBox(boundary)
+	var cells []VoronoiCell
+	for k, i := range unique {
+		if len(next[k]) == 0 {
+			continue
+		}
+
+		// Вершины ячейки в порядке обхода соседей, начиная с соседа с наименьшим индексом
+		start := -1
+		for j := range next[k] {
+			if start < 0 || j < start {
+				start = j
+			}
+		}
+		var vertices []vec3
+		for j := start; ; {
+			vertices = append(vertices, centers[faceOf[k][j]])
+			j = next[k][j]
+			if j == start || len(vertices) > len(faces) {
+				break
+			}
+		}
+
+		ring := sphericalRing(vertices, points[i].GetLongitude())
+		if k == north || k == south {
+			// Обход вокруг полюса смещает долготу на 360°: доводим кольцо до первой точки
+			// со смещенной долготой и замыкаем его по широте полюса
+			first, last := ring[0], ring[len(ring)-1]
+			closing := first.GetLongitude() + 360*math.Round((last.GetLongitude()-first.GetLongitude())/360)
+			pole := 90.0
+			if k == south {
+				pole = -90
+			}
+			ring = append(ring, types.NewPoint(closing, first.GetLatitude()),
+				types.NewPoint(closing, pole), types.NewPoint(first.GetLongitude(), pole))
+		}
+		ring = append(ring, ring[0])
+
+		// Ячейка, вышедшая за антимеридиан, обрезается также со сдвигами на 360°.
+		// Кольцо ячейки полюса занимает 360° по долготе и может пересекать границу дважды
+		var polygons types.MultiPolygon
+		cellBox := calc.CalculatePlanarBoundingBox(types.Polygon{ring})
+		from := math.Ceil((boundaryBox.MinLon - cellBox.MaxLon) / 360)
+		to := math.Floor((boundaryBox.MaxLon - cellBox.MinLon) / 360)
+		for n := from; n <= to; n++ {
+			shift := 360 * n
+			window := ring
+			if shift != 0 {
+				window = make(types.LineString, len(ring))
+				for m, p := range ring {
+					window[m] = types.NewPoint(p.GetLongitude()+shift, p.GetLatitude())
+				}
+			}
+			polygons = append(polygons, calc.ClipPolygon(boundary, window)...)
+		}
+		if len(polygons) > 0 {
+			cells = append(cells, VoronoiCell{Index: i, Site: points[i], Polygons: polygons})
+		}
+	}
+	return cells, nil
+}
+
+// sphericalRing переводит вершины сферического многоугольника в кольцо долгота/широта
+// без замыкающей точки. Дуги между вершинами аппроксимируются отрезками, долгота
+// меняется непрерывно, начиная с ближайшего к lon0 значения
+func sphericalRing(vertices []vec3, lon0 float64) types.LineString {
+	var ring types.LineString
+	prevLon := lon0
+	for n, a := range vertices {
+		b := vertices[(n+1)%len(vertices)]
+		angle := math.Acos(math.Max(-1, math.Min(1, a.dot(b))))
+		steps := max(int(math.Ceil(angle/sphericalEdgeStep)), 1)
+		for s := 0; s < steps; s++ {
+			v := slerp(a, b, angle, float64(s)/float64(steps))
+			lon, lat := toLonLat(v)
+			// Выбираем значение долготы, ближайшее к предыдущему
+			lon += 360 * math.Round((prevLon-lon)/360)
+			ring = append(ring, types.NewPoint(lon, lat))
+			prevLon = lon
+		}
+	}
+	return ring
+}
+
+// slerp интерполирует единичные векторы вдоль дуги большого круга с углом angle
+func slerp(a, b vec3, angle, t float64) vec3 {
+	if angle < 1e-12 {
+		return a
+	}
+	sa := math.Sin((1-t)*angle) / math.Sin(angle)
+	sb := math.Sin(t*angle) / math.Sin(angle)
+	return vec3{sa*a[0] + sb*b[0], sa*a[1] + sb*b[1], sa*a[2] + sb*b[2]}
+}
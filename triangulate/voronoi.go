@@ -0,0 +1,146 @@
+package triangulate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// VoronoiCell - ячейка диаграммы Вороного: область, ближайшая к порождающей точке
+type VoronoiCell struct {
+	// Index - индекс порождающей точки во входном наборе
+	Index int
+	// Site - порождающая точка
+	Site types.Point
+	// Polygons - ячейка, обрезанная по границе. При невыпуклой границе или границе
+	// с дырами ячейка может состоять из нескольких полигонов
+	Polygons types.MultiPolygon
+}
+
+// Voronoi строит диаграмму Вороного набора точек на плоскости локальной системы координат
+// и обрезает ячейки по полигону boundary, который может быть невыпуклым и содержать дыры.
+// Для совпадающих точек ячейка строится один раз, для первой из них. Ячейки, не пересекающие
+// границу, не возвращаются; остальные возвращаются в порядке входных точек
+func Voronoi(points []types.Point, boundary types.Polygon) ([]VoronoiCell, error) {
+	if len(boundary) == 0 || len(boundary[0]) < 4 {
+		return nil, fmt.Errorf("voronoi boundary with %d rings: %w", len(boundary), ErrDegenerate)
+	}
+	unique := uniquePoints(points)
+	if len(unique) == 0 {
+		return nil, fmt.Errorf("voronoi of %d points: %w", len(points), ErrDegenerate)
+	}
+
+	frame := newLocalFrame(points)
+	pts := make([][2]float64, len(unique))
+	for k, i := range unique {
+		pts[k] = frame.forward(points[i])
+	}
+
+	// Соседи по Делоне; если все точки на одной прямой, ячейку ограничивают все остальные точки
+	neighbors := make([][]int, len(unique))
+	if triangles := delaunay(pts); len(triangles) > 0 {
+		neighbors = vertexNeighbors(len(unique), triangles)
+	} else {
+		for k := range neighbors {
+			for j := range unique {
+				if j != k {
+					neighbors[k] = append(neighbors[k], j)
+				}
+			}
+		}
+	}
+
+	// Начальная ячейка - прямоугольник, содержащий границу и все точки
+	box := calc.CalculatePlanarBoundingBox(boundary)
+	corners := [][2]float64{
+		frame.forward(types.NewPoint(box.MinLon, box.MinLat)),
+		frame.forward(types.NewPoint(box.MaxLon, box.MaxLat)),
+	}
+	for _, p := range pts {
+		corners = append(corners, p)
+	}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, c := range corners {
+		minX, maxX = math.Min(minX, c[0]), math.Max(maxX, c[0])
+		minY, maxY = math.Min(minY, c[1]), math.Max(maxY, c[1])
+	}
+	margin := math.Max(maxX-minX, maxY-minY) + 1
+	frameBox := [][2]float64{
+		{minX - margin, minY - margin}, {maxX + margin, minY - margin},
+		{maxX + margin, maxY + margin}, {minX - margin, maxY + margin},
+	}
+
+	var cells []VoronoiCell
+	for k, i := range unique {
+		cell := frameBox
+		for _, j := range neighbors[k] {
+			cell = clipHalfPlane(cell, pts[k], pts[j])
+			if len(cell) < 3 {
+				break
+			}
+		}
+		if len(cell) < 3 {
+			continue
+		}
+
+		window := make(types.LineString, 0, len(cell)+1)
+		for _, xy := range cell {
+			window = append(window, frame.inverse(xy))
+		}
+		window = append(window, window[0])
+
+		polygons := calc.ClipPolygon(boundary, window)
+		if len(polygons) > 0 {
+			cells = append(cells, VoronoiCell{Index: i, Site: points[i], Polygons: polygons})
+		}
+	}
+	return cells, nil
+}
+
+// clipHalfPlane обрезает выпуклый многоугольник полуплоскостью точек, которые ближе к site,
+// чем к other (алгоритм Сазерленда-Ходжмана для одной прямой)
+func clipHalfPlane(polygon [][2]float64, site, other [2]float64) [][2]float64 {
+	// Точка q ближе к site, если (q - m)·(other - site) <= 0, где m - середина отрезка
+	nx, ny := other[0]-site[0], other[1]-site[1]
+	mx, my := (site[0]+other[0])/2, (site[1]+other[1])/2
+	side := func(q [2]float64) float64 {
+		return (q[0]-mx)*nx + (q[1]-my)*ny
+	}
+
+	result := make([][2]float64, 0, len(polygon)+1)
+	for k := range polygon {
+		a, b := polygon[k], polygon[(k+1)%len(polygon)]
+		sa, sb := side(a), side(b)
+		if sa <= 0 {
+			result = append(result, a)
+		}
+		if (sa < 0 && sb > 0) || (sa > 0 && sb < 0) {
+			t := sa / (sa - sb)
+			result = append(result, [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+		}
+	}
+	return result
+}
+
+// Features возвращает ячейки в виде набора объектов. Свойства объекта: site_index - индекс
+// порождающей точки, site_lon и site_lat - ее координаты. Ячейка из нескольких частей
+// записывается как MultiPolygon
+func Features(cells []VoronoiCell) *types.FeatureCollection {
+	fc := types.NewFeatureCollection()
+	fc.Features = make([]types.Feature, 0, len(cells))
+	for _, cell := range cells {
+		geometry := types.NewMultiPolygonGeometry(cell.Polygons)
+		if len(cell.Polygons) == 1 {
+			geometry = types.NewPolygonGeometry(cell.Polygons[0])
+		}
+
+		props := types.NewProperties()
+		props["site_index"] = cell.Index
+		props["site_lon"] = cell.Site.GetLongitude()
+		props["site_lat"] = cell.Site.GetLatitude()
+		fc.Features = append(fc.Features, types.NewFeature(geometry, props))
+	}
+	return fc
+}
@@ -0,0 +1,157 @@
+package triangulate
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/Fliiiiii/go-geo/calc"
+	"github.com/Fliiiiii/go-geo/types"
+)
+
+// box возвращает прямоугольник с обходом против часовой стрелки
+func box(minLon, minLat, maxLon, maxLat float64) types.LineString {
+	return types.NewLineString(
+		types.NewPoint(minLon, minLat), types.NewPoint(maxLon, minLat), types.NewPoint(maxLon, maxLat),
+		types.NewPoint(minLon, maxLat), types.NewPoint(minLon, minLat),
+	)
+}
+
+// cellContains проверяет, что точка лежит в одном из полигонов ячейки
+func cellContains(cell VoronoiCell, p types.Point) bool {
+	for _, polygon := range cell.Polygons {
+		if calc.PointInPolygon(polygon, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestVoronoi(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	points := randomPoints(r, 200, 37, 55, 38, 56)
+	boundary := types.NewPolygon(box(37, 55, 38, 56), box(37.4, 55.4, 37.6, 55.6).Reverse())
+
+	cells, err := Voronoi(points, boundary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ячейки разбивают границу: сумма площадей равна площади границы
+	total := 0.0
+	for _, cell := range cells {
+		total += polygonsArea(cell.Polygons)
+	}
+	if want := polygonsArea(types.MultiPolygon{boundary}); math.Abs(total-want) > 1e-9 {
+		t.Errorf("cells cover %g deg², boundary %g deg²", total, want)
+	}
+
+	frame := newLocalFrame(points)
+	dist := func(a, b types.Point) float64 {
+		pa, pb := frame.forward(a), frame.forward(b)
+		return math.Hypot(pa[0]-pb[0], pa[1]-pb[1])
+	}
+	byIndex := make(map[int]VoronoiCell, len(cells))
+	for _, cell := range cells {
+		byIndex[cell.Index] = cell
+	}
+
+	// Точка границы лежит в ячейке ближайшей порождающей точки
+	for i := 0; i < 1000; i++ {
+		p := randomPoints(r, 1, 37, 55, 38, 56)[0]
+		if !calc.PointInPolygon(boundary, p) {
+			continue
+		}
+		order := make([]int, len(points))
+		for k := range order {
+			order[k] = k
+		}
+		sort.Slice(order, func(a, b int) bool { return dist(p, points[order[a]]) < dist(p, points[order[b]]) })
+		if dist(p, points[order[1]])-dist(p, points[order[0]]) < 1e-9 {
+			continue
+		}
+		if !cellContains(byIndex[order[0]], p) {
+			t.Fatalf("point %v is not in the cell of its nearest site %d", p, order[0])
+		}
+	}
+
+	fc := Features(cells)
+	if len(fc.Features) != len(cells) || fc.Features[0].Properties["site_index"] != cells[0].Index {
+		t.Errorf("Features returned %d features for %d cells", len(fc.Features), len(cells))
+	}
+}
+
+func TestVoronoiCollinear(t *testing.T) {
+	points := []types.Point{types.NewPoint(0, 0), types.NewPoint(1, 1), types.NewPoint(2, 2), types.NewPoint(1, 1)}
+	cells, err := Voronoi(points, types.NewPolygon(box(-1, -1, 3, 3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 3 {
+		t.Fatalf("%d cells, want 3", len(cells))
+	}
+	total := 0.0
+	for _, cell := range cells {
+		total += polygonsArea(cell.Polygons)
+	}
+	if math.Abs(total-16) > 1e-9 {
+		t.Errorf("cells cover %g deg², want 16", total)
+	}
+
+	if _, err := Voronoi(points, nil); !errors.Is(err, ErrDegenerate) {
+		t.Errorf("empty boundary error = %v, want ErrDegenerate", err)
+	}
+}
+
+func TestSphericalVoronoiCoversWorld(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	points := make([]types.Point, 100)
+	for i := range points {
+		points[i] = types.NewPoint(-180+r.Float64()*360, math.Asin(2*r.Float64()-1)*180/math.Pi)
+	}
+	world := types.NewPolygon(box(-180, -90, 180, 90))
+
+	cells, err := SphericalVoronoi(points, world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != len(points) {
+		t.Fatalf("%d cells for %d points", len(cells), len(points))
+	}
+
+	total := 0.0
+	for _, cell := range cells {
+		total += polygonsArea(cell.Polygons)
+	}
+	if math.Abs(total-360*180) > 1e-6 {
+		t.Errorf("cells cover %g deg², want %d", total, 360*180)
+	}
+
+	byIndex := make(map[int]VoronoiCell, len(cells))
+	for _, cell := range cells {
+		byIndex[cell.Index] = cell
+	}
+
+	// Ребра ячеек аппроксимированы отрезками, поэтому точки у границы ячеек пропускаются
+	for i := 0; i < 1000; i++ {
+		p := types.NewPoint(-180+r.Float64()*360, -89+r.Float64()*178)
+		best, second := -1, -1
+		dist := make([]float64, len(points))
+		for k, site := range points {
+			dist[k] = calc.CalculateDistance(p, site)
+			if best < 0 || dist[k] < dist[best] {
+				best, second = k, best
+			} else if second < 0 || dist[k] < dist[second] {
+				second = k
+			}
+		}
+		if dist[second]-dist[best] < 50 {
+			continue
+		}
+		if !cellContains(byIndex[best], p) {
+			t.Fatalf("point %v is not in the cell of its nearest site %d", p, best)
+		}
+	}
+}
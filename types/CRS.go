@@ -0,0 +1,126 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Типы устаревшего члена "crs" формата GeoJSON 2008
+const (
+	CRSTypeName = "name"
+	CRSTypeEPSG = "EPSG"
+)
+
+// CRS представляет устаревший член "crs" формата GeoJSON 2008.
+// RFC 7946 его не поддерживает, но он встречается в данных старых источников
+type CRS struct {
+	Type       string         `json:"type" bson:"type"`
+	Properties map[string]any `json:"properties" bson:"properties"`
+}
+
+// NewNamedCRS создает член "crs" с именованной системой координат
+func NewNamedCRS(name string) *CRS {
+	return &CRS{
+		Type:       CRSTypeName,
+		Properties: map[string]any{"name": name},
+	}
+}
+
+// NewEPSGCRS создает именованный член "crs" для кода EPSG в виде URN OGC
+func NewEPSGCRS(code int) *CRS {
+	return NewNamedCRS(fmt.Sprintf("urn:ogc:def:crs:EPSG::%d", code))
+}
+
+// EPSG возвращает код EPSG системы координат. Поддерживаются имена вида "EPSG:4326",
+// "urn:ogc:def:crs:EPSG::4326", "urn:ogc:def:crs:OGC:1.3:CRS84" и тип "EPSG" со свойством "code"
+func (c *CRS) EPSG() (int, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	switch c.Type {
+	case CRSTypeEPSG:
+		switch code := c.Properties["code"].(type) {
+		case int:
+			return code, true
+		case int32:
+			return int(code), true
+		case int64:
+			return int(code), true
+		case float64:
+			return int(code), code == float64(int(code))
+		case string:
+			n, err := strconv.Atoi(code)
+			return n, err == nil
+		}
+	case CRSTypeName:
+		name, ok := c.Properties["name"].(string)
+		if !ok {
+			return 0, false
+		}
+		return parseCRSName(name)
+	}
+
+	return 0, false
+}
+
+// parseCRSName извлекает код EPSG из имени системы координат
+func parseCRSName(name string) (int, bool) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+
+	if strings.HasSuffix(upper, "CRS84") {
+		return 4326, true
+	}
+
+	idx := strings.LastIndex(upper, "EPSG")
+	if idx < 0 {
+		return 0, false
+	}
+
+	// Код следует за последним двоеточием после "EPSG", версия в URN может быть пропущена
+	rest := upper[idx+len("EPSG"):]
+	rest = rest[strings.LastIndex(rest, ":")+1:]
+	code, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// decodeCRS преобразует декодированное значение JSON в CRS.
+// Член "crs" необязателен и в старых данных встречается в произвольном виде (например,
+// строкой "EPSG:4326"), поэтому некорректное значение не считается ошибкой: возвращается nil
+func decodeCRS(data any) *CRS {
+	m, ok := data.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	typeStr, ok := m["type"].(string)
+	if !ok {
+		return nil
+	}
+
+	crs := &CRS{Type: typeStr}
+	if props, ok := m["properties"].(map[string]any); ok {
+		crs.Properties = props
+	}
+	return crs
+}
+
+// decodeBSONCRS преобразует значение BSON в CRS. Как и decodeCRS, возвращает nil,
+// если член отсутствует или не является объектом с типом
+func decodeBSONCRS(v bson.RawValue) *CRS {
+	if v.Type != bson.TypeEmbeddedDocument {
+		return nil
+	}
+
+	var crs CRS
+	if err := v.Unmarshal(&crs); err != nil || crs.Type == "" {
+		return nil
+	}
+	return &crs
+}
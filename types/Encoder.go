@@ -0,0 +1,73 @@
+package types
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Encoder записывает объекты GeoJSON в поток
+type Encoder struct {
+	enc    *jsoniter.Encoder
+	rewind bool
+}
+
+// NewEncoder создает новый Encoder, записывающий в w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{enc: json.NewEncoder(w)}
+}
+
+// SetRewind включает приведение колец к правилу правой руки RFC 7946 перед записью.
+// Исходные объекты при этом не изменяются
+func (e *Encoder) SetRewind(rewind bool) {
+	e.rewind = rewind
+}
+
+// SetIndent задает форматирование вывода так же, как json.Encoder.SetIndent
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.enc.SetIndent(prefix, indent)
+}
+
+// Encode записывает значение v в формате JSON
+func (e *Encoder) Encode(v any) error {
+	if e.rewind {
+		v = rewindValue(v)
+	}
+	return e.enc.Encode(v)
+}
+
+// rewindValue приводит к правилу правой руки значения известных типов
+func rewindValue(v any) any {
+	switch val := v.(type) {
+	case Polygon:
+		return val.Rewind()
+	case MultiPolygon:
+		return val.Rewind()
+	case Geometry:
+		return val.Rewind()
+	case *Geometry:
+		if val == nil {
+			return val
+		}
+		rewound := val.Rewind()
+		return &rewound
+	case Feature:
+		return val.Rewind()
+	case *Feature:
+		if val == nil {
+			return val
+		}
+		rewound := val.Rewind()
+		return &rewound
+	case FeatureCollection:
+		return val.Rewind()
+	case *FeatureCollection:
+		return val.Rewind()
+	case GeometryCollection:
+		return val.Rewind()
+	case *GeometryCollection:
+		return val.Rewind()
+	default:
+		return v
+	}
+}
@@ -1,10 +1,53 @@
 package types
 
+import "go.mongodb.org/mongo-driver/bson"
+
 type FeatureCollection struct {
 	Type     string    `json:"type"`
 	Features []Feature `json:"features"`
+	// CRS - необязательный устаревший член "crs", записывается только если задан.
+	// При чтении некорректный член игнорируется и CRS остается nil
+	CRS *CRS `json:"crs,omitempty" bson:"crs,omitempty"`
 }
 
 func NewFeatureCollection(features ...Feature) *FeatureCollection {
 	return &FeatureCollection{Type: "FeatureCollection", Features: features}
 }
+
+// UnmarshalJSON реализует интерфейс json.Unmarshaler для FeatureCollection
+func (fc *FeatureCollection) UnmarshalJSON(b []byte) error {
+	if len(b) == 0 || string(b) == "null" {
+		return nil
+	}
+
+	var raw struct {
+		Type     string    `json:"type"`
+		Features []Feature `json:"features"`
+		CRS      any       `json:"crs"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	fc.Type = raw.Type
+	fc.Features = raw.Features
+	fc.CRS = decodeCRS(raw.CRS)
+	return nil
+}
+
+// UnmarshalBSON реализует интерфейс bson.Unmarshaler для FeatureCollection
+func (fc *FeatureCollection) UnmarshalBSON(b []byte) error {
+	var raw struct {
+		Type     string        `bson:"type"`
+		Features []Feature     `bson:"features"`
+		CRS      bson.RawValue `bson:"crs"`
+	}
+	if err := bson.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	fc.Type = raw.Type
+	fc.Features = raw.Features
+	fc.CRS = decodeBSONCRS(raw.CRS)
+	return nil
+}
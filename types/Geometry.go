@@ -21,6 +21,8 @@ type Coordinates interface {
 type Geometry struct {
 	Type        GeometryType `json:"type" bson:"type"`
 	Coordinates Coordinates  `json:"coordinates" bson:"coordinates"`
+	// CRS - необязательный устаревший член "crs", записывается только если задан
+	CRS *CRS `json:"crs,omitempty" bson:"crs,omitempty"`
 }
 
 // NewPointGeometry создает геометрию типа Point
@@ -61,6 +63,7 @@ func (g *Geometry) UnmarshalBSON(b []byte) error {
 		// Сбрасываем все поля при получении пустого значения
 		g.Type = ""
 		g.Coordinates = nil
+		g.CRS = nil
 		return nil
 	}
 
@@ -80,8 +83,14 @@ func (g *Geometry) UnmarshalBSON(b []byte) error {
 		return fmt.Errorf("missing required field 'coordinates' for type %s", g.Type)
 	}
 
-	// Сбрасываем поле Coordinates перед установкой нового
+	// Сбрасываем поля Coordinates и CRS перед установкой новых
 	g.Coordinates = nil
+	g.CRS = nil
+
+	// Необязательный член "crs"; некорректное значение игнорируется
+	if crsElem, err := raw.LookupErr("crs"); err == nil {
+		g.CRS = decodeBSONCRS(crsElem)
+	}
 
 	switch g.Type {
 	case GeometryPoint:
@@ -120,12 +129,16 @@ func (g *Geometry) MarshalBSON() ([]byte, error) {
 		return bson.Marshal(nil)
 	}
 
-	geoInterface := bson.D{{"type", g.Type}}
+	geoInterface := bson.D{{Key: "type", Value: g.Type}}
 
 	if g.Coordinates == nil {
 		return nil, fmt.Errorf("coordinates data is nil for geometry type %s", g.Type)
 	}
-	geoInterface = append(geoInterface, bson.E{"coordinates", g.Coordinates})
+	geoInterface = append(geoInterface, bson.E{Key: "coordinates", Value: g.Coordinates})
+
+	if g.CRS != nil {
+		geoInterface = append(geoInterface, bson.E{Key: "crs", Value: g.CRS})
+	}
 
 	return bson.Marshal(geoInterface)
 }
@@ -143,6 +156,9 @@ func (g *Geometry) MarshalJSON() ([]byte, error) {
 		"type":        g.Type,
 		"coordinates": g.Coordinates,
 	}
+	if g.CRS != nil {
+		geoInterface["crs"] = g.CRS
+	}
 
 	return json.Marshal(geoInterface)
 }
@@ -153,6 +169,7 @@ func (g *Geometry) UnmarshalJSON(b []byte) error {
 		// Сбрасываем все поля при получении пустого значения
 		g.Type = ""
 		g.Coordinates = nil
+		g.CRS = nil
 		return nil
 	}
 
@@ -183,6 +200,10 @@ func (g *Geometry) UnmarshalJSON(b []byte) error {
 	// Сбрасываем все поля перед установкой новых
 	g.Type = GeometryType(typeStr)
 	g.Coordinates = nil
+	g.CRS = nil
+
+	// Необязательный член "crs"; некорректное значение игнорируется
+	g.CRS = decodeCRS(geoInterface["crs"])
 
 	switch g.Type {
 	case GeometryPoint:
@@ -0,0 +1,168 @@
+package types
+
+// Orientation определяет направление обхода кольца
+type Orientation int
+
+const (
+	// Degenerate - кольцо вырождено (меньше трех точек или нулевая площадь)
+	Degenerate Orientation = iota
+	// Clockwise - обход по часовой стрелке
+	Clockwise
+	// CounterClockwise - обход против часовой стрелки
+	CounterClockwise
+)
+
+// String возвращает название направления обхода
+func (o Orientation) String() string {
+	switch o {
+	case Clockwise:
+		return "clockwise"
+	case CounterClockwise:
+		return "counterclockwise"
+	default:
+		return "degenerate"
+	}
+}
+
+// SignedArea вычисляет ориентированную площадь кольца на плоскости долгота/широта
+// (в квадратных градусах) по формуле шнурования. Положительное значение означает
+// обход против часовой стрелки. Переходы через 180-й меридиан учитываются
+func (ls LineString) SignedArea() float64 {
+	n := len(ls)
+	if n < 3 {
+		return 0
+	}
+
+	// Разворачиваем долготы, чтобы кольцо, пересекающее 180-й меридиан, оставалось непрерывным
+	lons := make([]float64, n)
+	lons[0] = ls[0].GetLongitude()
+	for i := 1; i < n; i++ {
+		lon := ls[i].GetLongitude()
+		for lon-lons[i-1] > 180 {
+			lon -= 360
+		}
+		for lon-lons[i-1] < -180 {
+			lon += 360
+		}
+		lons[i] = lon
+	}
+
+	area := 0.0
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += lons[i]*ls[j].GetLatitude() - lons[j]*ls[i].GetLatitude()
+	}
+
+	return area / 2
+}
+
+// Orientation определяет направление обхода кольца
+func (ls LineString) Orientation() Orientation {
+	area := ls.SignedArea()
+	switch {
+	case area > 0:
+		return CounterClockwise
+	case area < 0:
+		return Clockwise
+	default:
+		return Degenerate
+	}
+}
+
+// IsClockwise проверяет, что кольцо обходится по часовой стрелке
+func (ls LineString) IsClockwise() bool {
+	return ls.Orientation() == Clockwise
+}
+
+// Reverse возвращает копию линии с обратным порядком точек
+func (ls LineString) Reverse() LineString {
+	result := make(LineString, len(ls))
+	for i, p := range ls {
+		result[len(ls)-1-i] = p
+	}
+	return result
+}
+
+// Rewind возвращает копию полигона, удовлетворяющую правилу правой руки RFC 7946:
+// внешний контур обходится против часовой стрелки, дыры - по часовой.
+// Вырожденные кольца остаются без изменений
+func (p Polygon) Rewind() Polygon {
+	if p == nil {
+		return nil
+	}
+
+	result := make(Polygon, len(p))
+	for i, ring := range p {
+		want := Clockwise
+		if i == 0 {
+			want = CounterClockwise
+		}
+
+		orientation := ring.Orientation()
+		if orientation != Degenerate && orientation != want {
+			ring = ring.Reverse()
+		}
+		result[i] = ring
+	}
+
+	return result
+}
+
+// Rewind возвращает копию MultiPolygon, в которой каждый полигон приведен к правилу правой руки
+func (mp MultiPolygon) Rewind() MultiPolygon {
+	if mp == nil {
+		return nil
+	}
+
+	result := make(MultiPolygon, len(mp))
+	for i, polygon := range mp {
+		result[i] = polygon.Rewind()
+	}
+	return result
+}
+
+// Rewind возвращает копию геометрии с кольцами, приведенными к правилу правой руки.
+// Геометрии без колец возвращаются без изменений
+func (g Geometry) Rewind() Geometry {
+	switch coords := g.Coordinates.(type) {
+	case Polygon:
+		g.Coordinates = coords.Rewind()
+	case MultiPolygon:
+		g.Coordinates = coords.Rewind()
+	}
+	return g
+}
+
+// Rewind возвращает копию коллекции геометрий, приведенных к правилу правой руки
+func (gc *GeometryCollection) Rewind() *GeometryCollection {
+	if gc == nil {
+		return nil
+	}
+
+	geometries := make([]Geometry, len(gc.Geometries))
+	for i, geometry := range gc.Geometries {
+		geometries[i] = geometry.Rewind()
+	}
+	return &GeometryCollection{Geometries: geometries}
+}
+
+// Rewind возвращает копию объекта с геометрией, приведенной к правилу правой руки.
+// Свойства объекта не копируются
+func (f Feature) Rewind() Feature {
+	f.Geometry = f.Geometry.Rewind()
+	return f
+}
+
+// Rewind возвращает копию коллекции объектов, приведенных к правилу правой руки
+func (fc *FeatureCollection) Rewind() *FeatureCollection {
+	if fc == nil {
+		return nil
+	}
+
+	result := *fc
+	result.Features = make([]Feature, len(fc.Features))
+	for i, feature := range fc.Features {
+		result.Features[i] = feature.Rewind()
+	}
+	return &result
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// legacyCRS - формы члена "crs", встречающиеся в старых данных
+var legacyCRS = []struct {
+	name string
+	json string
+	bson any
+	code int
+}{
+	{name: "absent", json: ``},
+	{name: "null", json: `,"crs":null`, bson: nil},
+	{name: "string", json: `,"crs":"EPSG:4326"`, bson: "EPSG:4326"},
+	{name: "number", json: `,"crs":4326`, bson: 4326},
+	{name: "array", json: `,"crs":["EPSG",4326]`, bson: bson.A{"EPSG", 4326}},
+	{name: "untyped object", json: `,"crs":{"properties":{"name":"EPSG:4326"}}`,
+		bson: bson.M{"properties": bson.M{"name": "EPSG:4326"}}},
+	{name: "named", json: `,"crs":{"type":"name","properties":{"name":"urn:ogc:def:crs:EPSG::3857"}}`,
+		bson: bson.M{"type": "name", "properties": bson.M{"name": "urn:ogc:def:crs:EPSG::3857"}}, code: 3857},
+	{name: "epsg", json: `,"crs":{"type":"EPSG","properties":{"code":4284}}`,
+		bson: bson.M{"type": "EPSG", "properties": bson.M{"code": 4284}}, code: 4284},
+}
+
+// checkCRS проверяет, что член "crs" распознан только в корректной форме
+func checkCRS(t *testing.T, name string, crs *CRS, want int) {
+	t.Helper()
+	code, ok := crs.EPSG()
+	switch {
+	case want == 0 && crs != nil:
+		t.Errorf("%s: crs = %+v, want nil", name, crs)
+	case want != 0 && (!ok || code != want):
+		t.Errorf("%s: EPSG = %d, %v, want %d", name, code, ok, want)
+	}
+}
+
+func TestGeometryLegacyCRSJSON(t *testing.T) {
+	for _, tc := range legacyCRS {
+		var g Geometry
+		data := `{"type":"Point","coordinates":[1,2]` + tc.json + `}`
+		if err := json.Unmarshal([]byte(data), &g); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if g.Type != GeometryPoint || g.Coordinates.(Point).GetLatitude() != 2 {
+			t.Errorf("%s: geometry = %+v", tc.name, g)
+		}
+		checkCRS(t, tc.name, g.CRS, tc.code)
+	}
+}
+
+func TestFeatureCollectionLegacyCRSJSON(t *testing.T) {
+	for _, tc := range legacyCRS {
+		var fc FeatureCollection
+		data := `{"type":"FeatureCollection","features":[{"type":"Feature",` +
+			`"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"id":1}}]` + tc.json + `}`
+		if err := json.Unmarshal([]byte(data), &fc); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if fc.Type != "FeatureCollection" || len(fc.Features) != 1 || fc.Features[0].Properties["id"] != float64(1) {
+			t.Errorf("%s: collection = %+v", tc.name, fc)
+		}
+		checkCRS(t, tc.name, fc.CRS, tc.code)
+	}
+}
+
+func TestLegacyCRSBSON(t *testing.T) {
+	for _, tc := range legacyCRS {
+		geometry := bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0, 2.0}}}
+		if tc.json != "" {
+			geometry = append(geometry, bson.E{Key: "crs", Value: tc.bson})
+		}
+		data, err := bson.Marshal(geometry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g Geometry
+		if err := bson.Unmarshal(data, &g); err != nil {
+			t.Errorf("%s: geometry: %v", tc.name, err)
+			continue
+		}
+		checkCRS(t, tc.name+" geometry", g.CRS, tc.code)
+
+		collection := bson.D{{Key: "type", Value: "FeatureCollection"}, {Key: "features", Value: bson.A{
+			bson.D{{Key: "type", Value: "Feature"}, {Key: "geometry", Value: geometry[:2]}},
+		}}}
+		if tc.json != "" {
+			collection = append(collection, bson.E{Key: "crs", Value: tc.bson})
+		}
+		if data, err = bson.Marshal(collection); err != nil {
+			t.Fatal(err)
+		}
+		var fc FeatureCollection
+		if err := bson.Unmarshal(data, &fc); err != nil {
+			t.Errorf("%s: collection: %v", tc.name, err)
+			continue
+		}
+		if len(fc.Features) != 1 || fc.Features[0].Geometry.Type != GeometryPoint {
+			t.Errorf("%s: collection = %+v", tc.name, fc)
+		}
+		checkCRS(t, tc.name+" collection", fc.CRS, tc.code)
+	}
+}
+
+func TestCRSRoundTrip(t *testing.T) {
+	fc := NewFeatureCollection(NewFeature(NewPointGeometry(NewPoint(1, 2)), NewProperties()))
+	fc.CRS = NewEPSGCRS(3395)
+
+	data, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded FeatureCollection
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	checkCRS(t, "json", decoded.CRS, 3395)
+
+	if data, err = bson.Marshal(fc); err != nil {
+		t.Fatal(err)
+	}
+	decoded = FeatureCollection{}
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	checkCRS(t, "bson", decoded.CRS, 3395)
+}
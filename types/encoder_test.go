@@ -0,0 +1,66 @@
+package types
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// encode записывает значение кодировщиком с приведением колец или без него и разбирает
+// результат обратно, так как порядок членов геометрии в выводе не определен
+func encode(t *testing.T, v any, rewind bool) any {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetRewind(rewind)
+	if err := enc.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	var decoded any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestEncoderRewind(t *testing.T) {
+	polygon := NewPolygon(cwSquare, ccwHole)
+	geometry := NewPolygonGeometry(polygon)
+	feature := NewFeature(geometry, Properties{"name": "square"})
+	fc := NewFeatureCollection(feature)
+	gc := NewGeometryCollection(geometry)
+
+	tests := []struct {
+		name      string
+		value     any
+		rewound   any
+		unchanged bool
+	}{
+		{name: "Polygon", value: polygon, rewound: polygon.Rewind()},
+		{name: "MultiPolygon", value: NewMultiPolygon(polygon), rewound: NewMultiPolygon(polygon).Rewind()},
+		{name: "Geometry", value: geometry, rewound: geometry.Rewind()},
+		{name: "*Geometry", value: &geometry, rewound: geometry.Rewind()},
+		{name: "Feature", value: feature, rewound: feature.Rewind()},
+		{name: "*Feature", value: &feature, rewound: feature.Rewind()},
+		{name: "FeatureCollection", value: *fc, rewound: fc.Rewind()},
+		{name: "*FeatureCollection", value: fc, rewound: fc.Rewind()},
+		{name: "GeometryCollection", value: *gc, rewound: gc.Rewind()},
+		{name: "*GeometryCollection", value: gc, rewound: gc.Rewind()},
+		{name: "LineString", value: cwSquare, rewound: cwSquare, unchanged: true},
+		{name: "nil *Geometry", value: (*Geometry)(nil), rewound: nil, unchanged: true},
+		{name: "nil *Feature", value: (*Feature)(nil), rewound: nil, unchanged: true},
+	}
+	for _, tt := range tests {
+		got := encode(t, tt.value, true)
+		if want := encode(t, tt.rewound, false); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: rewound output %v, want %v", tt.name, got, want)
+		}
+		if plain := encode(t, tt.value, false); reflect.DeepEqual(plain, got) != tt.unchanged {
+			t.Errorf("%s: output without rewind is %v", tt.name, plain)
+		}
+	}
+
+	// Кодировщик не изменяет исходные объекты
+	checkRing(t, "source polygon", polygon[0], Clockwise)
+	checkRing(t, "source feature collection", fc.Features[0].Geometry.Coordinates.(Polygon)[0], Clockwise)
+}
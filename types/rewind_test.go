@@ -0,0 +1,152 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+// Квадраты 0..4 и дыра 1..2 с обходом против и по часовой стрелке
+var (
+	ccwSquare = NewLineString(NewPoint(0, 0), NewPoint(4, 0), NewPoint(4, 4), NewPoint(0, 4), NewPoint(0, 0))
+	cwSquare  = ccwSquare.Reverse()
+	ccwHole   = NewLineString(NewPoint(1, 1), NewPoint(2, 1), NewPoint(2, 2), NewPoint(1, 2), NewPoint(1, 1))
+	cwHole    = ccwHole.Reverse()
+)
+
+// checkRing проверяет направление обхода кольца
+func checkRing(t *testing.T, name string, ring LineString, want Orientation) {
+	t.Helper()
+	if got := ring.Orientation(); got != want {
+		t.Errorf("%s is %v, want %v", name, got, want)
+	}
+}
+
+// checkWound проверяет, что полигон удовлетворяет правилу правой руки
+func checkWound(t *testing.T, name string, p Polygon) {
+	t.Helper()
+	for i, ring := range p {
+		want := Clockwise
+		if i == 0 {
+			want = CounterClockwise
+		}
+		checkRing(t, name, ring, want)
+	}
+}
+
+func TestOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		ring LineString
+		area float64
+		want Orientation
+	}{
+		{"counterclockwise", ccwSquare, 16, CounterClockwise},
+		{"clockwise", cwSquare, -16, Clockwise},
+		{"unclosed", NewLineString(NewPoint(0, 0), NewPoint(4, 0), NewPoint(4, 4)), 8, CounterClockwise},
+		{"empty", nil, 0, Degenerate},
+		{"two points", NewLineString(NewPoint(0, 0), NewPoint(1, 1)), 0, Degenerate},
+		{"collinear", NewLineString(NewPoint(0, 0), NewPoint(1, 1), NewPoint(2, 2), NewPoint(0, 0)), 0, Degenerate},
+		// Квадрат 2x2 через 180-й меридиан: без разворота долгот площадь была бы около -716
+		{"antimeridian", NewLineString(NewPoint(179, 0), NewPoint(-179, 0), NewPoint(-179, 2), NewPoint(179, 2), NewPoint(179, 0)), 4, CounterClockwise},
+		{"antimeridian clockwise", NewLineString(NewPoint(-179, 0), NewPoint(179, 0), NewPoint(179, 2), NewPoint(-179, 2), NewPoint(-179, 0)), -4, Clockwise},
+	}
+	for _, tt := range tests {
+		if got := tt.ring.SignedArea(); math.Abs(got-tt.area) > 1e-9 {
+			t.Errorf("%s: SignedArea = %g, want %g", tt.name, got, tt.area)
+		}
+		checkRing(t, tt.name, tt.ring, tt.want)
+		if got := tt.ring.IsClockwise(); got != (tt.want == Clockwise) {
+			t.Errorf("%s: IsClockwise = %v", tt.name, got)
+		}
+	}
+
+	for o, want := range map[Orientation]string{Clockwise: "clockwise", CounterClockwise: "counterclockwise", Degenerate: "degenerate"} {
+		if o.String() != want {
+			t.Errorf("%d.String() = %q, want %q", o, o.String(), want)
+		}
+	}
+}
+
+func TestPolygonRewind(t *testing.T) {
+	polygon := NewPolygon(cwSquare, ccwHole)
+	rewound := polygon.Rewind()
+	checkWound(t, "rewound polygon", rewound)
+
+	// Исходный полигон не изменяется
+	checkRing(t, "source outer ring", polygon[0], Clockwise)
+	checkRing(t, "source hole", polygon[1], CounterClockwise)
+
+	// Правильно ориентированные и вырожденные кольца сохраняются
+	degenerate := NewLineString(NewPoint(0, 0), NewPoint(1, 1), NewPoint(0, 0))
+	kept := NewPolygon(ccwSquare, cwHole, degenerate).Rewind()
+	if &kept[0][0] != &ccwSquare[0] || &kept[1][0] != &cwHole[0] || &kept[2][0] != &degenerate[0] {
+		t.Error("rings with the right orientation are copied")
+	}
+
+	if Polygon(nil).Rewind() != nil || MultiPolygon(nil).Rewind() != nil {
+		t.Error("nil is rewound to non-nil")
+	}
+
+	mp := NewMultiPolygon(NewPolygon(cwSquare, ccwHole), NewPolygon(ccwSquare))
+	for i, p := range mp.Rewind() {
+		checkWound(t, "multipolygon part", p)
+		if len(p) != len(mp[i]) {
+			t.Errorf("part %d has %d rings, want %d", i, len(p), len(mp[i]))
+		}
+	}
+	checkRing(t, "source multipolygon", mp[0][0], Clockwise)
+}
+
+func TestGeometryRewind(t *testing.T) {
+	polygon := NewPolygonGeometry(NewPolygon(cwSquare, ccwHole))
+	polygon.CRS = NewEPSGCRS(4326)
+	rewound := polygon.Rewind()
+	checkWound(t, "polygon geometry", rewound.Coordinates.(Polygon))
+	if rewound.Type != GeometryPolygon || rewound.CRS != polygon.CRS {
+		t.Errorf("geometry members are not kept: %+v", rewound)
+	}
+	checkRing(t, "source geometry", polygon.Coordinates.(Polygon)[0], Clockwise)
+
+	multi := NewMultiPolygonGeometry(NewMultiPolygon(NewPolygon(cwSquare))).Rewind()
+	checkWound(t, "multipolygon geometry", multi.Coordinates.(MultiPolygon)[0])
+
+	// Геометрии без колец не меняются, в том числе линия с обходом по часовой стрелке
+	line := NewLineStringGeometry(cwSquare).Rewind()
+	checkRing(t, "line geometry", line.Coordinates.(LineString), Clockwise)
+	if point := NewPointGeometry(NewPoint(1, 2)).Rewind(); point.Coordinates.(Point).GetLatitude() != 2 {
+		t.Errorf("point geometry = %+v", point)
+	}
+
+	gc := NewGeometryCollection(NewPolygonGeometry(NewPolygon(cwSquare)), NewLineStringGeometry(cwSquare))
+	rewoundGC := gc.Rewind()
+	checkWound(t, "geometry collection polygon", rewoundGC.Geometries[0].Coordinates.(Polygon))
+	checkRing(t, "geometry collection line", rewoundGC.Geometries[1].Coordinates.(LineString), Clockwise)
+	checkRing(t, "source geometry collection", gc.Geometries[0].Coordinates.(Polygon)[0], Clockwise)
+	if (*GeometryCollection)(nil).Rewind() != nil {
+		t.Error("nil geometry collection is rewound to non-nil")
+	}
+}
+
+func TestFeatureRewind(t *testing.T) {
+	props := Properties{"name": "square"}
+	feature := NewFeature(NewPolygonGeometry(NewPolygon(cwSquare, ccwHole)), props)
+	rewound := feature.Rewind()
+	checkWound(t, "feature", rewound.Geometry.Coordinates.(Polygon))
+	if rewound.Type != "Feature" || rewound.Properties["name"] != "square" {
+		t.Errorf("feature members are not kept: %+v", rewound)
+	}
+	checkRing(t, "source feature", feature.Geometry.Coordinates.(Polygon)[0], Clockwise)
+
+	fc := NewFeatureCollection(feature, NewFeature(NewMultiPolygonGeometry(NewMultiPolygon(NewPolygon(cwSquare))), nil))
+	fc.CRS = NewEPSGCRS(4326)
+	rewoundFC := fc.Rewind()
+	checkWound(t, "feature collection polygon", rewoundFC.Features[0].Geometry.Coordinates.(Polygon))
+	checkWound(t, "feature collection multipolygon", rewoundFC.Features[1].Geometry.Coordinates.(MultiPolygon)[0])
+	if rewoundFC.Type != "FeatureCollection" || rewoundFC.CRS != fc.CRS || rewoundFC == fc {
+		t.Errorf("feature collection members are not kept: %+v", rewoundFC)
+	}
+	checkRing(t, "source feature collection", fc.Features[0].Geometry.Coordinates.(Polygon)[0], Clockwise)
+	if (*FeatureCollection)(nil).Rewind() != nil {
+		t.Error("nil feature collection is rewound to non-nil")
+	}
+}